@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE event_series (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    series_master_id VARCHAR(255) NOT NULL UNIQUE,
+    ical_uid VARCHAR(255) NOT NULL,
+    pattern_type VARCHAR(255) NOT NULL,
+    pattern_interval INT NOT NULL,
+    pattern_days_of_week TEXT[],
+    pattern_day_of_month INT,
+    pattern_month INT,
+    pattern_index VARCHAR(255),
+    pattern_first_day_of_week VARCHAR(255),
+    range_type VARCHAR(255) NOT NULL,
+    range_start_date DATE NOT NULL,
+    range_end_date DATE,
+    range_number_of_occurrences INT,
+    recurrence_time_zone VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- exception_type is either 'modified' (occurrence edited into an exception) or 'cancelled'
+CREATE TABLE event_series_exceptions (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    event_series_id UUID NOT NULL REFERENCES event_series(id) ON DELETE CASCADE,
+    event_id VARCHAR(255) NOT NULL,
+    exception_type VARCHAR(255) NOT NULL,
+    original_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (event_series_id, original_start)
+);
+
+ALTER TABLE events
+ADD COLUMN event_series_id UUID REFERENCES event_series(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE events
+DROP COLUMN event_series_id;
+
+DROP TABLE event_series_exceptions;
+DROP TABLE event_series;
+-- +goose StatementEnd
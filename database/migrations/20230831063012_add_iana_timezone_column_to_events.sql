@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- rows that existed before are filled by the server on startup (Handler.BackfillIanaTimezones)
+-- with the timezone package, which holds the single windows to iana table
+ALTER TABLE events
+ADD COLUMN iana_timezone VARCHAR(255);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE events SET
+    start_time = (start_time AT TIME ZONE iana_timezone) AT TIME ZONE 'UTC',
+    end_time = (end_time AT TIME ZONE iana_timezone) AT TIME ZONE 'UTC'
+WHERE iana_timezone NOT IN ('Etc/UTC', 'UTC');
+
+ALTER TABLE events
+DROP COLUMN iana_timezone;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- all day events are stored as a date range, end_date is exclusive
+ALTER TABLE events
+ADD COLUMN start_date DATE,
+ADD COLUMN end_date DATE;
+
+UPDATE events SET
+    start_date = (start_time AT TIME ZONE iana_timezone)::DATE,
+    end_date = (end_time AT TIME ZONE iana_timezone)::DATE
+WHERE is_all_day = TRUE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE events
+DROP COLUMN start_date,
+DROP COLUMN end_date;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+ADD COLUMN mailbox_timezone VARCHAR(255),
+ADD COLUMN working_hours_start TIME,
+ADD COLUMN working_hours_end TIME,
+ADD COLUMN working_days TEXT[],
+ADD COLUMN working_hours_timezone VARCHAR(255),
+ADD COLUMN sync_window_past_days INT NOT NULL DEFAULT 30,
+ADD COLUMN sync_window_future_days INT NOT NULL DEFAULT 180,
+ADD COLUMN sync_window_start TIMESTAMP WITH TIME ZONE,
+ADD COLUMN sync_window_end TIMESTAMP WITH TIME ZONE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users
+DROP COLUMN mailbox_timezone,
+DROP COLUMN working_hours_start,
+DROP COLUMN working_hours_end,
+DROP COLUMN working_days,
+DROP COLUMN working_hours_timezone,
+DROP COLUMN sync_window_past_days,
+DROP COLUMN sync_window_future_days,
+DROP COLUMN sync_window_start,
+DROP COLUMN sync_window_end;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- window previous_delta is bound to, restored together with it on rollback
+ALTER TABLE users
+ADD COLUMN previous_sync_window_start TIMESTAMP WITH TIME ZONE,
+ADD COLUMN previous_sync_window_end TIMESTAMP WITH TIME ZONE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users
+DROP COLUMN previous_sync_window_start,
+DROP COLUMN previous_sync_window_end;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- backfills walk backwards from cursor until backfill_until, one chunk at a time
+-- cursor is the start of the last completed chunk so an interrupted job resumes from there
+CREATE TABLE backfill_jobs (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    user_id UUID NOT NULL,
+    chunk_unit VARCHAR(255) NOT NULL,
+    chunk_size INT NOT NULL,
+    backfill_until TIMESTAMP WITH TIME ZONE NOT NULL,
+    cursor TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(255) NOT NULL,
+    events_synced INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE backfill_jobs;
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+-- events are owned by the graph user they were synced for
+ALTER TABLE events
+ALTER COLUMN user_id TYPE VARCHAR(255) USING user_id::VARCHAR,
+ADD COLUMN show_as VARCHAR(255) NOT NULL DEFAULT 'busy';
+
+-- rows synced before this were all stored as user 1, which can only be attributed when a single user exists
+UPDATE events SET user_id = (SELECT user_id::VARCHAR FROM users LIMIT 1)
+WHERE user_id = '1' AND (SELECT COUNT(*) FROM users) = 1;
+
+-- otherwise nobody owns them, stop here rather than leave them orphaned
+DO $$
+DECLARE
+    orphaned_count INT;
+BEGIN
+    SELECT COUNT(*) INTO orphaned_count FROM events WHERE user_id = '1';
+    IF orphaned_count > 0 THEN
+        RAISE EXCEPTION '% events are still stored as user 1 and cannot be attributed to one of the users', orphaned_count
+            USING HINT = 'set their user_id to the graph user they were synced for, or delete them so they are synced again, then rerun the migration';
+    END IF;
+END
+$$;
+
+CREATE INDEX events_user_id_start_time_end_time_idx ON events (user_id, start_time, end_time);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX events_user_id_start_time_end_time_idx;
+
+UPDATE events SET user_id = '1';
+
+ALTER TABLE events
+ALTER COLUMN user_id TYPE BIGINT USING user_id::BIGINT,
+DROP COLUMN show_as;
+-- +goose StatementEnd
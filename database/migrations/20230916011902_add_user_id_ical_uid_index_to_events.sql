@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- each synced user keeps their own copy of a meeting, the copies share the iCalUId
+CREATE INDEX events_user_id_ical_uid_idx ON events (user_id, ical_uid);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX events_user_id_ical_uid_idx;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- lets attendees, which only carry an email address, be matched to synced users
+ALTER TABLE users
+ADD COLUMN email_address VARCHAR(255);
+
+CREATE UNIQUE INDEX users_lower_email_address_idx ON users (LOWER(email_address));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX users_lower_email_address_idx;
+
+ALTER TABLE users
+DROP COLUMN email_address;
+-- +goose StatementEnd
@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+-- a user's bookable time, seeded from their mailbox working hours
+CREATE TABLE availability_rules (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    user_id UUID NOT NULL UNIQUE,
+    time_zone VARCHAR(255) NOT NULL,
+    buffer_before_minutes INT NOT NULL DEFAULT 0,
+    buffer_after_minutes INT NOT NULL DEFAULT 0,
+    minimum_notice_minutes INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- a day can have several ranges, e.g. around a lunch break
+CREATE TABLE availability_weekly_hours (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    user_id UUID NOT NULL,
+    day_of_week VARCHAR(255) NOT NULL,
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX availability_weekly_hours_user_id_idx ON availability_weekly_hours (user_id);
+
+-- overrides replace the weekly hours of their date, an override without times is a day off
+-- holidays block the whole date
+CREATE TABLE availability_overrides (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    user_id UUID NOT NULL,
+    date DATE NOT NULL,
+    start_time TIME,
+    end_time TIME,
+    is_holiday BOOL NOT NULL DEFAULT FALSE,
+    name VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX availability_overrides_user_id_date_idx ON availability_overrides (user_id, date);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE availability_overrides;
+DROP TABLE availability_weekly_hours;
+DROP TABLE availability_rules;
+-- +goose StatementEnd
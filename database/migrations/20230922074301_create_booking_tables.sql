@@ -0,0 +1,54 @@
+-- +goose Up
+-- +goose StatementBegin
+-- booking types are the public scheduling links of a user, found by their slug
+-- buffers and minimum notice override the user's availability rules when set
+CREATE TABLE booking_types (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    user_id UUID NOT NULL,
+    slug VARCHAR(255) NOT NULL UNIQUE,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    duration_minutes INT NOT NULL,
+    slot_interval_minutes INT NOT NULL,
+    booking_window_days INT NOT NULL,
+    buffer_before_minutes INT,
+    buffer_after_minutes INT,
+    minimum_notice_minutes INT,
+    location_display_name VARCHAR(255),
+    is_online_meeting BOOL NOT NULL DEFAULT FALSE,
+    online_meeting_provider VARCHAR(255),
+    questions JSONB NOT NULL DEFAULT '[]',
+    is_active BOOL NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX booking_types_user_id_idx ON booking_types (user_id);
+
+-- guests manage their booking with a token, only its sha256 hash is stored
+CREATE TABLE bookings (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    booking_type_id UUID NOT NULL REFERENCES booking_types (id),
+    user_id UUID NOT NULL,
+    event_id VARCHAR(255) NOT NULL,
+    ical_uid VARCHAR(255) NOT NULL,
+    guest_name VARCHAR(255) NOT NULL,
+    guest_email VARCHAR(255) NOT NULL,
+    answers JSONB NOT NULL DEFAULT '{}',
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(255) NOT NULL,
+    manage_token_hash VARCHAR(255) NOT NULL UNIQUE,
+    cancelled_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX bookings_booking_type_id_idx ON bookings (booking_type_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE bookings;
+DROP TABLE booking_types;
+-- +goose StatementEnd
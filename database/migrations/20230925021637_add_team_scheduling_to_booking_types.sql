@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+-- single books the owner, roundRobin one available member, collective all members at once
+ALTER TABLE booking_types ADD COLUMN scheduling_mode VARCHAR(255) NOT NULL DEFAULT 'single';
+
+CREATE TABLE booking_type_members (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    booking_type_id UUID NOT NULL REFERENCES booking_types (id),
+    user_id UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (booking_type_id, user_id)
+);
+
+-- every member a booking was assigned to, along with their synced event count at the time
+-- round robin picks the least loaded member and breaks ties with this history
+CREATE TABLE booking_assignments (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    booking_id UUID NOT NULL REFERENCES bookings (id),
+    booking_type_id UUID NOT NULL REFERENCES booking_types (id),
+    user_id UUID NOT NULL,
+    scheduling_mode VARCHAR(255) NOT NULL,
+    event_count INT NOT NULL,
+    assigned_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX booking_assignments_booking_type_id_idx ON booking_assignments (booking_type_id, user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE booking_assignments;
+DROP TABLE booking_type_members;
+ALTER TABLE booking_types DROP COLUMN scheduling_mode;
+-- +goose StatementEnd
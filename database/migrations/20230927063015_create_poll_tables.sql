@@ -0,0 +1,65 @@
+-- +goose Up
+-- +goose StatementBegin
+-- a poll proposes several times for a meeting, the organizer finalizes one of them into an event
+CREATE TABLE polls (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    user_id UUID NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    location_display_name VARCHAR(255),
+    is_online_meeting BOOL NOT NULL DEFAULT FALSE,
+    online_meeting_provider VARCHAR(255),
+    status VARCHAR(255) NOT NULL,
+    final_option_id UUID,
+    event_id VARCHAR(255),
+    ical_uid VARCHAR(255),
+    closed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX polls_user_id_idx ON polls (user_id);
+
+CREATE TABLE poll_options (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    poll_id UUID NOT NULL REFERENCES polls (id),
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX poll_options_poll_id_idx ON poll_options (poll_id);
+
+-- invitees vote through a link with their token, only its sha256 hash is stored
+CREATE TABLE poll_invitees (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    poll_id UUID NOT NULL REFERENCES polls (id),
+    email_address VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    token_hash VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX poll_invitees_poll_id_idx ON poll_invitees (poll_id);
+
+CREATE TABLE poll_votes (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    poll_id UUID NOT NULL REFERENCES polls (id),
+    option_id UUID NOT NULL REFERENCES poll_options (id),
+    invitee_id UUID NOT NULL REFERENCES poll_invitees (id),
+    vote VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (option_id, invitee_id)
+);
+
+CREATE INDEX poll_votes_poll_id_idx ON poll_votes (poll_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE poll_votes;
+DROP TABLE poll_invitees;
+DROP TABLE poll_options;
+DROP TABLE polls;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- tentative events blocking time until they are confirmed or expire
+-- a hold is linked to its stored event by user_id and ical_uid, the way events are keyed
+CREATE TABLE holds (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    event_id VARCHAR(255) NOT NULL,
+    ical_uid VARCHAR(255) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(255) NOT NULL,
+    confirmed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX holds_user_id_ical_uid_idx ON holds (user_id, ical_uid);
+CREATE INDEX holds_status_expires_at_idx ON holds (status, expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE holds;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Graph's responseType of the attendee and when they responded, null until known
+ALTER TABLE attendees ADD COLUMN response_status VARCHAR(255);
+ALTER TABLE attendees ADD COLUMN response_time TIMESTAMP WITH TIME ZONE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE attendees DROP COLUMN response_time;
+ALTER TABLE attendees DROP COLUMN response_status;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- required, optional or resource and the time the attendee proposed instead, if any
+ALTER TABLE attendees ADD COLUMN attendee_type VARCHAR(255);
+ALTER TABLE attendees ADD COLUMN proposed_start_time TIMESTAMP WITH TIME ZONE;
+ALTER TABLE attendees ADD COLUMN proposed_end_time TIMESTAMP WITH TIME ZONE;
+
+-- every response change of an attendee, the attendees row only keeps the latest one
+-- rows outlive the attendee so they are keyed on ical_uid and email_address rather than the attendee id
+CREATE TABLE attendee_response_history (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    ical_uid VARCHAR(255) NOT NULL,
+    email_address VARCHAR(255) NOT NULL,
+    response_status VARCHAR(255) NOT NULL,
+    response_time TIMESTAMP WITH TIME ZONE,
+    proposed_start_time TIMESTAMP WITH TIME ZONE,
+    proposed_end_time TIMESTAMP WITH TIME ZONE,
+    source VARCHAR(255) NOT NULL,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX attendee_response_history_ical_uid_idx ON attendee_response_history (ical_uid, recorded_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE attendee_response_history;
+ALTER TABLE attendees DROP COLUMN proposed_end_time;
+ALTER TABLE attendees DROP COLUMN proposed_start_time;
+ALTER TABLE attendees DROP COLUMN attendee_type;
+-- +goose StatementEnd
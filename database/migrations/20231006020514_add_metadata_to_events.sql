@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Graph event properties kept for filtering, defaults are the ones Graph uses
+ALTER TABLE events
+ADD COLUMN importance VARCHAR(255) NOT NULL DEFAULT 'normal',
+ADD COLUMN sensitivity VARCHAR(255) NOT NULL DEFAULT 'normal',
+ADD COLUMN categories TEXT[] NOT NULL DEFAULT '{}',
+ADD COLUMN is_reminder_on BOOLEAN NOT NULL DEFAULT TRUE,
+ADD COLUMN reminder_minutes_before_start INTEGER,
+ADD COLUMN response_requested BOOLEAN NOT NULL DEFAULT TRUE,
+ADD COLUMN allow_new_time_proposals BOOLEAN NOT NULL DEFAULT TRUE,
+ADD COLUMN hide_attendees BOOLEAN NOT NULL DEFAULT FALSE,
+ADD COLUMN organizer_email VARCHAR(255);
+
+CREATE INDEX events_categories_idx ON events USING GIN (categories);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX events_categories_idx;
+
+ALTER TABLE events
+DROP COLUMN organizer_email,
+DROP COLUMN hide_attendees,
+DROP COLUMN allow_new_time_proposals,
+DROP COLUMN response_requested,
+DROP COLUMN reminder_minutes_before_start,
+DROP COLUMN is_reminder_on,
+DROP COLUMN categories,
+DROP COLUMN sensitivity,
+DROP COLUMN importance;
+-- +goose StatementEnd
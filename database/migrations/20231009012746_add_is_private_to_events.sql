@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- private events are only shown in full to their owner, everyone else only sees the busy time
+ALTER TABLE events ADD COLUMN is_private BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE events SET is_private = TRUE WHERE sensitivity = 'private';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE events DROP COLUMN is_private;
+-- +goose StatementEnd
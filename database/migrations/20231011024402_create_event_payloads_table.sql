@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- every version of a graph event as Graph sent it, so the normalized rows can be rebuilt without Graph
+-- sync_run_id groups the payloads of one delta query, delta_page is the page they arrived in
+-- removed_at is set once the user's copy of the event was removed, so it isn't rebuilt
+CREATE TABLE event_payloads (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    event_id VARCHAR(255) NOT NULL,
+    ical_uid VARCHAR(255) NOT NULL,
+    payload JSONB NOT NULL,
+    payload_hash VARCHAR(64) NOT NULL,
+    source VARCHAR(255) NOT NULL,
+    sync_run_id UUID,
+    delta_page INTEGER,
+    delta_page_link TEXT,
+    received_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    removed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX event_payloads_user_id_ical_uid_received_at_idx ON event_payloads (user_id, ical_uid, received_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE event_payloads;
+-- +goose StatementEnd
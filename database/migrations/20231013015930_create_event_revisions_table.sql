@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- before and after values of the tracked fields every time a user's copy of an event was created, changed or removed
+-- event_id is the events row, kept without a foreign key so the history outlives the row
+-- sync_run_id and delta_link are the delta query and page the change arrived in, null for other sources
+CREATE TABLE event_revisions (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    event_id UUID NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    ical_uid VARCHAR(255) NOT NULL,
+    graph_event_id VARCHAR(255) NOT NULL,
+    revision_type VARCHAR(255) NOT NULL,
+    changes JSONB NOT NULL,
+    source VARCHAR(255) NOT NULL,
+    sync_run_id UUID,
+    delta_link TEXT,
+    graph_modified_at TIMESTAMP WITH TIME ZONE,
+    is_private BOOLEAN NOT NULL DEFAULT FALSE,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX event_revisions_event_id_recorded_at_idx ON event_revisions (event_id, recorded_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE event_revisions;
+-- +goose StatementEnd
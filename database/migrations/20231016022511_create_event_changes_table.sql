@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the change feed other services consume, id is the cursor
+-- rows are inserted under a table lock so ids become visible in order and a cursor never skips a change
+CREATE TABLE event_changes (
+    id BIGSERIAL PRIMARY KEY,
+    revision_id UUID NOT NULL,
+    event_id UUID NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    ical_uid VARCHAR(255) NOT NULL,
+    graph_event_id VARCHAR(255) NOT NULL,
+    change_type VARCHAR(255) NOT NULL,
+    changed_fields TEXT[] NOT NULL DEFAULT '{}',
+    is_private BOOLEAN NOT NULL DEFAULT FALSE,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX event_changes_user_id_id_idx ON event_changes (user_id, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE event_changes;
+-- +goose StatementEnd
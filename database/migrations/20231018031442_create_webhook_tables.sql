@@ -0,0 +1,56 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the graph event type of the changed event, webhooks can filter on it
+ALTER TABLE event_changes ADD COLUMN event_type VARCHAR(255) NOT NULL DEFAULT '';
+
+-- endpoints consumers registered, empty filters match everything
+-- the secret signs the payloads so it's kept as is, it's only returned when the webhook is created
+CREATE TABLE webhooks (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    url TEXT NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255),
+    event_types TEXT[] NOT NULL DEFAULT '{}',
+    change_types TEXT[] NOT NULL DEFAULT '{}',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- the outbox, a delivery is added in the same transaction as its change so none is lost when the process stops
+CREATE TABLE webhook_deliveries (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    webhook_id UUID NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    change_id BIGINT NOT NULL REFERENCES event_changes(id) ON DELETE CASCADE,
+    status VARCHAR(255) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT,
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX webhook_deliveries_status_next_attempt_at_idx ON webhook_deliveries (status, next_attempt_at);
+
+-- deliveries that kept failing, replaying one queues a new delivery of the same change
+CREATE TABLE webhook_dead_letters (
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    delivery_id UUID NOT NULL REFERENCES webhook_deliveries(id) ON DELETE CASCADE,
+    webhook_id UUID NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    change_id BIGINT NOT NULL REFERENCES event_changes(id) ON DELETE CASCADE,
+    attempts INTEGER NOT NULL,
+    last_error TEXT,
+    failed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    replayed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX webhook_dead_letters_webhook_id_idx ON webhook_dead_letters (webhook_id, failed_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE webhook_dead_letters;
+DROP TABLE webhook_deliveries;
+DROP TABLE webhooks;
+ALTER TABLE event_changes DROP COLUMN event_type;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- racing stores could leave a user with more than one copy of a meeting, the latest one is kept
+DELETE FROM events e
+USING events newer
+WHERE e.user_id = newer.user_id AND e.ical_uid = newer.ical_uid
+AND (e.updated_at, e.id) < (newer.updated_at, newer.id);
+
+DROP INDEX events_user_id_ical_uid_idx;
+CREATE UNIQUE INDEX events_user_id_ical_uid_idx ON events (user_id, ical_uid);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX events_user_id_ical_uid_idx;
+CREATE INDEX events_user_id_ical_uid_idx ON events (user_id, ical_uid);
+-- +goose StatementEnd
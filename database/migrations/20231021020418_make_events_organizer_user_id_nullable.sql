@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the organizer was stored as a placeholder 1, it's only known when the copy belongs to the organizer
+ALTER TABLE events ALTER COLUMN organizer_user_id DROP NOT NULL;
+ALTER TABLE events ALTER COLUMN organizer_user_id TYPE VARCHAR(255) USING NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE events ALTER COLUMN organizer_user_id TYPE BIGINT USING 1;
+ALTER TABLE events ALTER COLUMN organizer_user_id SET NOT NULL;
+-- +goose StatementEnd
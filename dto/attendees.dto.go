@@ -6,12 +6,35 @@ import (
 	"github.com/google/uuid"
 )
 
+// sources of an attendee response change
+const (
+	ResponseFromSync = "sync"
+	ResponseFromApi  = "api"
+)
+
 type MGraphAttendeeDto struct {
-	ID           uuid.UUID
-	UserId       string
-	Name         string
-	EmailAddress string
-	ICalUid      string
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	ID                uuid.UUID
+	UserId            string
+	Name              string
+	EmailAddress      string
+	ICalUid           string
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	ResponseStatus    *string
+	ResponseTime      *time.Time
+	AttendeeType      *string
+	ProposedStartTime *time.Time
+	ProposedEndTime   *time.Time
+}
+
+type AttendeeResponseHistoryDto struct {
+	ID                uuid.UUID
+	ICalUid           string
+	EmailAddress      string
+	ResponseStatus    string
+	ResponseTime      *time.Time
+	ProposedStartTime *time.Time
+	ProposedEndTime   *time.Time
+	Source            string
+	RecordedAt        time.Time
 }
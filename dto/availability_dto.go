@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AvailabilityRulesDto struct {
+	ID                   uuid.UUID
+	UserId               uuid.UUID
+	TimeZone             string
+	BufferBeforeMinutes  int
+	BufferAfterMinutes   int
+	MinimumNoticeMinutes int
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+}
+
+type AvailabilityWeeklyHoursDto struct {
+	ID        uuid.UUID
+	UserId    uuid.UUID
+	DayOfWeek string
+	StartTime string
+	EndTime   string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type AvailabilityOverrideDto struct {
+	ID        uuid.UUID
+	UserId    uuid.UUID
+	Date      time.Time
+	StartTime *string
+	EndTime   *string
+	IsHoliday bool
+	Name      *string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BackfillJobDto struct {
+	ID            uuid.UUID
+	UserId        uuid.UUID
+	ChunkUnit     string
+	ChunkSize     int
+	BackfillUntil time.Time
+	Cursor        time.Time
+	Status        string
+	EventsSynced  int
+	LastError     *string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+const (
+	BackfillRunning   = "running"
+	BackfillFailed    = "failed"
+	BackfillCompleted = "completed"
+)
@@ -0,0 +1,24 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BookingAssignmentDto struct {
+	ID             uuid.UUID
+	BookingId      uuid.UUID
+	BookingTypeId  uuid.UUID
+	UserId         uuid.UUID
+	SchedulingMode string
+	EventCount     int
+	AssignedAt     time.Time
+}
+
+// how often and how recently a member was assigned bookings of a booking type
+type BookingAssignmentStatsDto struct {
+	UserId          uuid.UUID
+	AssignmentCount int
+	LastAssignedAt  time.Time
+}
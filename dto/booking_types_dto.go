@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	SingleScheduling     = "single"
+	RoundRobinScheduling = "roundRobin"
+	CollectiveScheduling = "collective"
+)
+
+type BookingTypeDto struct {
+	ID                    uuid.UUID
+	UserId                uuid.UUID
+	Slug                  string
+	Title                 string
+	Description           string
+	DurationMinutes       int
+	SlotIntervalMinutes   int
+	BookingWindowDays     int
+	BufferBeforeMinutes   *int
+	BufferAfterMinutes    *int
+	MinimumNoticeMinutes  *int
+	LocationDisplayName   *string
+	IsOnlineMeeting       bool
+	OnlineMeetingProvider *string
+	Questions             []BookingQuestionDto
+	IsActive              bool
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
+	SchedulingMode        string
+}
+
+// questions are stored as jsonb on the booking type
+type BookingQuestionDto struct {
+	Id       string `json:"id"`
+	Label    string `json:"label"`
+	Required bool   `json:"required"`
+}
+
+type BookingTypeMemberDto struct {
+	ID            uuid.UUID
+	BookingTypeId uuid.UUID
+	UserId        uuid.UUID
+	CreatedAt     time.Time
+}
@@ -0,0 +1,30 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	BookingConfirmed = "confirmed"
+	BookingCancelled = "cancelled"
+)
+
+type BookingDto struct {
+	ID              uuid.UUID
+	BookingTypeId   uuid.UUID
+	UserId          uuid.UUID
+	EventId         string
+	ICalUid         string
+	GuestName       string
+	GuestEmail      string
+	Answers         map[string]string
+	StartTime       time.Time
+	EndTime         time.Time
+	Status          string
+	ManageTokenHash string
+	CancelledAt     *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
@@ -0,0 +1,22 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventChangeDto is an entry of the change feed, ChangeType is one of EventCreated, EventUpdated or EventRemoved
+type EventChangeDto struct {
+	ID            int64
+	RevisionId    uuid.UUID
+	EventId       uuid.UUID
+	UserId        string
+	ICalUid       string
+	GraphEventId  string
+	ChangeType    string
+	ChangedFields []string
+	IsPrivate     bool
+	ChangedAt     time.Time
+	EventType     string
+}
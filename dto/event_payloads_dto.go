@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// sources of a stored graph event payload, reprocess only rebuilds rows from payloads that were already stored
+const (
+	EventPayloadFromDelta        = "delta"
+	EventPayloadFromBackfill     = "backfill"
+	EventPayloadFromCalendarView = "calendarView"
+	EventPayloadFromApi          = "api"
+	EventPayloadFromNotification = "notification"
+	EventPayloadFromReprocess    = "reprocess"
+)
+
+type EventPayloadDto struct {
+	ID            uuid.UUID
+	UserId        string
+	EventId       string
+	ICalUid       string
+	Payload       []byte
+	PayloadHash   string
+	Source        string
+	SyncRunId     *uuid.UUID
+	DeltaPage     *int
+	DeltaPageLink *string
+	ReceivedAt    time.Time
+	RemovedAt     *time.Time
+}
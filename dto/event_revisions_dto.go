@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	EventCreated = "created"
+	EventUpdated = "updated"
+	EventRemoved = "removed"
+)
+
+// EventFieldChangeDto is the value of a tracked field before and after the revision, nil when there was none
+type EventFieldChangeDto struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+type EventRevisionDto struct {
+	ID              uuid.UUID
+	EventId         uuid.UUID
+	UserId          string
+	ICalUid         string
+	GraphEventId    string
+	RevisionType    string
+	Changes         map[string]EventFieldChangeDto
+	Source          string
+	SyncRunId       *uuid.UUID
+	DeltaLink       *string
+	GraphModifiedAt *time.Time
+	IsPrivate       bool
+	RecordedAt      time.Time
+
+	// EventType is the graph event type, it goes to the change feed and isn't kept with the revision
+	EventType string
+}
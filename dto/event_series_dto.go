@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MGraphEventSeriesDto struct {
+	ID                       uuid.UUID
+	SeriesMasterId           string
+	ICalUid                  string
+	PatternType              string
+	PatternInterval          int32
+	PatternDaysOfWeek        []string
+	PatternDayOfMonth        *int32
+	PatternMonth             *int32
+	PatternIndex             *string
+	PatternFirstDayOfWeek    *string
+	RangeType                string
+	RangeStartDate           time.Time
+	RangeEndDate             *time.Time
+	RangeNumberOfOccurrences *int32
+	RecurrenceTimeZone       *string
+	CreatedAt                time.Time
+	UpdatedAt                time.Time
+}
+
+type MGraphEventSeriesExceptionDto struct {
+	ID            uuid.UUID
+	EventSeriesId uuid.UUID
+	EventId       string
+	ExceptionType string
+	OriginalStart time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+const (
+	ModifiedExceptionType  = "modified"
+	CancelledExceptionType = "cancelled"
+)
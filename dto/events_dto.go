@@ -14,12 +14,12 @@ type MGraphEventDto struct {
 	Title           string
 	Description     string
 	LocationsCount  int
-	StartTime       string
-	EndTime         string
+	StartTime       time.Time
+	EndTime         time.Time
 	IsOnline        bool
 	IsAllDay        bool
 	IsCancelled     bool
-	OrganizerUserId string
+	OrganizerUserId *string
 	CreatedTime     time.Time
 	UpdatedTime     time.Time
 	Timezone        string
@@ -30,4 +30,34 @@ type MGraphEventDto struct {
 	SeriesMasterId  *string
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
+	EventSeriesId   *uuid.UUID
+	IanaTimezone    string
+	StartDate       *time.Time
+	EndDate         *time.Time
+	ShowAs          string
+
+	Importance                 string
+	Sensitivity                string
+	Categories                 []string
+	IsReminderOn               bool
+	ReminderMinutesBeforeStart *int32
+	ResponseRequested          bool
+	AllowNewTimeProposals      bool
+	HideAttendees              bool
+	OrganizerEmail             *string
+	IsPrivate                  bool
+}
+
+// EventFilterDto narrows GetEventsByFilter down, unset fields don't filter
+// an event matches category when it's one of its categories
+type EventFilterDto struct {
+	StartTime      time.Time
+	EndTime        time.Time
+	UserId         *string
+	ShowAs         *string
+	Importance     *string
+	Sensitivity    *string
+	Category       *string
+	OrganizerEmail *string
+	IsReminderOn   *bool
 }
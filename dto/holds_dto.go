@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// a hold is claimed as confirming or expiring before its event is touched in Graph,
+// so a confirm and the expiry job can't both act on it
+const (
+	HoldHeld       = "held"
+	HoldConfirming = "confirming"
+	HoldConfirmed  = "confirmed"
+	HoldExpiring   = "expiring"
+	HoldExpired    = "expired"
+)
+
+type HoldDto struct {
+	ID          uuid.UUID
+	UserId      string
+	EventId     string
+	ICalUid     string
+	Subject     string
+	StartTime   time.Time
+	EndTime     time.Time
+	ExpiresAt   time.Time
+	Status      string
+	ConfirmedAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
@@ -0,0 +1,64 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// a poll is finalizing while its event is created, votes and other finalizes are turned away
+const (
+	PollOpen       = "open"
+	PollFinalizing = "finalizing"
+	PollClosed     = "closed"
+)
+
+const (
+	VoteYes      = "yes"
+	VoteIfNeeded = "ifNeeded"
+	VoteNo       = "no"
+)
+
+type PollDto struct {
+	ID                    uuid.UUID
+	UserId                uuid.UUID
+	Title                 string
+	Description           string
+	LocationDisplayName   *string
+	IsOnlineMeeting       bool
+	OnlineMeetingProvider *string
+	Status                string
+	FinalOptionId         *uuid.UUID
+	EventId               *string
+	ICalUid               *string
+	ClosedAt              *time.Time
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
+}
+
+type PollOptionDto struct {
+	ID        uuid.UUID
+	PollId    uuid.UUID
+	StartTime time.Time
+	EndTime   time.Time
+	CreatedAt time.Time
+}
+
+type PollInviteeDto struct {
+	ID           uuid.UUID
+	PollId       uuid.UUID
+	EmailAddress string
+	Name         string
+	TokenHash    string
+	CreatedAt    time.Time
+}
+
+type PollVoteDto struct {
+	ID        uuid.UUID
+	PollId    uuid.UUID
+	OptionId  uuid.UUID
+	InviteeId uuid.UUID
+	Vote      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
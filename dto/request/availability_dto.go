@@ -0,0 +1,45 @@
+package requestDto
+
+// sample json request body
+// {
+//     "time_zone": "Tokyo Standard Time",
+//     "buffer_before_minutes": 10,
+//     "buffer_after_minutes": 10,
+//     "minimum_notice_minutes": 240,
+//     "weekly_hours": [
+//         { "day_of_week": "monday", "start_time": "09:00", "end_time": "12:00" },
+//         { "day_of_week": "monday", "start_time": "13:00", "end_time": "18:00" }
+//     ]
+// }
+
+// replaces the user's availability rules and weekly hours as a whole
+type UpdateAvailabilityDto struct {
+	TimeZone             string                 `json:"time_zone"`
+	BufferBeforeMinutes  int                    `json:"buffer_before_minutes"`
+	BufferAfterMinutes   int                    `json:"buffer_after_minutes"`
+	MinimumNoticeMinutes int                    `json:"minimum_notice_minutes"`
+	WeeklyHours          []AvailabilityHoursDto `json:"weekly_hours"`
+}
+
+// times as 15:04, an end time of 00:00 means the end of the day
+type AvailabilityHoursDto struct {
+	DayOfWeek string `json:"day_of_week"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+}
+
+// sample json request body
+// {
+//     "date": "2023-12-29",
+//     "is_holiday": true,
+//     "name": "year end holidays"
+// }
+
+// without times and is_holiday the date is a day off, with times they replace the weekly hours of the date
+type CreateAvailabilityOverrideDto struct {
+	Date      string  `json:"date"`
+	StartTime *string `json:"start_time"`
+	EndTime   *string `json:"end_time"`
+	IsHoliday bool    `json:"is_holiday"`
+	Name      *string `json:"name"`
+}
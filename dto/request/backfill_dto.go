@@ -0,0 +1,14 @@
+package requestDto
+
+// sample json request body
+// {
+//     "until": "2022-01-01",
+//     "chunk_unit": "month",
+//     "chunk_size": 1
+// }
+
+type BackfillDto struct {
+	Until     string `json:"until"`
+	ChunkUnit string `json:"chunk_unit"`
+	ChunkSize int    `json:"chunk_size"`
+}
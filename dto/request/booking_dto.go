@@ -0,0 +1,70 @@
+package requestDto
+
+// sample json request body
+// {
+//     "slug": "luke-30min",
+//     "title": "30 minute meeting",
+//     "description": "a quick chat",
+//     "duration_minutes": 30,
+//     "slot_interval_minutes": 30,
+//     "booking_window_days": 30,
+//     "minimum_notice_minutes": 240,
+//     "is_online_meeting": true,
+//     "online_meeting_provider": "teamsForBusiness",
+//     "questions": [
+//         { "id": "topic", "label": "What would you like to discuss?", "required": true }
+//     ],
+//     "scheduling_mode": "roundRobin",
+//     "members": ["24dc94f1-08bf-4d47-850b-5690533b8236", "6e1b2c1a-3f0e-4c55-9a59-0f4a3c1f5b2e"]
+// }
+
+// the buffers and minimum notice default to the user's availability rules
+// scheduling_mode is one of single, roundRobin or collective, members are only set for the team modes
+type CreateBookingTypeDto struct {
+	Slug                  string                     `json:"slug"`
+	Title                 string                     `json:"title"`
+	Description           string                     `json:"description"`
+	DurationMinutes       int                        `json:"duration_minutes"`
+	SlotIntervalMinutes   *int                       `json:"slot_interval_minutes"`
+	BookingWindowDays     *int                       `json:"booking_window_days"`
+	BufferBeforeMinutes   *int                       `json:"buffer_before_minutes"`
+	BufferAfterMinutes    *int                       `json:"buffer_after_minutes"`
+	MinimumNoticeMinutes  *int                       `json:"minimum_notice_minutes"`
+	LocationDisplayName   *string                    `json:"location_display_name"`
+	IsOnlineMeeting       bool                       `json:"is_online_meeting"`
+	OnlineMeetingProvider *string                    `json:"online_meeting_provider"`
+	Questions             []CreateBookingQuestionDto `json:"questions"`
+	SchedulingMode        *string                    `json:"scheduling_mode"`
+	Members               []string                   `json:"members"`
+}
+
+type CreateBookingQuestionDto struct {
+	Id       string `json:"id"`
+	Label    string `json:"label"`
+	Required bool   `json:"required"`
+}
+
+// sample json request body
+// {
+//     "start": "2023-09-25T01:00:00Z",
+//     "guest_name": "Jane Doe",
+//     "guest_email": "jane.doe@example.com",
+//     "answers": { "topic": "pricing" }
+// }
+
+// start has to be one of the slots offered for the booking type
+type CreateBookingDto struct {
+	Start      string            `json:"start"`
+	GuestName  string            `json:"guest_name"`
+	GuestEmail string            `json:"guest_email"`
+	Answers    map[string]string `json:"answers"`
+}
+
+type RescheduleBookingDto struct {
+	Start string `json:"start"`
+}
+
+// reason is sent to the guest and the owner with the cancellation
+type CancelBookingDto struct {
+	Reason *string `json:"reason"`
+}
@@ -0,0 +1,11 @@
+package requestDto
+
+// how create and update requests treat overlaps with the participants' synced events
+const (
+	// skip the check, the default
+	ConflictPolicyIgnore = "ignore"
+	// go ahead and return the conflicts along with the event
+	ConflictPolicyWarn = "warn"
+	// refuse the request with the conflicts
+	ConflictPolicyReject = "reject"
+)
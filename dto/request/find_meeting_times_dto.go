@@ -0,0 +1,36 @@
+package requestDto
+
+// sample json request body
+// {
+//     "organizer_user_id": "24dc94f1-08bf-4d47-850b-5690533b8236",
+//     "attendees": ["24dc94f1-08bf-4d47-850b-5690533b8236", "someone@example.com"],
+//     "duration": 30,
+//     "start": "2023-09-18",
+//     "end": "2023-09-23",
+//     "working_hours_only": true,
+//     "min_attendee_percentage": 100
+// }
+
+// attendees are synced user ids, or email addresses that are looked up in Graph
+// durations and intervals are in minutes
+// working_hours_only limits attendees without availability rules to their working hours, rules always apply
+type FindMeetingTimesDto struct {
+	OrganizerUserId       *string                          `json:"organizer_user_id"`
+	Attendees             []string                         `json:"attendees"`
+	Duration              int                              `json:"duration"`
+	Start                 string                           `json:"start"`
+	End                   string                           `json:"end"`
+	WorkingHoursOnly      bool                             `json:"working_hours_only"`
+	WorkingHours          *FindMeetingTimesWorkingHoursDto `json:"working_hours"`
+	MinAttendeePercentage *float64                         `json:"min_attendee_percentage"`
+	SlotInterval          *int                             `json:"slot_interval"`
+	MaxCandidates         *int                             `json:"max_candidates"`
+}
+
+// overrides the attendees' own working hours, times as 15:04
+type FindMeetingTimesWorkingHoursDto struct {
+	StartTime  string   `json:"start_time"`
+	EndTime    string   `json:"end_time"`
+	DaysOfWeek []string `json:"days_of_week"`
+	TimeZone   string   `json:"time_zone"`
+}
@@ -0,0 +1,40 @@
+package requestDto
+
+// sample json request body
+// {
+//     "user_id": "24dc94f1-08bf-4d47-850b-5690533b8236",
+//     "subject": "hold for customer call",
+//     "start": "2023-10-02T01:00:00Z",
+//     "end": "2023-10-02T02:00:00Z",
+//     "expires_in_minutes": 120
+// }
+
+// expires_at or expires_in_minutes, by default the hold lasts a day or until it starts
+type CreateHoldDto struct {
+	UserId           string  `json:"user_id"`
+	Subject          string  `json:"subject"`
+	Start            string  `json:"start"`
+	End              string  `json:"end"`
+	ExpiresAt        *string `json:"expires_at"`
+	ExpiresInMinutes *int    `json:"expires_in_minutes"`
+}
+
+// sample json request body
+// {
+//     "subject": "customer call",
+//     "attendees": [
+//         { "email_address": "luke.teo@isao.cloud", "name": "Luke Teo", "attendee_type": "required" }
+//     ],
+//     "is_online_meeting": true,
+//     "online_meeting_provider": "teamsForBusiness"
+// }
+
+// only the fields that are set change on the event, confirming always makes it busy
+type ConfirmHoldDto struct {
+	Subject               *string                         `json:"subject"`
+	Content               *string                         `json:"content"`
+	Attendees             []MGraphCreateEventAttendeeDto  `json:"attendees"`
+	Locations             *[]MGraphCreateEventLocationDto `json:"locations"`
+	IsOnlineMeeting       *bool                           `json:"is_online_meeting"`
+	OnlineMeetingProvider *string                         `json:"online_meeting_provider"`
+}
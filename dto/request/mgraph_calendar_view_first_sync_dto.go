@@ -1,5 +1,9 @@
 package requestDto
 
+// sync window is counted in days around today in the user's mailbox time zone
+// -- defaults to 30 days back and 180 days forward when not set
 type MGraphCalendarViewFirstSyncDto struct {
-	UserId string `json:"user_id"`
+	UserId               string `json:"user_id"`
+	SyncWindowPastDays   *int   `json:"sync_window_past_days"`
+	SyncWindowFutureDays *int   `json:"sync_window_future_days"`
 }
@@ -0,0 +1,29 @@
+package requestDto
+
+// sample json request body Graph posts for the calendar view subscription
+// {
+//     "value": [{
+//         "subscriptionId": "7f105c7d-2dc5-4530-97cd-4e7ae6534c07",
+//         "clientState": "mgraph-scheduler-not-so-secret",
+//         "changeType": "updated",
+//         "resource": "Users/24dc94f1-08bf-4d47-850b-5690533b8236/Events/AAMkAGI1AAAt9AHjAAA=",
+//         "resourceData": {"@odata.type": "#Microsoft.Graph.Event", "id": "AAMkAGI1AAAt9AHjAAA="}
+//     }]
+// }
+
+type MGraphCalendarViewNotificationDto struct {
+	Value []MGraphChangeNotificationDto `json:"value"`
+}
+
+type MGraphChangeNotificationDto struct {
+	SubscriptionId string `json:"subscriptionId"`
+	ClientState    string `json:"clientState"`
+	// created, updated or deleted
+	ChangeType   string                          `json:"changeType"`
+	Resource     string                          `json:"resource"`
+	ResourceData MGraphChangeNotificationDataDto `json:"resourceData"`
+}
+
+type MGraphChangeNotificationDataDto struct {
+	Id string `json:"id"`
+}
@@ -0,0 +1,8 @@
+package requestDto
+
+// event_id can refer to a single event, a series master or one occurrence of a series
+type MGraphCancelEventDto struct {
+	UserId  string  `json:"user_id"`
+	EventId string  `json:"event_id"`
+	Comment *string `json:"comment"`
+}
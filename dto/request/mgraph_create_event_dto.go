@@ -1,11 +1,15 @@
 package requestDto
 
+// user_id is the organizer's mailbox, conflict_policy is one of ignore, warn or reject
+// show_as is Graph's free/busy status of the event, busy when not set
 type MGraphCreateEventDto struct {
+	UserId                *string                         `json:"user_id"`
 	Subject               string                          `json:"subject"`
 	Content               string                          `json:"content"`
 	StartTime             string                          `json:"start_time"`
 	EndTime               string                          `json:"end_time"`
 	TimeZone              string                          `json:"time_zone"`
+	IsAllDay              bool                            `json:"is_all_day"`
 	Attendees             []MGraphCreateEventAttendeeDto  `json:"attendees"`
 	Locations             *[]MGraphCreateEventLocationDto `json:"locations"`
 	IsRecurring           bool                            `json:"is_recurring"`
@@ -17,6 +21,10 @@ type MGraphCreateEventDto struct {
 	RecurrenceEnd         *string                         `json:"recurrence_end"`
 	IsOnlineMeeting       bool                            `json:"is_online_meeting"`
 	OnlineMeetingProvider *string                         `json:"online_meeting_provider"`
+	ConflictPolicy        *string                         `json:"conflict_policy"`
+	ShowAs                *string                         `json:"show_as"`
+
+	MGraphEventMetadataDto
 }
 
 // func TestType() bool {
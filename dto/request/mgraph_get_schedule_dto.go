@@ -0,0 +1,19 @@
+package requestDto
+
+// sample json request body
+// {
+//     "user_id": "24dc94f1-08bf-4d47-850b-5690533b8236",
+//     "schedules": ["someone@example.com", "someone.else@example.com"],
+//     "start": "2023-09-18T00:00:00Z",
+//     "end": "2023-09-19T00:00:00Z",
+//     "interval": 30
+// }
+
+// user_id is the mailbox the lookup is made from, interval is in minutes
+type MGraphGetScheduleDto struct {
+	UserId    *string  `json:"user_id"`
+	Schedules []string `json:"schedules"`
+	Start     string   `json:"start"`
+	End       string   `json:"end"`
+	Interval  *int     `json:"interval"`
+}
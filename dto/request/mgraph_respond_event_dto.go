@@ -0,0 +1,30 @@
+package requestDto
+
+// sample json request body
+// {
+//     "user_id": "24dc94f1-08bf-4d47-850b-5690533b8236",
+//     "event_id": "AAMkAGI1AAAt9AHjAAA=",
+//     "comment": "can we move this to the afternoon?",
+//     "send_response": true,
+//     "proposed_new_time": {
+//         "start_time": "2023-10-02T05:00:00",
+//         "end_time": "2023-10-02T06:00:00",
+//         "time_zone": "UTC"
+//     }
+// }
+
+// send_response defaults to true in Graph, proposed_new_time only goes with decline and tentatively accept
+// and needs the response to be sent
+type MGraphRespondEventDto struct {
+	UserId          string                    `json:"user_id"`
+	EventId         string                    `json:"event_id"`
+	Comment         *string                   `json:"comment"`
+	SendResponse    *bool                     `json:"send_response"`
+	ProposedNewTime *MGraphProposedNewTimeDto `json:"proposed_new_time"`
+}
+
+type MGraphProposedNewTimeDto struct {
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+	TimeZone  string `json:"time_zone"`
+}
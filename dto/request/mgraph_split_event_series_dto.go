@@ -0,0 +1,20 @@
+package requestDto
+
+// splits a recurring series at the occurrence referenced by event_id
+// the original series is truncated to end the day before the occurrence,
+// and a new series starting on the occurrence date is created with the given pattern
+// pattern and range fields left empty are carried over from the original series
+type MGraphSplitEventSeriesDto struct {
+	UserId            string    `json:"user_id"`
+	EventId           string    `json:"event_id"`
+	Subject           *string   `json:"subject"`
+	Content           *string   `json:"content"`
+	StartTime         *string   `json:"start_time"`
+	EndTime           *string   `json:"end_time"`
+	TimeZone          *string   `json:"time_zone"`
+	PatternType       *string   `json:"pattern_type"`
+	PatternInterval   *int32    `json:"pattern_interval"`
+	PatternDaysOfWeek *[]string `json:"pattern_days_of_week"`
+	RecurrenceType    *string   `json:"recurrence_type"`
+	RecurrenceEnd     *string   `json:"recurrence_end"`
+}
@@ -0,0 +1,37 @@
+package requestDto
+
+// only the fields that are set will be sent to Microsoft Graph
+// when event_id refers to an occurrence, Graph turns it into an exception of the series
+// when is_all_day is true, start_time and end_time can be plain dates with an exclusive end date
+// conflict_policy is one of ignore, warn or reject, and is only applied when the time or attendees change
+type MGraphUpdateEventDto struct {
+	UserId                string                          `json:"user_id"`
+	EventId               string                          `json:"event_id"`
+	Subject               *string                         `json:"subject"`
+	Content               *string                         `json:"content"`
+	StartTime             *string                         `json:"start_time"`
+	EndTime               *string                         `json:"end_time"`
+	TimeZone              *string                         `json:"time_zone"`
+	IsAllDay              *bool                           `json:"is_all_day"`
+	Attendees             *[]MGraphCreateEventAttendeeDto `json:"attendees"`
+	Locations             *[]MGraphCreateEventLocationDto `json:"locations"`
+	IsOnlineMeeting       *bool                           `json:"is_online_meeting"`
+	OnlineMeetingProvider *string                         `json:"online_meeting_provider"`
+	ConflictPolicy        *string                         `json:"conflict_policy"`
+	ShowAs                *string                         `json:"show_as"`
+
+	MGraphEventMetadataDto
+}
+
+// the event properties create and update share, they are embedded so the fields sit at the top level of the json
+// only the fields that are set are sent, Graph keeps its defaults for the rest
+type MGraphEventMetadataDto struct {
+	Importance                 *string   `json:"importance"`
+	Sensitivity                *string   `json:"sensitivity"`
+	Categories                 *[]string `json:"categories"`
+	IsReminderOn               *bool     `json:"is_reminder_on"`
+	ReminderMinutesBeforeStart *int32    `json:"reminder_minutes_before_start"`
+	ResponseRequested          *bool     `json:"response_requested"`
+	AllowNewTimeProposals      *bool     `json:"allow_new_time_proposals"`
+	HideAttendees              *bool     `json:"hide_attendees"`
+}
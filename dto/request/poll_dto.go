@@ -0,0 +1,59 @@
+package requestDto
+
+// sample json request body
+// {
+//     "title": "quarterly planning",
+//     "description": "pick the time that works best",
+//     "is_online_meeting": true,
+//     "online_meeting_provider": "teamsForBusiness",
+//     "options": [
+//         { "start": "2023-10-02T01:00:00Z", "end": "2023-10-02T02:00:00Z" },
+//         { "start": "2023-10-03T05:00:00Z", "end": "2023-10-03T06:00:00Z" }
+//     ],
+//     "invitees": [
+//         { "email_address": "luke.teo@isao.cloud", "name": "Luke Teo" }
+//     ]
+// }
+
+type CreatePollDto struct {
+	Title                 string                 `json:"title"`
+	Description           string                 `json:"description"`
+	LocationDisplayName   *string                `json:"location_display_name"`
+	IsOnlineMeeting       bool                   `json:"is_online_meeting"`
+	OnlineMeetingProvider *string                `json:"online_meeting_provider"`
+	Options               []CreatePollOptionDto  `json:"options"`
+	Invitees              []CreatePollInviteeDto `json:"invitees"`
+}
+
+// start and end as RFC3339
+type CreatePollOptionDto struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+type CreatePollInviteeDto struct {
+	EmailAddress string `json:"email_address"`
+	Name         string `json:"name"`
+}
+
+// sample json request body
+// {
+//     "votes": [
+//         { "option_id": "5b0f0a52-3a8e-4b8e-9d1e-0f6d3b1c2a10", "vote": "yes" },
+//         { "option_id": "a1c3e2f4-7b6d-4e5f-8a9b-0c1d2e3f4a5b", "vote": "ifNeeded" }
+//     ]
+// }
+
+// replaces all of the invitee's votes, vote is one of yes, ifNeeded or no
+type UpdatePollVotesDto struct {
+	Votes []PollVoteDto `json:"votes"`
+}
+
+type PollVoteDto struct {
+	OptionId string `json:"option_id"`
+	Vote     string `json:"vote"`
+}
+
+type FinalizePollDto struct {
+	OptionId string `json:"option_id"`
+}
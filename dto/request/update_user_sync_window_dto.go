@@ -0,0 +1,6 @@
+package requestDto
+
+type UpdateUserSyncWindowDto struct {
+	SyncWindowPastDays   *int `json:"sync_window_past_days"`
+	SyncWindowFutureDays *int `json:"sync_window_future_days"`
+}
@@ -0,0 +1,17 @@
+package requestDto
+
+// sample json request body
+// {
+//     "url": "https://example.com/hooks/calendar",
+//     "user_id": "24dc94f1-08bf-4d47-850b-5690533b8236",
+//     "event_types": ["singleInstance", "occurrence"],
+//     "change_types": ["created", "removed"]
+// }
+
+// user_id, event_types and change_types narrow down the changes that are sent, left out they match every change
+type CreateWebhookDto struct {
+	Url         string   `json:"url"`
+	UserId      *string  `json:"user_id"`
+	EventTypes  []string `json:"event_types"`
+	ChangeTypes []string `json:"change_types"`
+}
@@ -0,0 +1,68 @@
+package responseDto
+
+import (
+	"github.com/google/uuid"
+	"github.com/scheduler-prototype/dto"
+)
+
+type AvailabilityHoursResponseDto struct {
+	DayOfWeek string `json:"day_of_week"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+}
+
+type AvailabilityOverrideResponseDto struct {
+	ID        uuid.UUID `json:"id"`
+	Date      string    `json:"date"`
+	StartTime *string   `json:"start_time"`
+	EndTime   *string   `json:"end_time"`
+	IsHoliday bool      `json:"is_holiday"`
+	Name      *string   `json:"name"`
+}
+
+type AvailabilityResponseDto struct {
+	UserId               uuid.UUID                         `json:"user_id"`
+	TimeZone             string                            `json:"time_zone"`
+	BufferBeforeMinutes  int                               `json:"buffer_before_minutes"`
+	BufferAfterMinutes   int                               `json:"buffer_after_minutes"`
+	MinimumNoticeMinutes int                               `json:"minimum_notice_minutes"`
+	WeeklyHours          []AvailabilityHoursResponseDto    `json:"weekly_hours"`
+	Overrides            []AvailabilityOverrideResponseDto `json:"overrides"`
+}
+
+func NewAvailabilityOverrideResponseDto(override dto.AvailabilityOverrideDto) AvailabilityOverrideResponseDto {
+	return AvailabilityOverrideResponseDto{
+		ID:        override.ID,
+		Date:      override.Date.Format("2006-01-02"),
+		StartTime: override.StartTime,
+		EndTime:   override.EndTime,
+		IsHoliday: override.IsHoliday,
+		Name:      override.Name,
+	}
+}
+
+func NewAvailabilityResponseDto(rules dto.AvailabilityRulesDto, weeklyHours []dto.AvailabilityWeeklyHoursDto, overrides []dto.AvailabilityOverrideDto) AvailabilityResponseDto {
+	hoursResponses := []AvailabilityHoursResponseDto{}
+	for _, hours := range weeklyHours {
+		hoursResponses = append(hoursResponses, AvailabilityHoursResponseDto{
+			DayOfWeek: hours.DayOfWeek,
+			StartTime: hours.StartTime,
+			EndTime:   hours.EndTime,
+		})
+	}
+
+	overrideResponses := []AvailabilityOverrideResponseDto{}
+	for _, override := range overrides {
+		overrideResponses = append(overrideResponses, NewAvailabilityOverrideResponseDto(override))
+	}
+
+	return AvailabilityResponseDto{
+		UserId:               rules.UserId,
+		TimeZone:             rules.TimeZone,
+		BufferBeforeMinutes:  rules.BufferBeforeMinutes,
+		BufferAfterMinutes:   rules.BufferAfterMinutes,
+		MinimumNoticeMinutes: rules.MinimumNoticeMinutes,
+		WeeklyHours:          hoursResponses,
+		Overrides:            overrideResponses,
+	}
+}
@@ -0,0 +1,38 @@
+package responseDto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/scheduler-prototype/dto"
+)
+
+type BackfillJobResponseDto struct {
+	ID            uuid.UUID `json:"id"`
+	UserId        uuid.UUID `json:"user_id"`
+	ChunkUnit     string    `json:"chunk_unit"`
+	ChunkSize     int       `json:"chunk_size"`
+	BackfillUntil time.Time `json:"backfill_until"`
+	Cursor        time.Time `json:"cursor"`
+	Status        string    `json:"status"`
+	EventsSynced  int       `json:"events_synced"`
+	LastError     *string   `json:"last_error"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func NewBackfillJobResponseDto(backfillJob dto.BackfillJobDto) BackfillJobResponseDto {
+	return BackfillJobResponseDto{
+		ID:            backfillJob.ID,
+		UserId:        backfillJob.UserId,
+		ChunkUnit:     backfillJob.ChunkUnit,
+		ChunkSize:     backfillJob.ChunkSize,
+		BackfillUntil: backfillJob.BackfillUntil,
+		Cursor:        backfillJob.Cursor,
+		Status:        backfillJob.Status,
+		EventsSynced:  backfillJob.EventsSynced,
+		LastError:     backfillJob.LastError,
+		CreatedAt:     backfillJob.CreatedAt,
+		UpdatedAt:     backfillJob.UpdatedAt,
+	}
+}
@@ -0,0 +1,154 @@
+package responseDto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/scheduler-prototype/dto"
+)
+
+type BookingQuestionResponseDto struct {
+	Id       string `json:"id"`
+	Label    string `json:"label"`
+	Required bool   `json:"required"`
+}
+
+type BookingTypeResponseDto struct {
+	ID                    uuid.UUID                    `json:"id"`
+	UserId                uuid.UUID                    `json:"user_id"`
+	Slug                  string                       `json:"slug"`
+	Title                 string                       `json:"title"`
+	Description           string                       `json:"description"`
+	DurationMinutes       int                          `json:"duration_minutes"`
+	SlotIntervalMinutes   int                          `json:"slot_interval_minutes"`
+	BookingWindowDays     int                          `json:"booking_window_days"`
+	BufferBeforeMinutes   *int                         `json:"buffer_before_minutes"`
+	BufferAfterMinutes    *int                         `json:"buffer_after_minutes"`
+	MinimumNoticeMinutes  *int                         `json:"minimum_notice_minutes"`
+	LocationDisplayName   *string                      `json:"location_display_name"`
+	IsOnlineMeeting       bool                         `json:"is_online_meeting"`
+	OnlineMeetingProvider *string                      `json:"online_meeting_provider"`
+	Questions             []BookingQuestionResponseDto `json:"questions"`
+	IsActive              bool                         `json:"is_active"`
+	SchedulingMode        string                       `json:"scheduling_mode"`
+	Members               []uuid.UUID                  `json:"members"`
+}
+
+// what guests get to see of a booking type, without the owner's settings
+type PublicBookingTypeResponseDto struct {
+	Slug                string                       `json:"slug"`
+	Title               string                       `json:"title"`
+	Description         string                       `json:"description"`
+	DurationMinutes     int                          `json:"duration_minutes"`
+	BookingWindowDays   int                          `json:"booking_window_days"`
+	LocationDisplayName *string                      `json:"location_display_name"`
+	IsOnlineMeeting     bool                         `json:"is_online_meeting"`
+	Questions           []BookingQuestionResponseDto `json:"questions"`
+}
+
+type BookingSlotResponseDto struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// manage_token is only returned when the booking is made, guests need it to reschedule or cancel
+type BookingResponseDto struct {
+	ID          uuid.UUID         `json:"id"`
+	Slug        string            `json:"slug"`
+	Title       string            `json:"title"`
+	GuestName   string            `json:"guest_name"`
+	GuestEmail  string            `json:"guest_email"`
+	Answers     map[string]string `json:"answers"`
+	Start       time.Time         `json:"start"`
+	End         time.Time         `json:"end"`
+	Status      string            `json:"status"`
+	CancelledAt *time.Time        `json:"cancelled_at"`
+	ManageToken *string           `json:"manage_token,omitempty"`
+}
+
+func newBookingQuestionResponseDtos(questions []dto.BookingQuestionDto) []BookingQuestionResponseDto {
+	questionResponses := []BookingQuestionResponseDto{}
+	for _, question := range questions {
+		questionResponses = append(questionResponses, BookingQuestionResponseDto{
+			Id:       question.Id,
+			Label:    question.Label,
+			Required: question.Required,
+		})
+	}
+	return questionResponses
+}
+
+type BookingAssignmentResponseDto struct {
+	BookingId      uuid.UUID `json:"booking_id"`
+	UserId         uuid.UUID `json:"user_id"`
+	SchedulingMode string    `json:"scheduling_mode"`
+	EventCount     int       `json:"event_count"`
+	AssignedAt     time.Time `json:"assigned_at"`
+}
+
+func NewBookingTypeResponseDto(bookingType dto.BookingTypeDto, members []dto.BookingTypeMemberDto) BookingTypeResponseDto {
+	memberIds := []uuid.UUID{}
+	for _, member := range members {
+		memberIds = append(memberIds, member.UserId)
+	}
+
+	return BookingTypeResponseDto{
+		ID:                    bookingType.ID,
+		UserId:                bookingType.UserId,
+		Slug:                  bookingType.Slug,
+		Title:                 bookingType.Title,
+		Description:           bookingType.Description,
+		DurationMinutes:       bookingType.DurationMinutes,
+		SlotIntervalMinutes:   bookingType.SlotIntervalMinutes,
+		BookingWindowDays:     bookingType.BookingWindowDays,
+		BufferBeforeMinutes:   bookingType.BufferBeforeMinutes,
+		BufferAfterMinutes:    bookingType.BufferAfterMinutes,
+		MinimumNoticeMinutes:  bookingType.MinimumNoticeMinutes,
+		LocationDisplayName:   bookingType.LocationDisplayName,
+		IsOnlineMeeting:       bookingType.IsOnlineMeeting,
+		OnlineMeetingProvider: bookingType.OnlineMeetingProvider,
+		Questions:             newBookingQuestionResponseDtos(bookingType.Questions),
+		IsActive:              bookingType.IsActive,
+		SchedulingMode:        bookingType.SchedulingMode,
+		Members:               memberIds,
+	}
+}
+
+func NewPublicBookingTypeResponseDto(bookingType dto.BookingTypeDto) PublicBookingTypeResponseDto {
+	return PublicBookingTypeResponseDto{
+		Slug:                bookingType.Slug,
+		Title:               bookingType.Title,
+		Description:         bookingType.Description,
+		DurationMinutes:     bookingType.DurationMinutes,
+		BookingWindowDays:   bookingType.BookingWindowDays,
+		LocationDisplayName: bookingType.LocationDisplayName,
+		IsOnlineMeeting:     bookingType.IsOnlineMeeting,
+		Questions:           newBookingQuestionResponseDtos(bookingType.Questions),
+	}
+}
+
+func NewBookingResponseDto(booking dto.BookingDto, bookingType dto.BookingTypeDto, manageToken *string) BookingResponseDto {
+	return BookingResponseDto{
+		ID:          booking.ID,
+		Slug:        bookingType.Slug,
+		Title:       bookingType.Title,
+		GuestName:   booking.GuestName,
+		GuestEmail:  booking.GuestEmail,
+		Answers:     booking.Answers,
+		Start:       booking.StartTime,
+		End:         booking.EndTime,
+		Status:      booking.Status,
+		CancelledAt: booking.CancelledAt,
+		ManageToken: manageToken,
+	}
+}
+
+func NewBookingAssignmentResponseDto(assignment dto.BookingAssignmentDto) BookingAssignmentResponseDto {
+	return BookingAssignmentResponseDto{
+		BookingId:      assignment.BookingId,
+		UserId:         assignment.UserId,
+		SchedulingMode: assignment.SchedulingMode,
+		EventCount:     assignment.EventCount,
+		AssignedAt:     assignment.AssignedAt,
+	}
+}
@@ -0,0 +1,48 @@
+package responseDto
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/scheduler-prototype/dto"
+)
+
+// event is the current state of the event, which may be newer than the change, and null once it was removed
+type EventChangeResponseDto struct {
+	Cursor        string            `json:"cursor"`
+	ChangeType    string            `json:"change_type"`
+	EventId       uuid.UUID         `json:"event_id"`
+	UserId        string            `json:"user_id"`
+	ICalUid       string            `json:"ical_uid"`
+	GraphEventId  string            `json:"graph_event_id"`
+	ChangedFields []string          `json:"changed_fields"`
+	ChangedAt     time.Time         `json:"changed_at"`
+	Event         *EventResponseDto `json:"event"`
+}
+
+// next_cursor is passed as since to get the changes after this page, it stays the same when there are none yet
+type EventChangesResponseDto struct {
+	Changes    []EventChangeResponseDto `json:"changes"`
+	NextCursor string                   `json:"next_cursor"`
+	HasMore    bool                     `json:"has_more"`
+}
+
+func NewEventChangeResponseDto(change dto.EventChangeDto, event *EventResponseDto) EventChangeResponseDto {
+	changedFields := change.ChangedFields
+	if changedFields == nil {
+		changedFields = []string{}
+	}
+
+	return EventChangeResponseDto{
+		Cursor:        strconv.FormatInt(change.ID, 10),
+		ChangeType:    change.ChangeType,
+		EventId:       change.EventId,
+		UserId:        change.UserId,
+		ICalUid:       change.ICalUid,
+		GraphEventId:  change.GraphEventId,
+		ChangedFields: changedFields,
+		ChangedAt:     change.ChangedAt,
+		Event:         event,
+	}
+}
@@ -0,0 +1,30 @@
+package responseDto
+
+import (
+	"time"
+
+	"github.com/scheduler-prototype/dto"
+)
+
+// a synced event of the organizer or an internal attendee that overlaps the requested time
+type EventConflictResponseDto struct {
+	UserId       string    `json:"user_id"`
+	EmailAddress *string   `json:"email_address"`
+	EventId      string    `json:"event_id"`
+	Title        string    `json:"title"`
+	StartTime    time.Time `json:"start_time"`
+	EndTime      time.Time `json:"end_time"`
+	ShowAs       string    `json:"show_as"`
+}
+
+func NewEventConflictResponseDto(user dto.UserDto, event dto.MGraphEventDto) EventConflictResponseDto {
+	return EventConflictResponseDto{
+		UserId:       event.UserId,
+		EmailAddress: user.EmailAddress,
+		EventId:      event.EventId,
+		Title:        event.Title,
+		StartTime:    event.StartTime.UTC(),
+		EndTime:      event.EndTime.UTC(),
+		ShowAs:       event.ShowAs,
+	}
+}
@@ -0,0 +1,117 @@
+package responseDto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/scheduler-prototype/dto"
+)
+
+// all day events only carry start_date and end_date (exclusive),
+// every other event only carries start_time and end_time
+type EventResponseDto struct {
+	ID             uuid.UUID  `json:"id"`
+	EventId        string     `json:"event_id"`
+	ICalUid        string     `json:"ical_uid"`
+	Title          string     `json:"title"`
+	Description    string     `json:"description"`
+	StartTime      *time.Time `json:"start_time,omitempty"`
+	EndTime        *time.Time `json:"end_time,omitempty"`
+	StartDate      *string    `json:"start_date,omitempty"`
+	EndDate        *string    `json:"end_date,omitempty"`
+	IsAllDay       bool       `json:"is_all_day"`
+	Timezone       string     `json:"timezone"`
+	IanaTimezone   string     `json:"iana_timezone"`
+	IsOnline       bool       `json:"is_online"`
+	IsCancelled    bool       `json:"is_cancelled"`
+	IsRecurring    bool       `json:"is_recurring"`
+	Type           string     `json:"type"`
+	SeriesMasterId *string    `json:"series_master_id"`
+	PlatformUrl    string     `json:"platform_url"`
+	MeetingUrl     *string    `json:"meeting_url"`
+	UserId         string     `json:"user_id"`
+	ShowAs         string     `json:"show_as"`
+
+	Importance                 string   `json:"importance"`
+	Sensitivity                string   `json:"sensitivity"`
+	Categories                 []string `json:"categories"`
+	IsReminderOn               bool     `json:"is_reminder_on"`
+	ReminderMinutesBeforeStart *int32   `json:"reminder_minutes_before_start"`
+	ResponseRequested          bool     `json:"response_requested"`
+	AllowNewTimeProposals      bool     `json:"allow_new_time_proposals"`
+	HideAttendees              bool     `json:"hide_attendees"`
+	OrganizerEmail             *string  `json:"organizer_email"`
+	IsPrivate                  bool     `json:"is_private"`
+}
+
+func NewEventResponseDto(event dto.MGraphEventDto) EventResponseDto {
+	response := EventResponseDto{
+		ID:             event.ID,
+		EventId:        event.EventId,
+		ICalUid:        event.ICalUid,
+		Title:          event.Title,
+		Description:    event.Description,
+		IsAllDay:       event.IsAllDay,
+		Timezone:       event.Timezone,
+		IanaTimezone:   event.IanaTimezone,
+		IsOnline:       event.IsOnline,
+		IsCancelled:    event.IsCancelled,
+		IsRecurring:    event.IsRecurring,
+		Type:           event.Type,
+		SeriesMasterId: event.SeriesMasterId,
+		PlatformUrl:    event.PlatformUrl,
+		MeetingUrl:     event.MeetingUrl,
+		UserId:         event.UserId,
+		ShowAs:         event.ShowAs,
+
+		Importance:                 event.Importance,
+		Sensitivity:                event.Sensitivity,
+		Categories:                 event.Categories,
+		IsReminderOn:               event.IsReminderOn,
+		ReminderMinutesBeforeStart: event.ReminderMinutesBeforeStart,
+		ResponseRequested:          event.ResponseRequested,
+		AllowNewTimeProposals:      event.AllowNewTimeProposals,
+		HideAttendees:              event.HideAttendees,
+		OrganizerEmail:             event.OrganizerEmail,
+		IsPrivate:                  event.IsPrivate,
+	}
+
+	setEventResponseTimes(&response, event)
+
+	return response
+}
+
+// NewBusyEventResponseDto only shows when the event keeps its owner busy, for private events of other users
+func NewBusyEventResponseDto(event dto.MGraphEventDto) EventResponseDto {
+	response := EventResponseDto{
+		ID:           event.ID,
+		IsAllDay:     event.IsAllDay,
+		Timezone:     event.Timezone,
+		IanaTimezone: event.IanaTimezone,
+		IsCancelled:  event.IsCancelled,
+		IsRecurring:  event.IsRecurring,
+		Type:         event.Type,
+		UserId:       event.UserId,
+		ShowAs:       event.ShowAs,
+		Categories:   []string{},
+		IsPrivate:    true,
+	}
+
+	setEventResponseTimes(&response, event)
+
+	return response
+}
+
+func setEventResponseTimes(response *EventResponseDto, event dto.MGraphEventDto) {
+	if event.IsAllDay && event.StartDate != nil && event.EndDate != nil {
+		startDate := event.StartDate.Format("2006-01-02")
+		endDate := event.EndDate.Format("2006-01-02")
+		response.StartDate = &startDate
+		response.EndDate = &endDate
+	} else {
+		startTime := event.StartTime.UTC()
+		endTime := event.EndTime.UTC()
+		response.StartTime = &startTime
+		response.EndTime = &endTime
+	}
+}
@@ -0,0 +1,107 @@
+package responseDto
+
+import (
+	"time"
+
+	"github.com/scheduler-prototype/dto"
+)
+
+type ProposedNewTimeResponseDto struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+type AttendeeResponseResponseDto struct {
+	Name            string                      `json:"name,omitempty"`
+	EmailAddress    string                      `json:"email_address"`
+	Type            *string                     `json:"type,omitempty"`
+	ResponseStatus  *string                     `json:"response_status"`
+	ResponseTime    *time.Time                  `json:"response_time"`
+	ProposedNewTime *ProposedNewTimeResponseDto `json:"proposed_new_time,omitempty"`
+	Source          string                      `json:"source,omitempty"`
+	RecordedAt      *time.Time                  `json:"recorded_at,omitempty"`
+}
+
+// the acceptance rate is accepted over invitees, the organizer and resources aren't invitees
+type EventResponsesResponseDto struct {
+	EventId             string                        `json:"event_id"`
+	ICalUid             string                        `json:"ical_uid"`
+	Title               string                        `json:"title"`
+	Invitees            int                           `json:"invitees"`
+	Accepted            int                           `json:"accepted"`
+	TentativelyAccepted int                           `json:"tentatively_accepted"`
+	Declined            int                           `json:"declined"`
+	NotResponded        int                           `json:"not_responded"`
+	AcceptanceRate      float64                       `json:"acceptance_rate"`
+	Attendees           []AttendeeResponseResponseDto `json:"attendees"`
+	History             []AttendeeResponseResponseDto `json:"history"`
+}
+
+func NewEventResponsesResponseDto(event dto.MGraphEventDto, attendees []dto.MGraphAttendeeDto, history []dto.AttendeeResponseHistoryDto) EventResponsesResponseDto {
+	response := EventResponsesResponseDto{
+		EventId:   event.EventId,
+		ICalUid:   event.ICalUid,
+		Title:     event.Title,
+		Attendees: []AttendeeResponseResponseDto{},
+		History:   []AttendeeResponseResponseDto{},
+	}
+
+	for _, attendee := range attendees {
+		response.Attendees = append(response.Attendees, AttendeeResponseResponseDto{
+			Name:            attendee.Name,
+			EmailAddress:    attendee.EmailAddress,
+			Type:            attendee.AttendeeType,
+			ResponseStatus:  attendee.ResponseStatus,
+			ResponseTime:    attendee.ResponseTime,
+			ProposedNewTime: newProposedNewTimeResponseDto(attendee.ProposedStartTime, attendee.ProposedEndTime),
+		})
+
+		if (attendee.AttendeeType != nil && *attendee.AttendeeType == "resource") ||
+			(attendee.ResponseStatus != nil && *attendee.ResponseStatus == "organizer") {
+			continue
+		}
+
+		response.Invitees++
+		responseStatus := ""
+		if attendee.ResponseStatus != nil {
+			responseStatus = *attendee.ResponseStatus
+		}
+		switch responseStatus {
+		case "accepted":
+			response.Accepted++
+		case "tentativelyAccepted":
+			response.TentativelyAccepted++
+		case "declined":
+			response.Declined++
+		default:
+			response.NotResponded++
+		}
+	}
+
+	if response.Invitees > 0 {
+		response.AcceptanceRate = float64(response.Accepted) / float64(response.Invitees)
+	}
+
+	for _, change := range history {
+		responseStatus := change.ResponseStatus
+		recordedAt := change.RecordedAt
+		response.History = append(response.History, AttendeeResponseResponseDto{
+			EmailAddress:    change.EmailAddress,
+			ResponseStatus:  &responseStatus,
+			ResponseTime:    change.ResponseTime,
+			ProposedNewTime: newProposedNewTimeResponseDto(change.ProposedStartTime, change.ProposedEndTime),
+			Source:          change.Source,
+			RecordedAt:      &recordedAt,
+		})
+	}
+
+	return response
+}
+
+func newProposedNewTimeResponseDto(start *time.Time, end *time.Time) *ProposedNewTimeResponseDto {
+	if start == nil || end == nil {
+		return nil
+	}
+
+	return &ProposedNewTimeResponseDto{Start: start.UTC(), End: end.UTC()}
+}
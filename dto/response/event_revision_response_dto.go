@@ -0,0 +1,37 @@
+package responseDto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/scheduler-prototype/dto"
+)
+
+// graph_modified_at is when Graph last modified the event, recorded_at is when we saw the change
+type EventRevisionResponseDto struct {
+	ID              uuid.UUID                          `json:"id"`
+	EventId         uuid.UUID                          `json:"event_id"`
+	UserId          string                             `json:"user_id"`
+	RevisionType    string                             `json:"revision_type"`
+	Changes         map[string]dto.EventFieldChangeDto `json:"changes"`
+	Source          string                             `json:"source"`
+	SyncRunId       *uuid.UUID                         `json:"sync_run_id"`
+	DeltaLink       *string                            `json:"delta_link"`
+	GraphModifiedAt *time.Time                         `json:"graph_modified_at"`
+	RecordedAt      time.Time                          `json:"recorded_at"`
+}
+
+func NewEventRevisionResponseDto(revision dto.EventRevisionDto) EventRevisionResponseDto {
+	return EventRevisionResponseDto{
+		ID:              revision.ID,
+		EventId:         revision.EventId,
+		UserId:          revision.UserId,
+		RevisionType:    revision.RevisionType,
+		Changes:         revision.Changes,
+		Source:          revision.Source,
+		SyncRunId:       revision.SyncRunId,
+		DeltaLink:       revision.DeltaLink,
+		GraphModifiedAt: revision.GraphModifiedAt,
+		RecordedAt:      revision.RecordedAt,
+	}
+}
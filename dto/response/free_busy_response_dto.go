@@ -0,0 +1,52 @@
+package responseDto
+
+import (
+	"time"
+
+	"github.com/scheduler-prototype/freebusy"
+)
+
+type FreeBusyIntervalResponseDto struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Status string    `json:"status"`
+}
+
+// availability_view has one digit per interval of the range, in the format Graph's getSchedule uses
+// unavailable_intervals is the time blocked by the user's availability rules, it isn't part of availability_view
+// user_id holds the email address for schedules looked up in Graph, error is set when the lookup failed
+type FreeBusyResponseDto struct {
+	UserId               string                        `json:"user_id"`
+	Start                time.Time                     `json:"start"`
+	End                  time.Time                     `json:"end"`
+	Interval             int                           `json:"interval"`
+	BusyIntervals        []FreeBusyIntervalResponseDto `json:"busy_intervals"`
+	UnavailableIntervals []FreeBusyIntervalResponseDto `json:"unavailable_intervals"`
+	AvailabilityView     string                        `json:"availability_view"`
+	Error                *string                       `json:"error,omitempty"`
+}
+
+func NewFreeBusyResponseDto(userId string, start time.Time, end time.Time, interval time.Duration, busyIntervals []freebusy.Interval, unavailableIntervals []freebusy.Interval) FreeBusyResponseDto {
+	return FreeBusyResponseDto{
+		UserId:               userId,
+		Start:                start,
+		End:                  end,
+		Interval:             int(interval / time.Minute),
+		BusyIntervals:        newFreeBusyIntervalResponseDtos(busyIntervals),
+		UnavailableIntervals: newFreeBusyIntervalResponseDtos(unavailableIntervals),
+		AvailabilityView:     freebusy.AvailabilityView(busyIntervals, start, end, interval),
+	}
+}
+
+func newFreeBusyIntervalResponseDtos(intervals []freebusy.Interval) []FreeBusyIntervalResponseDto {
+	intervalResponses := []FreeBusyIntervalResponseDto{}
+	for _, interval := range intervals {
+		intervalResponses = append(intervalResponses, FreeBusyIntervalResponseDto{
+			Start:  interval.Start,
+			End:    interval.End,
+			Status: interval.Status,
+		})
+	}
+
+	return intervalResponses
+}
@@ -0,0 +1,34 @@
+package responseDto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/scheduler-prototype/dto"
+)
+
+type HoldResponseDto struct {
+	ID          uuid.UUID  `json:"id"`
+	UserId      string     `json:"user_id"`
+	EventId     string     `json:"event_id"`
+	Subject     string     `json:"subject"`
+	Start       time.Time  `json:"start"`
+	End         time.Time  `json:"end"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	Status      string     `json:"status"`
+	ConfirmedAt *time.Time `json:"confirmed_at"`
+}
+
+func NewHoldResponseDto(hold dto.HoldDto) HoldResponseDto {
+	return HoldResponseDto{
+		ID:          hold.ID,
+		UserId:      hold.UserId,
+		EventId:     hold.EventId,
+		Subject:     hold.Subject,
+		Start:       hold.StartTime,
+		End:         hold.EndTime,
+		ExpiresAt:   hold.ExpiresAt,
+		Status:      hold.Status,
+		ConfirmedAt: hold.ConfirmedAt,
+	}
+}
@@ -0,0 +1,46 @@
+package responseDto
+
+import (
+	"time"
+
+	"github.com/scheduler-prototype/freebusy"
+)
+
+// start and end are only set for conflicts caused by a busy interval
+type MeetingTimeConflictResponseDto struct {
+	Attendee string     `json:"attendee"`
+	Reason   string     `json:"reason"`
+	Start    *time.Time `json:"start,omitempty"`
+	End      *time.Time `json:"end,omitempty"`
+}
+
+type MeetingTimeSlotResponseDto struct {
+	Start                  time.Time                        `json:"start"`
+	End                    time.Time                        `json:"end"`
+	AvailabilityPercentage float64                          `json:"availability_percentage"`
+	AvailableAttendees     []string                         `json:"available_attendees"`
+	Conflicts              []MeetingTimeConflictResponseDto `json:"conflicts"`
+}
+
+func NewMeetingTimeSlotResponseDto(slot freebusy.Slot) MeetingTimeSlotResponseDto {
+	conflicts := []MeetingTimeConflictResponseDto{}
+	for _, conflict := range slot.Conflicts {
+		conflictResponse := MeetingTimeConflictResponseDto{
+			Attendee: conflict.AttendeeId,
+			Reason:   conflict.Reason,
+		}
+		if conflict.Interval != nil {
+			conflictResponse.Start = &conflict.Interval.Start
+			conflictResponse.End = &conflict.Interval.End
+		}
+		conflicts = append(conflicts, conflictResponse)
+	}
+
+	return MeetingTimeSlotResponseDto{
+		Start:                  slot.Start,
+		End:                    slot.End,
+		AvailabilityPercentage: slot.AvailabilityPercentage,
+		AvailableAttendees:     slot.AvailableAttendees,
+		Conflicts:              conflicts,
+	}
+}
@@ -0,0 +1,113 @@
+package responseDto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/scheduler-prototype/dto"
+)
+
+// status is the free/busy of the participant's synced calendar, unknown for participants that aren't synced
+type PollAvailabilityResponseDto struct {
+	EmailAddress string `json:"email_address"`
+	Status       string `json:"status"`
+}
+
+type PollOptionResponseDto struct {
+	ID           uuid.UUID                     `json:"id"`
+	Start        time.Time                     `json:"start"`
+	End          time.Time                     `json:"end"`
+	Yes          int                           `json:"yes"`
+	IfNeeded     int                           `json:"if_needed"`
+	No           int                           `json:"no"`
+	Availability []PollAvailabilityResponseDto `json:"availability"`
+}
+
+// invite_token is only returned when the poll is created, the invitee votes with it
+type PollInviteeResponseDto struct {
+	ID           uuid.UUID         `json:"id"`
+	EmailAddress string            `json:"email_address"`
+	Name         string            `json:"name"`
+	Votes        map[string]string `json:"votes"`
+	InviteToken  *string           `json:"invite_token,omitempty"`
+}
+
+// invitees are only listed to the organizer, an invitee sees their own votes instead
+type PollResponseDto struct {
+	ID                  uuid.UUID                `json:"id"`
+	Title               string                   `json:"title"`
+	Description         string                   `json:"description"`
+	LocationDisplayName *string                  `json:"location_display_name"`
+	IsOnlineMeeting     bool                     `json:"is_online_meeting"`
+	Status              string                   `json:"status"`
+	FinalOptionId       *uuid.UUID               `json:"final_option_id"`
+	EventId             *string                  `json:"event_id,omitempty"`
+	Options             []PollOptionResponseDto  `json:"options"`
+	Invitees            []PollInviteeResponseDto `json:"invitees,omitempty"`
+	MyVotes             map[string]string        `json:"my_votes,omitempty"`
+}
+
+// NewPollResponseDto tallies the votes of every option, availability is keyed by option id
+func NewPollResponseDto(poll dto.PollDto, options []dto.PollOptionDto, votes []dto.PollVoteDto, availability map[uuid.UUID][]PollAvailabilityResponseDto) PollResponseDto {
+	optionResponses := []PollOptionResponseDto{}
+	for _, option := range options {
+		optionResponse := PollOptionResponseDto{
+			ID:           option.ID,
+			Start:        option.StartTime,
+			End:          option.EndTime,
+			Availability: availability[option.ID],
+		}
+		if optionResponse.Availability == nil {
+			optionResponse.Availability = []PollAvailabilityResponseDto{}
+		}
+
+		for _, vote := range votes {
+			if vote.OptionId != option.ID {
+				continue
+			}
+			switch vote.Vote {
+			case dto.VoteYes:
+				optionResponse.Yes++
+			case dto.VoteIfNeeded:
+				optionResponse.IfNeeded++
+			case dto.VoteNo:
+				optionResponse.No++
+			}
+		}
+
+		optionResponses = append(optionResponses, optionResponse)
+	}
+
+	return PollResponseDto{
+		ID:                  poll.ID,
+		Title:               poll.Title,
+		Description:         poll.Description,
+		LocationDisplayName: poll.LocationDisplayName,
+		IsOnlineMeeting:     poll.IsOnlineMeeting,
+		Status:              poll.Status,
+		FinalOptionId:       poll.FinalOptionId,
+		EventId:             poll.EventId,
+		Options:             optionResponses,
+	}
+}
+
+// NewPollInviteeResponseDto lists the invitee's votes keyed by option id
+func NewPollInviteeResponseDto(invitee dto.PollInviteeDto, votes []dto.PollVoteDto, inviteToken *string) PollInviteeResponseDto {
+	return PollInviteeResponseDto{
+		ID:           invitee.ID,
+		EmailAddress: invitee.EmailAddress,
+		Name:         invitee.Name,
+		Votes:        NewPollVotesResponse(invitee, votes),
+		InviteToken:  inviteToken,
+	}
+}
+
+func NewPollVotesResponse(invitee dto.PollInviteeDto, votes []dto.PollVoteDto) map[string]string {
+	inviteeVotes := map[string]string{}
+	for _, vote := range votes {
+		if vote.InviteeId == invitee.ID {
+			inviteeVotes[vote.OptionId.String()] = vote.Vote
+		}
+	}
+	return inviteeVotes
+}
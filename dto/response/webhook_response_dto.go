@@ -0,0 +1,112 @@
+package responseDto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/scheduler-prototype/dto"
+)
+
+// secret is only set in the response to the creation, it's the key the payloads are signed with
+type WebhookResponseDto struct {
+	ID          uuid.UUID `json:"id"`
+	Url         string    `json:"url"`
+	Secret      *string   `json:"secret,omitempty"`
+	UserId      *string   `json:"user_id"`
+	EventTypes  []string  `json:"event_types"`
+	ChangeTypes []string  `json:"change_types"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type WebhookDeadLetterResponseDto struct {
+	ID         uuid.UUID  `json:"id"`
+	DeliveryId uuid.UUID  `json:"delivery_id"`
+	Cursor     int64      `json:"cursor"`
+	Attempts   int        `json:"attempts"`
+	LastError  *string    `json:"last_error"`
+	FailedAt   time.Time  `json:"failed_at"`
+	ReplayedAt *time.Time `json:"replayed_at"`
+}
+
+type WebhookDeliveryResponseDto struct {
+	ID            uuid.UUID `json:"id"`
+	Cursor        int64     `json:"cursor"`
+	Status        string    `json:"status"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+}
+
+// WebhookPayloadDto is the body posted to the webhooks, the event is left out so that a
+// consumer reads its current state from the api with its own permissions
+type WebhookPayloadDto struct {
+	DeliveryId    uuid.UUID `json:"delivery_id"`
+	Cursor        int64     `json:"cursor"`
+	ChangeType    string    `json:"change_type"`
+	EventType     string    `json:"event_type"`
+	EventId       uuid.UUID `json:"event_id"`
+	UserId        string    `json:"user_id"`
+	ICalUid       string    `json:"ical_uid"`
+	GraphEventId  string    `json:"graph_event_id"`
+	ChangedFields []string  `json:"changed_fields"`
+	ChangedAt     time.Time `json:"changed_at"`
+}
+
+func NewWebhookResponseDto(webhook dto.WebhookDto) WebhookResponseDto {
+	eventTypes := webhook.EventTypes
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	changeTypes := webhook.ChangeTypes
+	if changeTypes == nil {
+		changeTypes = []string{}
+	}
+
+	return WebhookResponseDto{
+		ID:          webhook.ID,
+		Url:         webhook.Url,
+		UserId:      webhook.UserId,
+		EventTypes:  eventTypes,
+		ChangeTypes: changeTypes,
+		CreatedAt:   webhook.CreatedAt,
+	}
+}
+
+func NewWebhookDeadLetterResponseDto(deadLetter dto.WebhookDeadLetterDto) WebhookDeadLetterResponseDto {
+	return WebhookDeadLetterResponseDto{
+		ID:         deadLetter.ID,
+		DeliveryId: deadLetter.DeliveryId,
+		Cursor:     deadLetter.ChangeId,
+		Attempts:   deadLetter.Attempts,
+		LastError:  deadLetter.LastError,
+		FailedAt:   deadLetter.FailedAt,
+		ReplayedAt: deadLetter.ReplayedAt,
+	}
+}
+
+func NewWebhookDeliveryResponseDto(delivery dto.WebhookDeliveryDto) WebhookDeliveryResponseDto {
+	return WebhookDeliveryResponseDto{
+		ID:            delivery.ID,
+		Cursor:        delivery.ChangeId,
+		Status:        delivery.Status,
+		NextAttemptAt: delivery.NextAttemptAt,
+	}
+}
+
+func NewWebhookPayloadDto(delivery dto.WebhookDeliveryDto, change dto.EventChangeDto) WebhookPayloadDto {
+	changedFields := change.ChangedFields
+	if changedFields == nil {
+		changedFields = []string{}
+	}
+
+	return WebhookPayloadDto{
+		DeliveryId:    delivery.ID,
+		Cursor:        change.ID,
+		ChangeType:    change.ChangeType,
+		EventType:     change.EventType,
+		EventId:       change.EventId,
+		UserId:        change.UserId,
+		ICalUid:       change.ICalUid,
+		GraphEventId:  change.GraphEventId,
+		ChangedFields: changedFields,
+		ChangedAt:     change.ChangedAt,
+	}
+}
@@ -7,12 +7,24 @@ import (
 )
 
 type UserDto struct {
-	ID                    uuid.UUID
-	UserId                uuid.UUID
-	CurrentDelta          *string
-	PreviousDelta         *string
-	CreatedAt             time.Time
-	UpdatedAt             time.Time
-	SubscriptionId        *string
-	SubscriptionExpiresAt *time.Time
+	ID                      uuid.UUID
+	UserId                  uuid.UUID
+	CurrentDelta            *string
+	PreviousDelta           *string
+	CreatedAt               time.Time
+	UpdatedAt               time.Time
+	SubscriptionId          *string
+	SubscriptionExpiresAt   *time.Time
+	MailboxTimezone         *string
+	WorkingHoursStart       *string
+	WorkingHoursEnd         *string
+	WorkingDays             []string
+	WorkingHoursTimezone    *string
+	SyncWindowPastDays      int
+	SyncWindowFutureDays    int
+	SyncWindowStart         *time.Time
+	SyncWindowEnd           *time.Time
+	PreviousSyncWindowStart *time.Time
+	PreviousSyncWindowEnd   *time.Time
+	EmailAddress            *string
 }
@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryDead      = "dead"
+)
+
+// empty EventTypes or ChangeTypes and a nil UserId match every change
+type WebhookDto struct {
+	ID          uuid.UUID
+	Url         string
+	Secret      string
+	UserId      *string
+	EventTypes  []string
+	ChangeTypes []string
+	IsActive    bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type WebhookDeliveryDto struct {
+	ID            uuid.UUID
+	WebhookId     uuid.UUID
+	ChangeId      int64
+	Status        string
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     *string
+	DeliveredAt   *time.Time
+	CreatedAt     time.Time
+}
+
+type WebhookDeadLetterDto struct {
+	ID         uuid.UUID
+	DeliveryId uuid.UUID
+	WebhookId  uuid.UUID
+	ChangeId   int64
+	Attempts   int
+	LastError  *string
+	FailedAt   time.Time
+	ReplayedAt *time.Time
+}
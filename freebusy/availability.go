@@ -0,0 +1,215 @@
+package freebusy
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+// reasons the availability rules block time for
+const (
+	OutsideWorkingHours = "outsideWorkingHours"
+	Holiday             = "holiday"
+	MinimumNotice       = "minimumNotice"
+)
+
+var ErrInvalidClock = errors.New("times must be formatted as 15:04 or 15:04:05")
+
+// ClockRange is a wall clock range within a day, times as 15:04:05
+type ClockRange struct {
+	Start string
+	End   string
+}
+
+// Availability is the time a user can be booked in, in their own zone
+type Availability struct {
+	Location    *time.Location
+	WeeklyHours map[time.Weekday][]ClockRange
+	// keyed by 2006-01-02, replaces the weekly hours of that date, no ranges means a day off
+	DateOverrides map[string][]ClockRange
+	// keyed by 2006-01-02
+	Holidays      map[string]bool
+	BufferBefore  time.Duration
+	BufferAfter   time.Duration
+	MinimumNotice time.Duration
+}
+
+// ParseClock returns the hour, minute and second of a 15:04 or 15:04:05 time
+func ParseClock(clock string) (int, int, int, error) {
+	if len(clock) == 5 {
+		clock += ":00"
+	}
+	// Graph sends working hours with fractional seconds
+	if len(clock) > 8 {
+		clock = clock[:8]
+	}
+
+	parsed, err := time.Parse("15:04:05", clock)
+	if err != nil {
+		return 0, 0, 0, ErrInvalidClock
+	}
+
+	return parsed.Hour(), parsed.Minute(), parsed.Second(), nil
+}
+
+// ParseDayOfWeek accepts day names in any case, e.g. monday
+func ParseDayOfWeek(dayOfWeek string) (time.Weekday, bool) {
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		if strings.EqualFold(weekday.String(), dayOfWeek) {
+			return weekday, true
+		}
+	}
+	return time.Sunday, false
+}
+
+func onDay(day time.Time, clock string, location *time.Location) (time.Time, error) {
+	hour, minute, second, err := ParseClock(clock)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, second, 0, location), nil
+}
+
+// WorkingIntervals returns the bookable ranges of every day touching the range, clipped to it
+func (a Availability) WorkingIntervals(start time.Time, end time.Time) []Interval {
+	intervals := []Interval{}
+
+	localStart := start.In(a.Location)
+	day := time.Date(localStart.Year(), localStart.Month(), localStart.Day(), 0, 0, 0, 0, a.Location)
+	for ; day.Before(end); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		if a.Holidays[date] {
+			continue
+		}
+
+		clockRanges, isOverridden := a.DateOverrides[date]
+		if !isOverridden {
+			clockRanges = a.WeeklyHours[day.Weekday()]
+		}
+
+		for _, clockRange := range clockRanges {
+			rangeStart, err := onDay(day, clockRange.Start, a.Location)
+			if err != nil {
+				continue
+			}
+			rangeEnd, err := onDay(day, clockRange.End, a.Location)
+			if err != nil {
+				continue
+			}
+			// 00:00 as an end time means the end of the day
+			if !rangeEnd.After(rangeStart) {
+				rangeEnd = day.AddDate(0, 0, 1)
+			}
+
+			if rangeStart.Before(start) {
+				rangeStart = start
+			}
+			if rangeEnd.After(end) {
+				rangeEnd = end
+			}
+			if rangeEnd.After(rangeStart) {
+				intervals = append(intervals, Interval{Start: rangeStart, End: rangeEnd, Status: Free})
+			}
+		}
+	}
+
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i].Start.Before(intervals[j].Start)
+	})
+
+	return intervals
+}
+
+// UnavailableIntervals returns the time of the range the rules block,
+// the status of each interval tells the reason: outsideWorkingHours, holiday or minimumNotice
+func (a Availability) UnavailableIntervals(start time.Time, end time.Time, now time.Time) []Interval {
+	unavailable := []Interval{}
+
+	// holidays first so the time isn't also reported as outside working hours
+	localStart := start.In(a.Location)
+	day := time.Date(localStart.Year(), localStart.Month(), localStart.Day(), 0, 0, 0, 0, a.Location)
+	for ; day.Before(end); day = day.AddDate(0, 0, 1) {
+		if !a.Holidays[day.Format("2006-01-02")] {
+			continue
+		}
+
+		holidayStart, holidayEnd := day, day.AddDate(0, 0, 1)
+		if holidayStart.Before(start) {
+			holidayStart = start
+		}
+		if holidayEnd.After(end) {
+			holidayEnd = end
+		}
+		unavailable = append(unavailable, Interval{Start: holidayStart, End: holidayEnd, Status: Holiday})
+	}
+
+	// the gaps between working intervals, leaving out the holidays
+	cursor := start
+	gaps := []Interval{}
+	for _, working := range a.WorkingIntervals(start, end) {
+		if working.Start.After(cursor) {
+			gaps = append(gaps, Interval{Start: cursor, End: working.Start, Status: OutsideWorkingHours})
+		}
+		if working.End.After(cursor) {
+			cursor = working.End
+		}
+	}
+	if end.After(cursor) {
+		gaps = append(gaps, Interval{Start: cursor, End: end, Status: OutsideWorkingHours})
+	}
+	for _, gap := range gaps {
+		unavailable = append(unavailable, subtract(gap, unavailable)...)
+	}
+
+	// nothing can be booked in the past or closer than the minimum notice
+	noticeEnd := now.Add(a.MinimumNotice)
+	if noticeEnd.After(start) {
+		if noticeEnd.After(end) {
+			noticeEnd = end
+		}
+		unavailable = append(unavailable, Interval{Start: start, End: noticeEnd, Status: MinimumNotice})
+	}
+
+	sort.Slice(unavailable, func(i, j int) bool {
+		return unavailable[i].Start.Before(unavailable[j].Start)
+	})
+
+	return unavailable
+}
+
+// ApplyBuffers widens the busy intervals by the buffers and merges them again
+func (a Availability) ApplyBuffers(intervals []Interval) []Interval {
+	buffered := []Interval{}
+	for _, interval := range intervals {
+		interval.Start = interval.Start.Add(-a.BufferBefore)
+		interval.End = interval.End.Add(a.BufferAfter)
+		buffered = append(buffered, interval)
+	}
+
+	return Merge(buffered)
+}
+
+// subtract returns the parts of the interval not covered by any of the others
+func subtract(interval Interval, others []Interval) []Interval {
+	remaining := []Interval{interval}
+	for _, other := range others {
+		next := []Interval{}
+		for _, part := range remaining {
+			if !other.Start.Before(part.End) || !other.End.After(part.Start) {
+				next = append(next, part)
+				continue
+			}
+			if other.Start.After(part.Start) {
+				next = append(next, Interval{Start: part.Start, End: other.Start, Status: part.Status})
+			}
+			if other.End.Before(part.End) {
+				next = append(next, Interval{Start: other.End, End: part.End, Status: part.Status})
+			}
+		}
+		remaining = next
+	}
+
+	return remaining
+}
@@ -0,0 +1,141 @@
+package freebusy
+
+import (
+	"sort"
+	"time"
+)
+
+// conflict reason for attendees whose free/busy could not be looked up
+const ScheduleUnavailable = "scheduleUnavailable"
+
+type Attendee struct {
+	// synced user id or email address, as it was requested
+	Id string
+	// merged busy intervals of the searched range
+	BusyIntervals []Interval
+	// nil when the attendee's working hours are not known
+	WorkingHours *WorkingHours
+	// time blocked by the attendee's availability rules, see Availability.UnavailableIntervals
+	UnavailableIntervals []Interval
+	// set when the attendee's free/busy could not be looked up
+	Unavailable bool
+}
+
+type Conflict struct {
+	AttendeeId string
+	Reason     string
+	// the busy interval behind the conflict, nil for working hours and unavailable schedules
+	Interval *Interval
+}
+
+type Slot struct {
+	Start              time.Time
+	End                time.Time
+	AvailableAttendees []string
+	// share of attendees that can attend, between 0 and 100
+	AvailabilityPercentage float64
+	Conflicts              []Conflict
+}
+
+type SlotSearch struct {
+	Start    time.Time
+	End      time.Time
+	Duration time.Duration
+	// distance between the starts of two candidate slots
+	Step time.Duration
+	// candidates with fewer attendees available are dropped
+	MinAttendeePercentage float64
+	// the attendees' working hours, or Override when set, must contain the slot
+	// -- attendees with availability rules have no working hours, their unavailable intervals always block
+	WorkingHoursOnly bool
+	Override         *WorkingHours
+	MaxCandidates    int
+}
+
+// blocking statuses keep the attendee from attending, the others are only reported
+var blockingStatuses = map[string]bool{
+	Busy: true,
+	Oof:  true,
+}
+
+// FindSlots returns the candidate slots of the search ranked by availability,
+// then by the number of soft conflicts, then by start time
+func FindSlots(attendees []Attendee, search SlotSearch) []Slot {
+	slots := []Slot{}
+	if len(attendees) == 0 || search.Duration <= 0 || search.Step <= 0 {
+		return slots
+	}
+
+	for slotStart := search.Start; !slotStart.Add(search.Duration).After(search.End); slotStart = slotStart.Add(search.Step) {
+		slotEnd := slotStart.Add(search.Duration)
+		slot := Slot{Start: slotStart, End: slotEnd, AvailableAttendees: []string{}, Conflicts: []Conflict{}}
+
+		for _, attendee := range attendees {
+			isAvailable := true
+
+			if attendee.Unavailable {
+				slot.Conflicts = append(slot.Conflicts, Conflict{AttendeeId: attendee.Id, Reason: ScheduleUnavailable})
+				continue
+			}
+
+			workingHours := attendee.WorkingHours
+			if search.Override != nil {
+				workingHours = search.Override
+			}
+			if search.WorkingHoursOnly && workingHours != nil && !workingHours.Contains(slotStart, slotEnd) {
+				slot.Conflicts = append(slot.Conflicts, Conflict{AttendeeId: attendee.Id, Reason: OutsideWorkingHours})
+				isAvailable = false
+			}
+
+			// the availability rules, holidays and the minimum notice block whatever the search asks for
+			for i := range attendee.UnavailableIntervals {
+				unavailableInterval := attendee.UnavailableIntervals[i]
+				if !unavailableInterval.Start.Before(slotEnd) || !unavailableInterval.End.After(slotStart) {
+					continue
+				}
+
+				slot.Conflicts = append(slot.Conflicts, Conflict{AttendeeId: attendee.Id, Reason: unavailableInterval.Status, Interval: &unavailableInterval})
+				isAvailable = false
+			}
+
+			for i := range attendee.BusyIntervals {
+				busyInterval := attendee.BusyIntervals[i]
+				if !busyInterval.Start.Before(slotEnd) || !busyInterval.End.After(slotStart) {
+					continue
+				}
+
+				slot.Conflicts = append(slot.Conflicts, Conflict{AttendeeId: attendee.Id, Reason: busyInterval.Status, Interval: &busyInterval})
+				if blockingStatuses[NormalizeStatus(busyInterval.Status)] {
+					isAvailable = false
+				}
+			}
+
+			if isAvailable {
+				slot.AvailableAttendees = append(slot.AvailableAttendees, attendee.Id)
+			}
+		}
+
+		slot.AvailabilityPercentage = float64(len(slot.AvailableAttendees)) * 100 / float64(len(attendees))
+		if slot.AvailabilityPercentage < search.MinAttendeePercentage {
+			continue
+		}
+
+		slots = append(slots, slot)
+	}
+
+	sort.SliceStable(slots, func(i, j int) bool {
+		if len(slots[i].AvailableAttendees) != len(slots[j].AvailableAttendees) {
+			return len(slots[i].AvailableAttendees) > len(slots[j].AvailableAttendees)
+		}
+		if len(slots[i].Conflicts) != len(slots[j].Conflicts) {
+			return len(slots[i].Conflicts) < len(slots[j].Conflicts)
+		}
+		return slots[i].Start.Before(slots[j].Start)
+	})
+
+	if search.MaxCandidates > 0 && len(slots) > search.MaxCandidates {
+		slots = slots[:search.MaxCandidates]
+	}
+
+	return slots
+}
@@ -0,0 +1,176 @@
+package freebusy
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+// statuses as Graph reports them in showAs and getSchedule
+const (
+	Free             = "free"
+	Tentative        = "tentative"
+	Busy             = "busy"
+	Oof              = "oof"
+	WorkingElsewhere = "workingElsewhere"
+	Unknown          = "unknown"
+)
+
+const (
+	DefaultInterval = 30 * time.Minute
+	MinInterval     = 5 * time.Minute
+	MaxInterval     = 24 * time.Hour
+	// the longest range getSchedule accepts, the availability view holds one entry per slot of it
+	MaxRange = 62 * 24 * time.Hour
+)
+
+var ErrInvalidInterval = errors.New("interval must be between 5 and 1440 minutes")
+var ErrEmptyRange = errors.New("end must be after start")
+var ErrRangeTooLong = errors.New("range must not be longer than 62 days")
+
+// when intervals overlap, the status ranked higher wins
+var statusRanks = map[string]int{
+	Free:             0,
+	Tentative:        1,
+	WorkingElsewhere: 2,
+	Busy:             3,
+	Oof:              4,
+}
+
+// digits used by Graph's availabilityView
+var availabilityViewCodes = map[string]byte{
+	Free:             '0',
+	Tentative:        '1',
+	Busy:             '2',
+	Oof:              '3',
+	WorkingElsewhere: '4',
+}
+
+type Interval struct {
+	Start  time.Time
+	End    time.Time
+	Status string
+}
+
+// NormalizeStatus maps a showAs value onto a known status, anything unknown blocks time like busy
+func NormalizeStatus(status string) string {
+	if _, ok := statusRanks[status]; ok {
+		return status
+	}
+	return Busy
+}
+
+// StatusOf returns the highest ranked status of the intervals overlapping the range, free when none do
+func StatusOf(intervals []Interval, start time.Time, end time.Time) string {
+	status := Free
+	for _, interval := range intervals {
+		if !interval.Start.Before(end) || !interval.End.After(start) {
+			continue
+		}
+		if intervalStatus := NormalizeStatus(interval.Status); statusRanks[intervalStatus] > statusRanks[status] {
+			status = intervalStatus
+		}
+	}
+	return status
+}
+
+// Merge combines overlapping and touching intervals, free intervals are dropped
+// -- overlapping intervals are split at their boundaries, only the overlap takes the strongest status
+// so a tentative morning with a busy half hour in it stays tentative around that half hour
+func Merge(intervals []Interval) []Interval {
+	valid := []Interval{}
+	boundaries := []time.Time{}
+	for _, interval := range intervals {
+		interval.Status = NormalizeStatus(interval.Status)
+		if interval.Status == Free || !interval.End.After(interval.Start) {
+			continue
+		}
+		valid = append(valid, interval)
+		boundaries = append(boundaries, interval.Start, interval.End)
+	}
+
+	sort.Slice(boundaries, func(i, j int) bool {
+		return boundaries[i].Before(boundaries[j])
+	})
+
+	merged := []Interval{}
+	for i := 0; i+1 < len(boundaries); i++ {
+		segmentStart, segmentEnd := boundaries[i], boundaries[i+1]
+		if !segmentEnd.After(segmentStart) {
+			continue
+		}
+
+		status := StatusOf(valid, segmentStart, segmentEnd)
+		if status == Free {
+			continue
+		}
+
+		last := len(merged) - 1
+		if last >= 0 && merged[last].Status == status && !segmentStart.After(merged[last].End) {
+			merged[last].End = segmentEnd
+			continue
+		}
+		merged = append(merged, Interval{Start: segmentStart, End: segmentEnd, Status: status})
+	}
+
+	return merged
+}
+
+// Clip cuts the intervals to the range, dropping the ones outside of it
+func Clip(intervals []Interval, start time.Time, end time.Time) []Interval {
+	clipped := []Interval{}
+	for _, interval := range intervals {
+		if interval.Start.Before(start) {
+			interval.Start = start
+		}
+		if interval.End.After(end) {
+			interval.End = end
+		}
+		if interval.End.After(interval.Start) {
+			clipped = append(clipped, interval)
+		}
+	}
+
+	return clipped
+}
+
+// AvailabilityView returns one digit per slot of the range, like Graph's getSchedule
+// 0 free, 1 tentative, 2 busy, 3 out of office, 4 working elsewhere
+func AvailabilityView(intervals []Interval, start time.Time, end time.Time, interval time.Duration) string {
+	var view strings.Builder
+	for slotStart := start; slotStart.Before(end); slotStart = slotStart.Add(interval) {
+		slotEnd := slotStart.Add(interval)
+
+		status := Free
+		for _, busyInterval := range intervals {
+			if busyInterval.Start.Before(slotEnd) && busyInterval.End.After(slotStart) &&
+				statusRanks[NormalizeStatus(busyInterval.Status)] > statusRanks[status] {
+				status = NormalizeStatus(busyInterval.Status)
+			}
+		}
+
+		view.WriteByte(availabilityViewCodes[status])
+	}
+
+	return view.String()
+}
+
+// ValidateInterval checks the slot size against the limits Graph accepts
+func ValidateInterval(interval time.Duration) error {
+	if interval < MinInterval || interval > MaxInterval {
+		return ErrInvalidInterval
+	}
+	return nil
+}
+
+// ValidateRange checks the range is not empty and within the limit Graph accepts
+func ValidateRange(start time.Time, end time.Time) error {
+	if !end.After(start) {
+		return ErrEmptyRange
+	}
+	if end.Sub(start) > MaxRange {
+		return ErrRangeTooLong
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package freebusy
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func at(hour int, minute int) time.Time {
+	return time.Date(2023, 10, 20, hour, minute, 0, 0, time.UTC)
+}
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals []Interval
+		want      []Interval
+	}{
+		{
+			name: "no intervals",
+			want: []Interval{},
+		},
+		{
+			name: "touching intervals of the same status",
+			intervals: []Interval{
+				{Start: at(9, 0), End: at(10, 0), Status: Busy},
+				{Start: at(10, 0), End: at(11, 0), Status: Busy},
+			},
+			want: []Interval{{Start: at(9, 0), End: at(11, 0), Status: Busy}},
+		},
+		{
+			name: "busy half hour within a tentative morning",
+			intervals: []Interval{
+				{Start: at(9, 0), End: at(13, 0), Status: Tentative},
+				{Start: at(11, 0), End: at(11, 30), Status: Busy},
+			},
+			want: []Interval{
+				{Start: at(9, 0), End: at(11, 0), Status: Tentative},
+				{Start: at(11, 0), End: at(11, 30), Status: Busy},
+				{Start: at(11, 30), End: at(13, 0), Status: Tentative},
+			},
+		},
+		{
+			name: "partly overlapping statuses",
+			intervals: []Interval{
+				{Start: at(9, 0), End: at(10, 30), Status: Busy},
+				{Start: at(10, 0), End: at(12, 0), Status: Oof},
+			},
+			want: []Interval{
+				{Start: at(9, 0), End: at(10, 0), Status: Busy},
+				{Start: at(10, 0), End: at(12, 0), Status: Oof},
+			},
+		},
+		{
+			name: "free and empty intervals are dropped",
+			intervals: []Interval{
+				{Start: at(9, 0), End: at(10, 0), Status: Free},
+				{Start: at(11, 0), End: at(11, 0), Status: Busy},
+				{Start: at(12, 0), End: at(13, 0), Status: Busy},
+			},
+			want: []Interval{{Start: at(12, 0), End: at(13, 0), Status: Busy}},
+		},
+		{
+			name: "gap between intervals",
+			intervals: []Interval{
+				{Start: at(9, 0), End: at(10, 0), Status: Busy},
+				{Start: at(11, 0), End: at(12, 0), Status: Busy},
+			},
+			want: []Interval{
+				{Start: at(9, 0), End: at(10, 0), Status: Busy},
+				{Start: at(11, 0), End: at(12, 0), Status: Busy},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Merge(tt.intervals)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Merge() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
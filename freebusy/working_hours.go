@@ -0,0 +1,43 @@
+package freebusy
+
+import (
+	"strings"
+	"time"
+)
+
+type WorkingHours struct {
+	// wall clock times as 15:04:05
+	Start string
+	End   string
+	// lowercase day names as Graph sends them, e.g. monday
+	DaysOfWeek []string
+	Location   *time.Location
+}
+
+// Contains reports whether the range falls inside a single working day
+func (w WorkingHours) Contains(start time.Time, end time.Time) bool {
+	localStart := start.In(w.Location)
+
+	isWorkingDay := false
+	for _, dayOfWeek := range w.DaysOfWeek {
+		if strings.EqualFold(dayOfWeek, localStart.Weekday().String()) {
+			isWorkingDay = true
+			break
+		}
+	}
+	if !isWorkingDay {
+		return false
+	}
+
+	dayStart, err := onDay(localStart, w.Start, w.Location)
+	if err != nil {
+		return false
+	}
+
+	dayEnd, err := onDay(localStart, w.End, w.Location)
+	if err != nil {
+		return false
+	}
+
+	return !start.Before(dayStart) && !end.After(dayEnd)
+}
@@ -0,0 +1,423 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/scheduler-prototype/dto"
+	requestDto "github.com/scheduler-prototype/dto/request"
+	responseDto "github.com/scheduler-prototype/dto/response"
+	"github.com/scheduler-prototype/freebusy"
+	"github.com/scheduler-prototype/timezone"
+	"github.com/scheduler-prototype/utility"
+)
+
+// used when the mailbox has no working hours to seed from
+var defaultWorkingDays = []string{"monday", "tuesday", "wednesday", "thursday", "friday"}
+
+const (
+	defaultWorkingHoursStart = "09:00:00"
+	defaultWorkingHoursEnd   = "17:00:00"
+)
+
+// seedAvailability creates the user's availability rules from their mailbox working hours,
+// rules that already exist were possibly edited and are left alone
+func (h *Handler) seedAvailability(userDto dto.UserDto) error {
+	_, err := h.repo.GetAvailabilityRulesByUserId(&userDto.UserId)
+	if err == nil {
+		return nil
+	}
+	if err != utility.ErrNotFound {
+		return err
+	}
+
+	timeZone := "UTC"
+	for _, candidate := range []*string{userDto.WorkingHoursTimezone, userDto.MailboxTimezone} {
+		if candidate == nil {
+			continue
+		}
+		if _, err := timezone.LoadLocation(*candidate); err == nil {
+			timeZone = *candidate
+			break
+		}
+	}
+
+	rules := &dto.AvailabilityRulesDto{
+		UserId:    userDto.UserId,
+		TimeZone:  timeZone,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	err = h.repo.UpsertAvailabilityRules(rules)
+	if err != nil {
+		return err
+	}
+
+	workingDays := defaultWorkingDays
+	workingHoursStart := defaultWorkingHoursStart
+	workingHoursEnd := defaultWorkingHoursEnd
+	if userDto.WorkingHoursStart != nil && userDto.WorkingHoursEnd != nil && len(userDto.WorkingDays) > 0 {
+		workingDays = userDto.WorkingDays
+		workingHoursStart = *userDto.WorkingHoursStart
+		workingHoursEnd = *userDto.WorkingHoursEnd
+	}
+
+	weeklyHours := []dto.AvailabilityWeeklyHoursDto{}
+	for _, workingDay := range workingDays {
+		weeklyHours = append(weeklyHours, dto.AvailabilityWeeklyHoursDto{
+			UserId:    userDto.UserId,
+			DayOfWeek: workingDay,
+			StartTime: workingHoursStart,
+			EndTime:   workingHoursEnd,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		})
+	}
+
+	return h.repo.ReplaceAvailabilityWeeklyHours(&userDto.UserId, weeklyHours)
+}
+
+// userAvailability loads the user's availability rules for the range, nil when the user has none
+func (h *Handler) userAvailability(userUuid uuid.UUID, startTime time.Time, endTime time.Time) (*freebusy.Availability, error) {
+	rules, err := h.repo.GetAvailabilityRulesByUserId(&userUuid)
+	if err != nil {
+		if err == utility.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	location, err := timezone.LoadLocation(rules.TimeZone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	availability := &freebusy.Availability{
+		Location:      location,
+		WeeklyHours:   map[time.Weekday][]freebusy.ClockRange{},
+		DateOverrides: map[string][]freebusy.ClockRange{},
+		Holidays:      map[string]bool{},
+		BufferBefore:  time.Duration(rules.BufferBeforeMinutes) * time.Minute,
+		BufferAfter:   time.Duration(rules.BufferAfterMinutes) * time.Minute,
+		MinimumNotice: time.Duration(rules.MinimumNoticeMinutes) * time.Minute,
+	}
+
+	weeklyHours, err := h.repo.GetAvailabilityWeeklyHoursByUserId(&userUuid)
+	if err != nil {
+		return nil, err
+	}
+	for _, hours := range weeklyHours {
+		if weekday, ok := freebusy.ParseDayOfWeek(hours.DayOfWeek); ok {
+			availability.WeeklyHours[weekday] = append(availability.WeeklyHours[weekday], freebusy.ClockRange{Start: hours.StartTime, End: hours.EndTime})
+		}
+	}
+
+	// dates are local to the user, a day either side covers any zone difference
+	overrides, err := h.repo.GetAvailabilityOverridesByUserIdAndDateRange(&userUuid, startTime.AddDate(0, 0, -1), endTime.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	for _, override := range overrides {
+		date := override.Date.Format("2006-01-02")
+		if override.IsHoliday {
+			availability.Holidays[date] = true
+			continue
+		}
+
+		clockRanges := availability.DateOverrides[date]
+		if override.StartTime != nil && override.EndTime != nil {
+			clockRanges = append(clockRanges, freebusy.ClockRange{Start: *override.StartTime, End: *override.EndTime})
+		}
+		availability.DateOverrides[date] = clockRanges
+	}
+
+	return availability, nil
+}
+
+func (h *Handler) GetUserAvailability(w http.ResponseWriter, r *http.Request) {
+	userUuid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	availabilityResponse, err := h.availabilityResponse(userUuid)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrNotFound {
+			status = http.StatusNotFound
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(availabilityResponse)
+}
+
+func (h *Handler) UpdateUserAvailability(w http.ResponseWriter, r *http.Request) {
+	userUuid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	req := &requestDto.UpdateAvailabilityDto{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if err := validateAvailability(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	_, err = h.repo.GetUserByUserId(&userUuid)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrNotFound {
+			status = http.StatusNotFound
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	rules := &dto.AvailabilityRulesDto{
+		UserId:               userUuid,
+		TimeZone:             req.TimeZone,
+		BufferBeforeMinutes:  req.BufferBeforeMinutes,
+		BufferAfterMinutes:   req.BufferAfterMinutes,
+		MinimumNoticeMinutes: req.MinimumNoticeMinutes,
+		CreatedAt:            time.Now(),
+		UpdatedAt:            time.Now(),
+	}
+	err = h.repo.UpsertAvailabilityRules(rules)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	weeklyHours := []dto.AvailabilityWeeklyHoursDto{}
+	for _, hours := range req.WeeklyHours {
+		weeklyHours = append(weeklyHours, dto.AvailabilityWeeklyHoursDto{
+			UserId:    userUuid,
+			DayOfWeek: hours.DayOfWeek,
+			StartTime: hours.StartTime,
+			EndTime:   hours.EndTime,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		})
+	}
+	err = h.repo.ReplaceAvailabilityWeeklyHours(&userUuid, weeklyHours)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	availabilityResponse, err := h.availabilityResponse(userUuid)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(availabilityResponse)
+}
+
+func (h *Handler) CreateUserAvailabilityOverride(w http.ResponseWriter, r *http.Request) {
+	userUuid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	req := &requestDto.CreateAvailabilityOverrideDto{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	override, err := newAvailabilityOverrideDto(userUuid, req)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	_, err = h.repo.GetAvailabilityRulesByUserId(&userUuid)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrNotFound {
+			status = http.StatusNotFound
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	err = h.repo.CreateAvailabilityOverride(override)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(responseDto.NewAvailabilityOverrideResponseDto(*override))
+}
+
+func (h *Handler) DeleteUserAvailabilityOverride(w http.ResponseWriter, r *http.Request) {
+	userUuid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	overrideUuid, err := uuid.Parse(chi.URLParam(r, "overrideId"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	err = h.repo.DeleteAvailabilityOverride(&userUuid, &overrideUuid)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrNotFound {
+			status = http.StatusNotFound
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) availabilityResponse(userUuid uuid.UUID) (responseDto.AvailabilityResponseDto, error) {
+	rules, err := h.repo.GetAvailabilityRulesByUserId(&userUuid)
+	if err != nil {
+		return responseDto.AvailabilityResponseDto{}, err
+	}
+
+	weeklyHours, err := h.repo.GetAvailabilityWeeklyHoursByUserId(&userUuid)
+	if err != nil {
+		return responseDto.AvailabilityResponseDto{}, err
+	}
+
+	overrides, err := h.repo.GetAvailabilityOverridesByUserId(&userUuid)
+	if err != nil {
+		return responseDto.AvailabilityResponseDto{}, err
+	}
+
+	return responseDto.NewAvailabilityResponseDto(rules, weeklyHours, overrides), nil
+}
+
+func validateAvailability(req *requestDto.UpdateAvailabilityDto) error {
+	if _, err := timezone.LoadLocation(req.TimeZone); err != nil {
+		return errors.New("time_zone: " + err.Error())
+	}
+
+	if req.BufferBeforeMinutes < 0 || req.BufferAfterMinutes < 0 || req.MinimumNoticeMinutes < 0 {
+		return errors.New("buffers and minimum notice can't be negative")
+	}
+
+	for _, hours := range req.WeeklyHours {
+		if _, ok := freebusy.ParseDayOfWeek(hours.DayOfWeek); !ok {
+			return errors.New("weekly_hours: unknown day_of_week " + hours.DayOfWeek)
+		}
+		if err := validateClockRange(hours.StartTime, hours.EndTime); err != nil {
+			return errors.New("weekly_hours: " + err.Error())
+		}
+	}
+
+	return nil
+}
+
+// validateClockRange checks both times and that the range isn't empty, 00:00 as end time is the end of the day
+func validateClockRange(startTime string, endTime string) error {
+	startHour, startMinute, startSecond, err := freebusy.ParseClock(startTime)
+	if err != nil {
+		return err
+	}
+
+	endHour, endMinute, endSecond, err := freebusy.ParseClock(endTime)
+	if err != nil {
+		return err
+	}
+
+	start := startHour*3600 + startMinute*60 + startSecond
+	end := endHour*3600 + endMinute*60 + endSecond
+	if end != 0 && end <= start {
+		return errors.New("end_time must be after start_time")
+	}
+
+	return nil
+}
+
+func newAvailabilityOverrideDto(userUuid uuid.UUID, req *requestDto.CreateAvailabilityOverrideDto) (*dto.AvailabilityOverrideDto, error) {
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return nil, errors.New("date: " + err.Error())
+	}
+
+	if (req.StartTime == nil) != (req.EndTime == nil) {
+		return nil, errors.New("start_time and end_time must be set together")
+	}
+	if req.StartTime != nil {
+		if req.IsHoliday {
+			return nil, errors.New("holidays block the whole date and can't have times")
+		}
+		if err := validateClockRange(*req.StartTime, *req.EndTime); err != nil {
+			return nil, err
+		}
+	}
+
+	return &dto.AvailabilityOverrideDto{
+		UserId:    userUuid,
+		Date:      date,
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+		IsHoliday: req.IsHoliday,
+		Name:      req.Name,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}, nil
+}
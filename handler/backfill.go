@@ -0,0 +1,244 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	graphmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/scheduler-prototype/dto"
+	requestDto "github.com/scheduler-prototype/dto/request"
+	responseDto "github.com/scheduler-prototype/dto/response"
+	"github.com/scheduler-prototype/mgraph"
+	"github.com/scheduler-prototype/utility"
+)
+
+const (
+	// pause between chunks to stay clear of Graph throttling limits
+	backfillChunkDelay = time.Second
+	// first wait after being throttled, doubled on every retry
+	backfillRetryDelay = 5 * time.Second
+	backfillMaxRetries = 5
+	// a running job that hasn't made progress for this long is considered interrupted
+	backfillStaleAfter = 10 * time.Minute
+)
+
+// StartBackfill resumes the user's latest backfill that didn't complete, or creates a new one
+// -- backfills walk backwards from the start of the user's sync window and never touch the delta link
+func (h *Handler) StartBackfill(userId uuid.UUID, until time.Time, chunkUnit string, chunkSize int) (*dto.BackfillJobDto, error) {
+	if chunkUnit != "month" && chunkUnit != "week" && chunkUnit != "day" {
+		return nil, errors.New("chunk_unit must be one of month, week or day")
+	}
+	if chunkSize < 1 {
+		return nil, errors.New("chunk_size must be at least 1")
+	}
+
+	userDto, err := h.repo.GetUserByUserId(&userId)
+	if err != nil {
+		return nil, err
+	}
+
+	latestJob, err := h.repo.GetLatestBackfillJobByUserId(&userId)
+	if err != nil && err != utility.ErrNotFound {
+		return nil, err
+	}
+
+	if err == nil && latestJob.Status != dto.BackfillCompleted {
+		if latestJob.Status == dto.BackfillRunning && time.Since(latestJob.UpdatedAt) < backfillStaleAfter {
+			return nil, utility.ErrConflict
+		}
+
+		// resume from the last completed chunk
+		latestJob.Status = dto.BackfillRunning
+		latestJob.LastError = nil
+		latestJob.UpdatedAt = time.Now()
+		err = h.repo.UpdateBackfillJobProgress(&latestJob)
+		if err != nil {
+			return nil, err
+		}
+		return &latestJob, nil
+	}
+
+	cursor := time.Now().UTC()
+	if userDto.SyncWindowStart != nil {
+		cursor = *userDto.SyncWindowStart
+	}
+
+	backfillJob := &dto.BackfillJobDto{
+		UserId:        userId,
+		ChunkUnit:     chunkUnit,
+		ChunkSize:     chunkSize,
+		BackfillUntil: until.UTC(),
+		Cursor:        cursor,
+		Status:        dto.BackfillRunning,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+
+	err = h.repo.CreateBackfillJob(backfillJob)
+	if err != nil {
+		return nil, err
+	}
+
+	return backfillJob, nil
+}
+
+// RunBackfill syncs the job one chunk at a time, saving progress after each chunk
+func (h *Handler) RunBackfill(backfillJob *dto.BackfillJobDto) error {
+	for backfillJob.Cursor.After(backfillJob.BackfillUntil) {
+		chunkStart := previousChunkStart(backfillJob.Cursor, backfillJob.ChunkUnit, backfillJob.ChunkSize)
+		if chunkStart.Before(backfillJob.BackfillUntil) {
+			chunkStart = backfillJob.BackfillUntil
+		}
+
+		eventsSynced, err := h.backfillChunk(backfillJob.UserId, chunkStart, backfillJob.Cursor)
+		if err != nil {
+			errorMessage := err.Error()
+			backfillJob.Status = dto.BackfillFailed
+			backfillJob.LastError = &errorMessage
+			backfillJob.UpdatedAt = time.Now()
+			if updateErr := h.repo.UpdateBackfillJobProgress(backfillJob); updateErr != nil {
+				log.Printf("backfill %s: could not save failure: %s", backfillJob.ID, updateErr)
+			}
+			return err
+		}
+
+		backfillJob.Cursor = chunkStart
+		backfillJob.EventsSynced += eventsSynced
+		backfillJob.UpdatedAt = time.Now()
+		err = h.repo.UpdateBackfillJobProgress(backfillJob)
+		if err != nil {
+			return err
+		}
+		log.Printf("backfill %s: synced %d events back to %s", backfillJob.ID, eventsSynced, chunkStart.Format(time.RFC3339))
+
+		time.Sleep(backfillChunkDelay)
+	}
+
+	backfillJob.Status = dto.BackfillCompleted
+	backfillJob.UpdatedAt = time.Now()
+	return h.repo.UpdateBackfillJobProgress(backfillJob)
+}
+
+func (h *Handler) backfillChunk(userId uuid.UUID, chunkStart time.Time, chunkEnd time.Time) (int, error) {
+	var events []graphmodels.Eventable
+	var err error
+
+	retryDelay := backfillRetryDelay
+	for attempt := 0; ; attempt++ {
+		events, err = h.client.GetCalendarViewRange(userId.String(), chunkStart.Format(time.RFC3339), chunkEnd.Format(time.RFC3339))
+		if err != mgraph.ErrThrottled || attempt == backfillMaxRetries {
+			break
+		}
+
+		log.Printf("backfill: throttled, retrying in %s", retryDelay)
+		time.Sleep(retryDelay)
+		retryDelay *= 2
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	// events are matched on their iCalUId so re-running a chunk doesn't duplicate rows
+	syncRunId := uuid.New()
+	for _, event := range events {
+		err := h.storeEventFrom(event, userId.String(), dto.EventPayloadDto{Source: dto.EventPayloadFromBackfill, SyncRunId: &syncRunId})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(events), nil
+}
+
+func previousChunkStart(cursor time.Time, chunkUnit string, chunkSize int) time.Time {
+	switch chunkUnit {
+	case "day":
+		return cursor.AddDate(0, 0, -chunkSize)
+	case "week":
+		return cursor.AddDate(0, 0, -7*chunkSize)
+	default:
+		return cursor.AddDate(0, -chunkSize, 0)
+	}
+}
+
+func (h *Handler) PostBackfill(w http.ResponseWriter, r *http.Request) {
+	userUuid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	req := &requestDto.BackfillDto{ChunkUnit: "month", ChunkSize: 1}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	until, err := time.Parse("2006-01-02", req.Until)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	backfillJob, err := h.StartBackfill(userUuid, until, req.ChunkUnit, req.ChunkSize)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrNotFound {
+			status = http.StatusNotFound
+		} else if err == utility.ErrConflict {
+			status = http.StatusConflict
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// the backfill keeps running after the response, progress is available through GetBackfill
+	go func(backfillJob dto.BackfillJobDto) {
+		if err := h.RunBackfill(&backfillJob); err != nil {
+			log.Printf("backfill %s: %s", backfillJob.ID, err)
+		}
+	}(*backfillJob)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(responseDto.NewBackfillJobResponseDto(*backfillJob))
+}
+
+func (h *Handler) GetBackfill(w http.ResponseWriter, r *http.Request) {
+	userUuid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	backfillJob, err := h.repo.GetLatestBackfillJobByUserId(&userUuid)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrNotFound {
+			status = http.StatusNotFound
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(responseDto.NewBackfillJobResponseDto(backfillJob))
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/scheduler-prototype/timezone"
+)
+
+// BackfillIanaTimezones fills iana_timezone of the rows stored before the column existed and returns how many it fixed
+// -- those rows had their start and end inserted as naive wall clock times read as UTC,
+// they're reinterpreted in the zone they were given in, all day events also get their dates
+func (h *Handler) BackfillIanaTimezones() (int, error) {
+	events, err := h.repo.GetEventsWithoutIanaTimezone()
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range events {
+		event := &events[i]
+
+		event.IanaTimezone, err = timezone.ToIANA(event.Timezone)
+		if err != nil {
+			event.IanaTimezone = "Etc/UTC"
+		}
+		location, err := time.LoadLocation(event.IanaTimezone)
+		if err != nil {
+			return 0, err
+		}
+
+		event.StartTime = wallClockIn(event.StartTime, location)
+		event.EndTime = wallClockIn(event.EndTime, location)
+		if event.IsAllDay {
+			startDate := dateOf(event.StartTime.In(location))
+			endDate := dateOf(event.EndTime.In(location))
+			event.StartDate = &startDate
+			event.EndDate = &endDate
+		}
+
+		err = h.repo.UpdateIanaTimezoneByEvent(event)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(events), nil
+}
+
+// wallClockIn reads the UTC clock of t as a time in location
+func wallClockIn(t time.Time, location *time.Location) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), location)
+}
+
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,578 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/scheduler-prototype/dto"
+	requestDto "github.com/scheduler-prototype/dto/request"
+	responseDto "github.com/scheduler-prototype/dto/response"
+	"github.com/scheduler-prototype/freebusy"
+	"github.com/scheduler-prototype/utility"
+)
+
+const (
+	defaultBookingWindowDays = 60
+	maxBookingWindowDays     = 365
+)
+
+var bookingSlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+func (h *Handler) CreateBookingType(w http.ResponseWriter, r *http.Request) {
+	userUuid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	req := &requestDto.CreateBookingTypeDto{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	bookingType, members, err := newBookingTypeDto(userUuid, req)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// slots are computed from the availability rules, the user needs them before taking bookings
+	_, err = h.repo.GetAvailabilityRulesByUserId(&userUuid)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrNotFound {
+			status = http.StatusNotFound
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	err = h.validateBookingTypeMembers(bookingType, members)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, errInvalidBookingMember) {
+			status = http.StatusBadRequest
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	_, err = h.repo.GetBookingTypeBySlug(bookingType.Slug)
+	if err == nil {
+		w.WriteHeader(http.StatusConflict)
+		response := map[string]string{"error": "slug: " + utility.ErrConflict.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+	if err != utility.ErrNotFound {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	err = h.repo.CreateBookingType(bookingType, members)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(responseDto.NewBookingTypeResponseDto(*bookingType, members))
+}
+
+func (h *Handler) GetBookingTypes(w http.ResponseWriter, r *http.Request) {
+	userUuid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	bookingTypes, err := h.repo.GetBookingTypesByUserId(&userUuid)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	bookingTypeResponses := []responseDto.BookingTypeResponseDto{}
+	for _, bookingType := range bookingTypes {
+		members, err := h.repo.GetBookingTypeMembersByBookingTypeId(&bookingType.ID)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			response := map[string]string{"error": err.Error()}
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		bookingTypeResponses = append(bookingTypeResponses, responseDto.NewBookingTypeResponseDto(bookingType, members))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(bookingTypeResponses)
+}
+
+// DeleteBookingType only deactivates the booking type, guests can still manage the bookings made with it
+func (h *Handler) DeleteBookingType(w http.ResponseWriter, r *http.Request) {
+	userUuid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	bookingTypeUuid, err := uuid.Parse(chi.URLParam(r, "bookingTypeId"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	err = h.repo.DeactivateBookingType(&userUuid, &bookingTypeUuid)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrNotFound {
+			status = http.StatusNotFound
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetBookingAssignments lists who the bookings of a team booking type were assigned to, most recent first
+func (h *Handler) GetBookingAssignments(w http.ResponseWriter, r *http.Request) {
+	userUuid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	bookingTypeUuid, err := uuid.Parse(chi.URLParam(r, "bookingTypeId"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	bookingType, err := h.repo.GetBookingTypeById(&bookingTypeUuid)
+	if err == nil && bookingType.UserId != userUuid {
+		err = utility.ErrNotFound
+	}
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrNotFound {
+			status = http.StatusNotFound
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	assignments, err := h.repo.GetBookingAssignmentsByBookingTypeId(&bookingType.ID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	assignmentResponses := []responseDto.BookingAssignmentResponseDto{}
+	for _, assignment := range assignments {
+		assignmentResponses = append(assignmentResponses, responseDto.NewBookingAssignmentResponseDto(assignment))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(assignmentResponses)
+}
+
+// GetPublicBookingType is the booking page of a slug, open to anyone with the link
+func (h *Handler) GetPublicBookingType(w http.ResponseWriter, r *http.Request) {
+	bookingType, err := h.activeBookingType(chi.URLParam(r, "slug"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrNotFound {
+			status = http.StatusNotFound
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(responseDto.NewPublicBookingTypeResponseDto(bookingType))
+}
+
+// GetBookingSlots lists the bookable slots of the range, start and end default to the booking window
+func (h *Handler) GetBookingSlots(w http.ResponseWriter, r *http.Request) {
+	bookingType, err := h.activeBookingType(chi.URLParam(r, "slug"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrNotFound {
+			status = http.StatusNotFound
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	now := time.Now()
+	startTime := now
+	endTime := now.AddDate(0, 0, bookingType.BookingWindowDays)
+
+	if startParam := r.URL.Query().Get("start"); startParam != "" {
+		startTime, err = parseTimeParam(startParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			response := map[string]string{"error": "start: " + err.Error()}
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+	}
+
+	if endParam := r.URL.Query().Get("end"); endParam != "" {
+		endTime, err = parseTimeParam(endParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			response := map[string]string{"error": "end: " + err.Error()}
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+	}
+
+	memberIds, err := h.bookingTypeMemberIds(bookingType)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	slots, err := h.bookingSlots(bookingType, memberIds, startTime, endTime, now, nil)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	slotResponses := []responseDto.BookingSlotResponseDto{}
+	for _, slot := range slots {
+		slotResponses = append(slotResponses, responseDto.BookingSlotResponseDto{Start: slot.Start, End: slot.End})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(slotResponses)
+}
+
+// activeBookingType finds the booking type of a slug, deactivated booking types are not found
+func (h *Handler) activeBookingType(slug string) (dto.BookingTypeDto, error) {
+	bookingType, err := h.repo.GetBookingTypeBySlug(slug)
+	if err != nil {
+		return dto.BookingTypeDto{}, err
+	}
+
+	if !bookingType.IsActive {
+		return dto.BookingTypeDto{}, utility.ErrNotFound
+	}
+
+	return bookingType, nil
+}
+
+// bookingTypeMemberIds returns the users whose calendars a booking type books,
+// the owner alone unless the booking type is a team one
+func (h *Handler) bookingTypeMemberIds(bookingType dto.BookingTypeDto) ([]uuid.UUID, error) {
+	if bookingType.SchedulingMode == dto.SingleScheduling {
+		return []uuid.UUID{bookingType.UserId}, nil
+	}
+
+	members, err := h.repo.GetBookingTypeMembersByBookingTypeId(&bookingType.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	memberIds := []uuid.UUID{}
+	for _, member := range members {
+		memberIds = append(memberIds, member.UserId)
+	}
+	return memberIds, nil
+}
+
+// bookingSlots returns the slots of the range the members can be booked in, sorted by start
+// -- round robin needs one member to be free, the other modes all of them
+// -- the range is limited to the booking window, slots start on the slot interval counted from the owner's midnight
+// -- events of excludeICalUid don't block, so a booking can be moved to a time overlapping itself
+func (h *Handler) bookingSlots(bookingType dto.BookingTypeDto, memberIds []uuid.UUID, startTime time.Time, endTime time.Time, now time.Time, excludeICalUid *string) ([]freebusy.Slot, error) {
+	if startTime.Before(now) {
+		startTime = now
+	}
+	if windowEnd := now.AddDate(0, 0, bookingType.BookingWindowDays); endTime.After(windowEnd) {
+		endTime = windowEnd
+	}
+	if !endTime.After(startTime) || len(memberIds) == 0 {
+		return []freebusy.Slot{}, nil
+	}
+
+	attendees := []freebusy.Attendee{}
+	for _, memberId := range memberIds {
+		attendee, err := h.bookingAttendee(bookingType, memberId, startTime, endTime, now, excludeICalUid)
+		if err != nil {
+			return nil, err
+		}
+		attendees = append(attendees, attendee)
+	}
+
+	// the owner's time zone decides where the slots of a day start
+	location := time.UTC
+	if ownerAvailability, err := h.userAvailability(bookingType.UserId, startTime, endTime); err != nil {
+		return nil, err
+	} else if ownerAvailability != nil {
+		location = ownerAvailability.Location
+	}
+
+	step := time.Duration(bookingType.SlotIntervalMinutes) * time.Minute
+	localStart := startTime.In(location)
+	slotStart := time.Date(localStart.Year(), localStart.Month(), localStart.Day(), 0, 0, 0, 0, location)
+	for slotStart.Before(startTime) {
+		slotStart = slotStart.Add(step)
+	}
+
+	minAttendeePercentage := float64(100)
+	if bookingType.SchedulingMode == dto.RoundRobinScheduling {
+		minAttendeePercentage = float64(100) / float64(len(attendees))
+	}
+
+	slots := freebusy.FindSlots(attendees, freebusy.SlotSearch{
+		Start:                 slotStart,
+		End:                   endTime,
+		Duration:              time.Duration(bookingType.DurationMinutes) * time.Minute,
+		Step:                  step,
+		MinAttendeePercentage: minAttendeePercentage,
+		WorkingHoursOnly:      true,
+	})
+
+	sort.Slice(slots, func(i, j int) bool {
+		return slots[i].Start.Before(slots[j].Start)
+	})
+
+	return slots, nil
+}
+
+// bookingAttendee returns the free/busy of a member, with the buffers and minimum notice of the booking type
+// -- members without availability rules can't be booked
+func (h *Handler) bookingAttendee(bookingType dto.BookingTypeDto, memberId uuid.UUID, startTime time.Time, endTime time.Time, now time.Time, excludeICalUid *string) (freebusy.Attendee, error) {
+	availability, err := h.userAvailability(memberId, startTime, endTime)
+	if err != nil {
+		return freebusy.Attendee{}, err
+	}
+	if availability == nil {
+		return freebusy.Attendee{Id: memberId.String(), Unavailable: true}, nil
+	}
+
+	if bookingType.BufferBeforeMinutes != nil {
+		availability.BufferBefore = time.Duration(*bookingType.BufferBeforeMinutes) * time.Minute
+	}
+	if bookingType.BufferAfterMinutes != nil {
+		availability.BufferAfter = time.Duration(*bookingType.BufferAfterMinutes) * time.Minute
+	}
+	if bookingType.MinimumNoticeMinutes != nil {
+		availability.MinimumNotice = time.Duration(*bookingType.MinimumNoticeMinutes) * time.Minute
+	}
+
+	// events just outside the range can reach into it with their buffers
+	events, err := h.repo.GetBusyEventsByUserIdAndTimeRange(memberId.String(), startTime.Add(-availability.BufferAfter), endTime.Add(availability.BufferBefore))
+	if err != nil {
+		return freebusy.Attendee{}, err
+	}
+
+	busyIntervals := []freebusy.Interval{}
+	for _, event := range events {
+		if freebusy.NormalizeStatus(event.ShowAs) == freebusy.Free {
+			continue
+		}
+		if excludeICalUid != nil && event.ICalUid == *excludeICalUid {
+			continue
+		}
+		busyIntervals = append(busyIntervals, freebusy.Interval{Start: event.StartTime, End: event.EndTime, Status: event.ShowAs})
+	}
+
+	return freebusy.Attendee{
+		Id:                   memberId.String(),
+		BusyIntervals:        freebusy.Merge(freebusy.Clip(availability.ApplyBuffers(busyIntervals), startTime, endTime)),
+		UnavailableIntervals: availability.UnavailableIntervals(startTime, endTime, now),
+	}, nil
+}
+
+var errInvalidBookingMember = errors.New("invalid booking type member")
+
+// validateBookingTypeMembers checks the members can be booked,
+// collective bookings invite the members so they need an email address
+func (h *Handler) validateBookingTypeMembers(bookingType *dto.BookingTypeDto, members []dto.BookingTypeMemberDto) error {
+	for _, member := range members {
+		user, err := h.repo.GetUserByUserId(&member.UserId)
+		if err != nil {
+			if err == utility.ErrNotFound {
+				return fmt.Errorf("members: %s is not a synced user: %w", member.UserId, errInvalidBookingMember)
+			}
+			return err
+		}
+
+		_, err = h.repo.GetAvailabilityRulesByUserId(&member.UserId)
+		if err != nil {
+			if err == utility.ErrNotFound {
+				return fmt.Errorf("members: %s has no availability rules: %w", member.UserId, errInvalidBookingMember)
+			}
+			return err
+		}
+
+		if bookingType.SchedulingMode == dto.CollectiveScheduling && member.UserId != bookingType.UserId && user.EmailAddress == nil {
+			return fmt.Errorf("members: %s has no email address to invite: %w", member.UserId, errInvalidBookingMember)
+		}
+	}
+
+	return nil
+}
+
+func newBookingTypeDto(userUuid uuid.UUID, req *requestDto.CreateBookingTypeDto) (*dto.BookingTypeDto, []dto.BookingTypeMemberDto, error) {
+	if !bookingSlugPattern.MatchString(req.Slug) {
+		return nil, nil, errors.New("slug: only lowercase letters, digits and single dashes are allowed")
+	}
+	if req.Title == "" {
+		return nil, nil, errors.New("title: is required")
+	}
+	if req.DurationMinutes <= 0 {
+		return nil, nil, errors.New("duration_minutes: must be a positive number of minutes")
+	}
+
+	slotInterval := freebusy.DefaultInterval
+	if req.SlotIntervalMinutes != nil {
+		slotInterval = time.Duration(*req.SlotIntervalMinutes) * time.Minute
+	}
+	if err := freebusy.ValidateInterval(slotInterval); err != nil {
+		return nil, nil, errors.New("slot_interval_minutes: " + err.Error())
+	}
+
+	bookingWindowDays := defaultBookingWindowDays
+	if req.BookingWindowDays != nil {
+		bookingWindowDays = *req.BookingWindowDays
+	}
+	if bookingWindowDays < 1 || bookingWindowDays > maxBookingWindowDays {
+		return nil, nil, errors.New("booking_window_days: must be between 1 and 365")
+	}
+
+	for _, minutes := range []*int{req.BufferBeforeMinutes, req.BufferAfterMinutes, req.MinimumNoticeMinutes} {
+		if minutes != nil && *minutes < 0 {
+			return nil, nil, errors.New("buffers and minimum notice can't be negative")
+		}
+	}
+
+	questions := []dto.BookingQuestionDto{}
+	seenQuestionIds := map[string]bool{}
+	for _, question := range req.Questions {
+		if question.Id == "" || question.Label == "" {
+			return nil, nil, errors.New("questions: id and label are required")
+		}
+		if seenQuestionIds[question.Id] {
+			return nil, nil, errors.New("questions: duplicate id " + question.Id)
+		}
+		seenQuestionIds[question.Id] = true
+		questions = append(questions, dto.BookingQuestionDto{Id: question.Id, Label: question.Label, Required: question.Required})
+	}
+
+	schedulingMode := dto.SingleScheduling
+	if req.SchedulingMode != nil && *req.SchedulingMode != "" {
+		schedulingMode = *req.SchedulingMode
+	}
+
+	members := []dto.BookingTypeMemberDto{}
+	seenMemberIds := map[uuid.UUID]bool{}
+	for _, member := range req.Members {
+		memberUuid, err := uuid.Parse(member)
+		if err != nil {
+			return nil, nil, errors.New("members: " + err.Error())
+		}
+		if !seenMemberIds[memberUuid] {
+			seenMemberIds[memberUuid] = true
+			members = append(members, dto.BookingTypeMemberDto{UserId: memberUuid, CreatedAt: time.Now()})
+		}
+	}
+
+	switch schedulingMode {
+	case dto.SingleScheduling:
+		if len(members) > 0 {
+			return nil, nil, errors.New("members: only team booking types have members")
+		}
+	case dto.RoundRobinScheduling:
+		if len(members) == 0 {
+			return nil, nil, errors.New("members: at least one member is required")
+		}
+	case dto.CollectiveScheduling:
+		// the event is created in the owner's calendar, so the owner always attends
+		if !seenMemberIds[userUuid] {
+			members = append([]dto.BookingTypeMemberDto{{UserId: userUuid, CreatedAt: time.Now()}}, members...)
+		}
+	default:
+		return nil, nil, errors.New("scheduling_mode must be one of single, roundRobin or collective")
+	}
+
+	bookingType := &dto.BookingTypeDto{
+		UserId:                userUuid,
+		Slug:                  req.Slug,
+		Title:                 req.Title,
+		Description:           req.Description,
+		DurationMinutes:       req.DurationMinutes,
+		SlotIntervalMinutes:   int(slotInterval / time.Minute),
+		BookingWindowDays:     bookingWindowDays,
+		BufferBeforeMinutes:   req.BufferBeforeMinutes,
+		BufferAfterMinutes:    req.BufferAfterMinutes,
+		MinimumNoticeMinutes:  req.MinimumNoticeMinutes,
+		LocationDisplayName:   req.LocationDisplayName,
+		IsOnlineMeeting:       req.IsOnlineMeeting,
+		OnlineMeetingProvider: req.OnlineMeetingProvider,
+		Questions:             questions,
+		IsActive:              true,
+		CreatedAt:             time.Now(),
+		UpdatedAt:             time.Now(),
+		SchedulingMode:        schedulingMode,
+	}
+
+	return bookingType, members, nil
+}
@@ -0,0 +1,591 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"html"
+	"log"
+	"net/http"
+	"net/mail"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	graphmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/scheduler-prototype/dto"
+	requestDto "github.com/scheduler-prototype/dto/request"
+	responseDto "github.com/scheduler-prototype/dto/response"
+	"github.com/scheduler-prototype/freebusy"
+	"github.com/scheduler-prototype/utility"
+)
+
+// graph date times of bookings are sent in UTC
+const bookingDateTimeLayout = "2006-01-02T15:04:05"
+
+// CreateBooking books a slot of the booking type, the event is created in the owner's calendar with the guest as attendee
+// -- the manage token is only returned here, guests need it to reschedule or cancel
+func (h *Handler) CreateBooking(w http.ResponseWriter, r *http.Request) {
+	bookingType, err := h.activeBookingType(chi.URLParam(r, "slug"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrNotFound {
+			status = http.StatusNotFound
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	req := &requestDto.CreateBookingDto{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	startTime, answers, err := validateBooking(bookingType, req)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+	endTime := startTime.Add(time.Duration(bookingType.DurationMinutes) * time.Minute)
+
+	memberIds, err := h.bookingTypeMemberIds(bookingType)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// keeps two guests from taking the same slot between the check and the event creation
+	unlock, err := h.repo.LockBookingMembers(memberIds)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+	defer unlock()
+
+	slot, err := h.bookingSlot(bookingType, memberIds, startTime, nil)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrConflict {
+			status = http.StatusConflict
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// the event is created in the organizer's calendar, round robin hands it to the least loaded free member
+	organizerUuid := bookingType.UserId
+	assignedMemberIds := []uuid.UUID{}
+	eventCounts := map[uuid.UUID]int{}
+	attendees := []requestDto.MGraphCreateEventAttendeeDto{
+		{EmailAddress: req.GuestEmail, Name: req.GuestName, AttendeeType: "required"},
+	}
+
+	switch bookingType.SchedulingMode {
+	case dto.RoundRobinScheduling:
+		var eventCount int
+		organizerUuid, eventCount, err = h.leastLoadedMember(bookingType, slot.AvailableAttendees, time.Now())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			response := map[string]string{"error": err.Error()}
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		eventCounts[organizerUuid] = eventCount
+		assignedMemberIds = append(assignedMemberIds, organizerUuid)
+	case dto.CollectiveScheduling:
+		for _, memberId := range memberIds {
+			eventCounts[memberId], err = h.bookingEventCount(bookingType, memberId, time.Now())
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				response := map[string]string{"error": err.Error()}
+				json.NewEncoder(w).Encode(response)
+				return
+			}
+			assignedMemberIds = append(assignedMemberIds, memberId)
+
+			if memberId == bookingType.UserId {
+				continue
+			}
+			member, err := h.repo.GetUserByUserId(&memberId)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				response := map[string]string{"error": err.Error()}
+				json.NewEncoder(w).Encode(response)
+				return
+			}
+			if member.EmailAddress != nil {
+				name, err := h.userDisplayName(memberId, *member.EmailAddress)
+				if err != nil {
+					w.WriteHeader(http.StatusInternalServerError)
+					response := map[string]string{"error": err.Error()}
+					json.NewEncoder(w).Encode(response)
+					return
+				}
+				attendees = append(attendees, requestDto.MGraphCreateEventAttendeeDto{EmailAddress: *member.EmailAddress, Name: name, AttendeeType: "required"})
+			}
+		}
+	}
+
+	organizerUserId := organizerUuid.String()
+	createEventDto := &requestDto.MGraphCreateEventDto{
+		UserId:                &organizerUserId,
+		Subject:               bookingType.Title + " with " + req.GuestName,
+		Content:               bookingContent(bookingType, answers),
+		StartTime:             startTime.UTC().Format(bookingDateTimeLayout),
+		EndTime:               endTime.UTC().Format(bookingDateTimeLayout),
+		TimeZone:              "UTC",
+		Attendees:             attendees,
+		Locations:             &[]requestDto.MGraphCreateEventLocationDto{},
+		IsOnlineMeeting:       bookingType.IsOnlineMeeting,
+		OnlineMeetingProvider: bookingType.OnlineMeetingProvider,
+	}
+	if bookingType.LocationDisplayName != nil {
+		createEventDto.Locations = &[]requestDto.MGraphCreateEventLocationDto{
+			{DisplayName: *bookingType.LocationDisplayName, Address: &requestDto.MGraphCreateEventLocationAddressDto{}},
+		}
+	}
+
+	event, err := h.client.PostCreateEvent(createEventDto)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// store the event right away so the slot is taken for the next guest
+	err = h.storeEvent(*event, organizerUserId)
+	if err != nil {
+		h.discardBookingEvent(organizerUserId, *event)
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	manageToken, manageTokenHash, err := utility.NewToken()
+	if err != nil {
+		h.discardBookingEvent(organizerUserId, *event)
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	booking := &dto.BookingDto{
+		BookingTypeId:   bookingType.ID,
+		UserId:          organizerUuid,
+		EventId:         *(*event).GetId(),
+		ICalUid:         *(*event).GetICalUId(),
+		GuestName:       req.GuestName,
+		GuestEmail:      req.GuestEmail,
+		Answers:         answers,
+		StartTime:       startTime,
+		EndTime:         endTime,
+		Status:          dto.BookingConfirmed,
+		ManageTokenHash: manageTokenHash,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+	err = h.repo.CreateBooking(booking, bookingAssignments(bookingType, assignedMemberIds, eventCounts))
+	if err != nil {
+		h.discardBookingEvent(organizerUserId, *event)
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(responseDto.NewBookingResponseDto(*booking, bookingType, &manageToken))
+}
+
+// discardBookingEvent takes back the event of a booking that couldn't be recorded, so the guest
+// isn't left with an invitation to a booking that doesn't exist and the slot opens up again
+// -- cancelling sends the attendees a cancellation, the event is deleted when that fails
+func (h *Handler) discardBookingEvent(userId string, event graphmodels.Eventable) {
+	if event.GetId() == nil {
+		return
+	}
+
+	comment := "The booking could not be completed, please book again."
+	if err := h.client.PostCancelEvent(userId, *event.GetId(), &comment); err != nil {
+		log.Printf("booking: could not cancel event %s: %s", *event.GetId(), err)
+		if err := h.client.DeleteEvent(userId, *event.GetId()); err != nil {
+			log.Printf("booking: could not delete event %s: %s", *event.GetId(), err)
+			return
+		}
+	}
+
+	if event.GetICalUId() != nil {
+		if err := h.removeEvent(userId, *event.GetICalUId(), dto.EventPayloadDto{Source: dto.EventPayloadFromApi}); err != nil {
+			log.Printf("booking: could not remove event %s: %s", *event.GetId(), err)
+		}
+	}
+}
+
+func (h *Handler) GetBooking(w http.ResponseWriter, r *http.Request) {
+	booking, bookingType, err := h.managedBooking(chi.URLParam(r, "token"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrNotFound {
+			status = http.StatusNotFound
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(responseDto.NewBookingResponseDto(booking, bookingType, nil))
+}
+
+// RescheduleBooking moves the booking to another free slot of its booking type
+func (h *Handler) RescheduleBooking(w http.ResponseWriter, r *http.Request) {
+	booking, bookingType, err := h.managedBooking(chi.URLParam(r, "token"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrNotFound {
+			status = http.StatusNotFound
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if booking.Status != dto.BookingConfirmed {
+		w.WriteHeader(http.StatusConflict)
+		response := map[string]string{"error": "booking is " + booking.Status}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	req := &requestDto.RescheduleBookingDto{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	startTime, err := time.Parse(time.RFC3339, req.Start)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": "start: " + err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+	endTime := startTime.Add(time.Duration(bookingType.DurationMinutes) * time.Minute)
+
+	// a round robin booking stays with the member it was assigned to
+	memberIds := []uuid.UUID{booking.UserId}
+	if bookingType.SchedulingMode != dto.RoundRobinScheduling {
+		memberIds, err = h.bookingTypeMemberIds(bookingType)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			response := map[string]string{"error": err.Error()}
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+	}
+
+	// keeps two guests from taking the same slot between the check and the event creation
+	unlock, err := h.repo.LockBookingMembers(memberIds)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+	defer unlock()
+
+	// the booking's own event doesn't block its new time
+	_, err = h.bookingSlot(bookingType, memberIds, startTime, &booking.ICalUid)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrConflict {
+			status = http.StatusConflict
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	newStartTime := startTime.UTC().Format(bookingDateTimeLayout)
+	newEndTime := endTime.UTC().Format(bookingDateTimeLayout)
+	timeZone := "UTC"
+	event, err := h.client.PatchUpdateEvent(&requestDto.MGraphUpdateEventDto{
+		UserId:    booking.UserId.String(),
+		EventId:   booking.EventId,
+		StartTime: &newStartTime,
+		EndTime:   &newEndTime,
+		TimeZone:  &timeZone,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	err = h.storeEvent(event, booking.UserId.String())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	booking.StartTime = startTime
+	booking.EndTime = endTime
+	err = h.repo.UpdateBookingTimes(&booking)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(responseDto.NewBookingResponseDto(booking, bookingType, nil))
+}
+
+// CancelBooking cancels the event in the owner's calendar, the guest receives the cancellation with the reason
+func (h *Handler) CancelBooking(w http.ResponseWriter, r *http.Request) {
+	booking, bookingType, err := h.managedBooking(chi.URLParam(r, "token"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrNotFound {
+			status = http.StatusNotFound
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if booking.Status != dto.BookingConfirmed {
+		w.WriteHeader(http.StatusConflict)
+		response := map[string]string{"error": "booking is " + booking.Status}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	req := &requestDto.CancelBookingDto{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	err = h.client.PostCancelEvent(booking.UserId.String(), booking.EventId, req.Reason)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	err = h.cancelEvent(booking.EventId, dto.EventPayloadDto{Source: dto.EventPayloadFromApi})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	cancelledAt := time.Now()
+	booking.Status = dto.BookingCancelled
+	booking.CancelledAt = &cancelledAt
+	err = h.repo.CancelBooking(&booking)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(responseDto.NewBookingResponseDto(booking, bookingType, nil))
+}
+
+// managedBooking finds the booking of a manage token along with its booking type
+func (h *Handler) managedBooking(manageToken string) (dto.BookingDto, dto.BookingTypeDto, error) {
+	booking, err := h.repo.GetBookingByManageTokenHash(utility.HashToken(manageToken))
+	if err != nil {
+		return dto.BookingDto{}, dto.BookingTypeDto{}, err
+	}
+
+	bookingType, err := h.repo.GetBookingTypeById(&booking.BookingTypeId)
+	if err != nil {
+		return dto.BookingDto{}, dto.BookingTypeDto{}, err
+	}
+
+	return booking, bookingType, nil
+}
+
+// bookingSlot returns the offered slot starting at the start, ErrConflict when there is none
+func (h *Handler) bookingSlot(bookingType dto.BookingTypeDto, memberIds []uuid.UUID, startTime time.Time, excludeICalUid *string) (freebusy.Slot, error) {
+	endTime := startTime.Add(time.Duration(bookingType.DurationMinutes) * time.Minute)
+	slots, err := h.bookingSlots(bookingType, memberIds, startTime, endTime, time.Now(), excludeICalUid)
+	if err != nil {
+		return freebusy.Slot{}, err
+	}
+
+	for _, slot := range slots {
+		if slot.Start.Equal(startTime) {
+			return slot, nil
+		}
+	}
+
+	return freebusy.Slot{}, utility.ErrConflict
+}
+
+// leastLoadedMember picks the available member with the fewest synced events in the booking window,
+// ties go to the member assigned the fewest bookings of the booking type, then to the one assigned longest ago
+func (h *Handler) leastLoadedMember(bookingType dto.BookingTypeDto, availableMemberIds []string, now time.Time) (uuid.UUID, int, error) {
+	stats, err := h.repo.GetBookingAssignmentStatsByBookingTypeId(&bookingType.ID)
+	if err != nil {
+		return uuid.UUID{}, 0, err
+	}
+
+	type candidate struct {
+		userId     uuid.UUID
+		eventCount int
+		stats      dto.BookingAssignmentStatsDto
+	}
+
+	candidates := []candidate{}
+	for _, memberId := range availableMemberIds {
+		memberUuid, err := uuid.Parse(memberId)
+		if err != nil {
+			return uuid.UUID{}, 0, err
+		}
+
+		eventCount, err := h.bookingEventCount(bookingType, memberUuid, now)
+		if err != nil {
+			return uuid.UUID{}, 0, err
+		}
+
+		candidates = append(candidates, candidate{userId: memberUuid, eventCount: eventCount, stats: stats[memberUuid]})
+	}
+
+	if len(candidates) == 0 {
+		return uuid.UUID{}, 0, utility.ErrConflict
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].eventCount != candidates[j].eventCount {
+			return candidates[i].eventCount < candidates[j].eventCount
+		}
+		if candidates[i].stats.AssignmentCount != candidates[j].stats.AssignmentCount {
+			return candidates[i].stats.AssignmentCount < candidates[j].stats.AssignmentCount
+		}
+		return candidates[i].stats.LastAssignedAt.Before(candidates[j].stats.LastAssignedAt)
+	})
+
+	return candidates[0].userId, candidates[0].eventCount, nil
+}
+
+// bookingEventCount is the load of a member, their synced events within the booking window
+func (h *Handler) bookingEventCount(bookingType dto.BookingTypeDto, memberId uuid.UUID, now time.Time) (int, error) {
+	return h.repo.CountBusyEventsByUserIdAndTimeRange(memberId.String(), now, now.AddDate(0, 0, bookingType.BookingWindowDays))
+}
+
+// userDisplayName is the name the user goes by in the directory, their address when they have none
+func (h *Handler) userDisplayName(userId uuid.UUID, emailAddress string) (string, error) {
+	graphUser, err := h.client.GetUser(userId.String())
+	if err != nil {
+		return "", err
+	}
+	if graphUser.GetDisplayName() == nil || *graphUser.GetDisplayName() == "" {
+		return emailAddress, nil
+	}
+	return *graphUser.GetDisplayName(), nil
+}
+
+// bookingAssignments is the history round robin balances with,
+// collective bookings record every member they were assigned to
+func bookingAssignments(bookingType dto.BookingTypeDto, memberIds []uuid.UUID, eventCounts map[uuid.UUID]int) []dto.BookingAssignmentDto {
+	assignments := []dto.BookingAssignmentDto{}
+	for _, memberId := range memberIds {
+		assignments = append(assignments, dto.BookingAssignmentDto{
+			BookingTypeId:  bookingType.ID,
+			UserId:         memberId,
+			SchedulingMode: bookingType.SchedulingMode,
+			EventCount:     eventCounts[memberId],
+			AssignedAt:     time.Now(),
+		})
+	}
+
+	return assignments
+}
+
+// validateBooking checks the guest and the answers, answers to unknown questions are dropped
+func validateBooking(bookingType dto.BookingTypeDto, req *requestDto.CreateBookingDto) (time.Time, map[string]string, error) {
+	startTime, err := time.Parse(time.RFC3339, req.Start)
+	if err != nil {
+		return time.Time{}, nil, errors.New("start: " + err.Error())
+	}
+
+	if strings.TrimSpace(req.GuestName) == "" {
+		return time.Time{}, nil, errors.New("guest_name: is required")
+	}
+	if _, err := mail.ParseAddress(req.GuestEmail); err != nil {
+		return time.Time{}, nil, errors.New("guest_email: " + err.Error())
+	}
+
+	answers := map[string]string{}
+	for _, question := range bookingType.Questions {
+		answer := strings.TrimSpace(req.Answers[question.Id])
+		if answer == "" {
+			if question.Required {
+				return time.Time{}, nil, errors.New("answers: " + question.Id + " is required")
+			}
+			continue
+		}
+		answers[question.Id] = answer
+	}
+
+	return startTime, answers, nil
+}
+
+// bookingContent is the event body, the description followed by the guest's answers
+func bookingContent(bookingType dto.BookingTypeDto, answers map[string]string) string {
+	var content strings.Builder
+	if bookingType.Description != "" {
+		content.WriteString("<p>" + html.EscapeString(bookingType.Description) + "</p>")
+	}
+
+	for _, question := range bookingType.Questions {
+		answer, ok := answers[question.Id]
+		if !ok {
+			continue
+		}
+		content.WriteString("<p><b>" + html.EscapeString(question.Label) + "</b><br>" + html.EscapeString(answer) + "</p>")
+	}
+
+	return content.String()
+}
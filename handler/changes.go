@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/scheduler-prototype/dto"
+	responseDto "github.com/scheduler-prototype/dto/response"
+)
+
+const (
+	defaultChangesLimit = 100
+	maxChangesLimit     = 1000
+)
+
+// GetChanges is the change feed of the synced events, ordered by cursor
+// since is the next_cursor of the previous page, left out to read from the beginning
+// user narrows the feed down to the copies of one graph user and limit sets the page size
+// -- private events of anyone but the caller in the X-User-Id header only show their busy time
+func (h *Handler) GetChanges(w http.ResponseWriter, r *http.Request) {
+	since, limit, err := parseChangesParams(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	var userId *string
+	if user := r.URL.Query().Get("user"); user != "" {
+		userId = &user
+	}
+
+	// one more than the page tells whether there is another page
+	changes, err := h.repo.GetEventChanges(since, userId, limit+1)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	hasMore := len(changes) > limit
+	if hasMore {
+		changes = changes[:limit]
+	}
+
+	eventIds := []uuid.UUID{}
+	for _, change := range changes {
+		eventIds = append(eventIds, change.EventId)
+	}
+
+	events, err := h.repo.GetEventsByIds(eventIds)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	eventsById := map[uuid.UUID]dto.MGraphEventDto{}
+	for _, event := range events {
+		eventsById[event.ID] = event
+	}
+
+	caller := h.callerUserId(r)
+	response := responseDto.EventChangesResponseDto{
+		Changes:    []responseDto.EventChangeResponseDto{},
+		NextCursor: strconv.FormatInt(since, 10),
+		HasMore:    hasMore,
+	}
+	for _, change := range changes {
+		var eventResponse *responseDto.EventResponseDto
+		if event, ok := eventsById[change.EventId]; ok {
+			currentEvent := newEventResponseDtoFor(event, caller)
+			eventResponse = &currentEvent
+		}
+
+		if !canSeeEventDetails(dto.MGraphEventDto{UserId: change.UserId, IsPrivate: change.IsPrivate}, caller) {
+			change.ChangedFields = busyChangedFields(change.ChangedFields)
+		}
+
+		response.Changes = append(response.Changes, responseDto.NewEventChangeResponseDto(change, eventResponse))
+		response.NextCursor = strconv.FormatInt(change.ID, 10)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func parseChangesParams(r *http.Request) (int64, int, error) {
+	var since int64
+	if value := r.URL.Query().Get("since"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed < 0 {
+			return 0, 0, errors.New("since: must be a cursor returned by this endpoint")
+		}
+		since = parsed
+	}
+
+	limit := defaultChangesLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxChangesLimit {
+			return 0, 0, errors.New("limit: must be between 1 and " + strconv.Itoa(maxChangesLimit))
+		}
+		limit = parsed
+	}
+
+	return since, limit, nil
+}
+
+func busyChangedFields(changedFields []string) []string {
+	fields := []string{}
+	for _, field := range changedFields {
+		if busyEventFields[field] {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/scheduler-prototype/dto"
+	requestDto "github.com/scheduler-prototype/dto/request"
+	responseDto "github.com/scheduler-prototype/dto/response"
+	"github.com/scheduler-prototype/freebusy"
+	"github.com/scheduler-prototype/timezone"
+	"github.com/scheduler-prototype/utility"
+)
+
+func parseConflictPolicy(policy *string) (string, error) {
+	if policy == nil || *policy == "" {
+		return requestDto.ConflictPolicyIgnore, nil
+	}
+
+	switch *policy {
+	case requestDto.ConflictPolicyIgnore, requestDto.ConflictPolicyWarn, requestDto.ConflictPolicyReject:
+		return *policy, nil
+	}
+
+	return "", errors.New("conflict_policy must be one of ignore, warn or reject")
+}
+
+// eventRange returns the instants a requested event covers,
+// all day events run from midnight to midnight in the given zone
+func eventRange(startTime string, endTime string, timeZone string, isAllDay bool) (time.Time, time.Time, error) {
+	if !isAllDay {
+		start, err := timezone.ParseDateTimeTimeZone(startTime, timeZone)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+
+		end, err := timezone.ParseDateTimeTimeZone(endTime, timeZone)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+
+		return start, end, nil
+	}
+
+	location, err := timezone.LoadLocation(timeZone)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	if len(startTime) < 10 || len(endTime) < 10 {
+		return time.Time{}, time.Time{}, errors.New("all day events need dates as start_time and end_time")
+	}
+
+	startDate, err := time.ParseInLocation("2006-01-02", startTime[:10], location)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	endDate, err := time.ParseInLocation("2006-01-02", endTime[:10], location)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	return startDate, endDate, nil
+}
+
+// eventConflicts returns the synced events of the organizer and the internal attendees overlapping the range
+// -- attendees that aren't synced users can't be checked and are skipped
+// -- copies of the event itself, matched by iCalUId, are not conflicts
+// -- the titles of private events only show to callerUserId, the organizer in the request body isn't authenticated
+func (h *Handler) eventConflicts(callerUserId string, organizerUserId string, attendeeEmails []string, startTime time.Time, endTime time.Time, iCalUid *string) ([]responseDto.EventConflictResponseDto, error) {
+	participants := []dto.UserDto{}
+	seenUserIds := map[uuid.UUID]bool{}
+
+	organizerUuid, err := uuid.Parse(organizerUserId)
+	if err != nil {
+		return nil, err
+	}
+
+	organizer, err := h.repo.GetUserByUserId(&organizerUuid)
+	if err != nil && err != utility.ErrNotFound {
+		return nil, err
+	}
+	if err == nil {
+		participants = append(participants, organizer)
+		seenUserIds[organizer.UserId] = true
+	}
+
+	for _, attendeeEmail := range attendeeEmails {
+		attendee, err := h.repo.GetUserByEmailAddress(attendeeEmail)
+		if err != nil {
+			if err == utility.ErrNotFound {
+				continue
+			}
+			return nil, err
+		}
+
+		if !seenUserIds[attendee.UserId] {
+			participants = append(participants, attendee)
+			seenUserIds[attendee.UserId] = true
+		}
+	}
+
+	conflicts := []responseDto.EventConflictResponseDto{}
+	for _, participant := range participants {
+		events, err := h.repo.GetBusyEventsByUserIdAndTimeRange(participant.UserId.String(), startTime, endTime)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, event := range events {
+			if freebusy.NormalizeStatus(event.ShowAs) == freebusy.Free {
+				continue
+			}
+			if iCalUid != nil && strings.EqualFold(event.ICalUid, *iCalUid) {
+				continue
+			}
+
+			conflict := responseDto.NewEventConflictResponseDto(participant, event)
+			if !canSeeEventDetails(event, callerUserId) {
+				conflict.Title = ""
+			}
+			conflicts = append(conflicts, conflict)
+		}
+	}
+
+	return conflicts, nil
+}
+
+// writeConflictResponse refuses a request because of its conflicts
+func writeConflictResponse(w http.ResponseWriter, conflicts []responseDto.EventConflictResponseDto) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusConflict)
+	response := map[string]interface{}{"error": utility.ErrConflict.Error(), "conflicts": conflicts}
+	json.NewEncoder(w).Encode(response)
+}
+
+// writeEventWithConflicts answers a request made with the warn policy
+func writeEventWithConflicts(w http.ResponseWriter, eventJson []byte, conflicts []responseDto.EventConflictResponseDto) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	response := map[string]interface{}{"event": json.RawMessage(eventJson), "conflicts": conflicts}
+	json.NewEncoder(w).Encode(response)
+}
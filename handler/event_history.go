@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/scheduler-prototype/dto"
+	responseDto "github.com/scheduler-prototype/dto/response"
+	"github.com/scheduler-prototype/utility"
+)
+
+// the fields of a private event anyone may see the changes of, the same ones its busy time shows
+var busyEventFields = map[string]bool{
+	"start_time": true,
+	"end_time":   true,
+	"start_date": true,
+	"end_date":   true,
+	"is_all_day": true,
+	"show_as":    true,
+	// a cancellation frees the time as well
+	"is_cancelled": true,
+}
+
+// trackedEventFields returns the fields whose changes are recorded, as they show in the history
+func trackedEventFields(event *dto.MGraphEventDto) map[string]interface{} {
+	if event == nil {
+		return map[string]interface{}{}
+	}
+
+	dateOf := func(date *time.Time) interface{} {
+		if date == nil {
+			return nil
+		}
+		return date.Format("2006-01-02")
+	}
+
+	categories := event.Categories
+	if categories == nil {
+		categories = []string{}
+	}
+
+	return map[string]interface{}{
+		"title":                         event.Title,
+		"description":                   event.Description,
+		"start_time":                    event.StartTime.UTC().Format(time.RFC3339),
+		"end_time":                      event.EndTime.UTC().Format(time.RFC3339),
+		"start_date":                    dateOf(event.StartDate),
+		"end_date":                      dateOf(event.EndDate),
+		"is_all_day":                    event.IsAllDay,
+		"timezone":                      event.Timezone,
+		"is_cancelled":                  event.IsCancelled,
+		"show_as":                       event.ShowAs,
+		"is_online":                     event.IsOnline,
+		"meeting_url":                   event.MeetingUrl,
+		"locations_count":               event.LocationsCount,
+		"importance":                    event.Importance,
+		"sensitivity":                   event.Sensitivity,
+		"categories":                    categories,
+		"organizer_email":               event.OrganizerEmail,
+		"is_reminder_on":                event.IsReminderOn,
+		"reminder_minutes_before_start": event.ReminderMinutesBeforeStart,
+	}
+}
+
+// eventChanges diffs the tracked fields, before is nil for a created event and after is nil for a removed one
+func eventChanges(before *dto.MGraphEventDto, after *dto.MGraphEventDto) map[string]dto.EventFieldChangeDto {
+	beforeFields := trackedEventFields(before)
+	afterFields := trackedEventFields(after)
+
+	fields := afterFields
+	if after == nil {
+		fields = beforeFields
+	}
+
+	changes := map[string]dto.EventFieldChangeDto{}
+	for field := range fields {
+		beforeValue, afterValue := derefValue(beforeFields[field]), derefValue(afterFields[field])
+		if before != nil && after != nil && reflect.DeepEqual(beforeValue, afterValue) {
+			continue
+		}
+		changes[field] = dto.EventFieldChangeDto{Before: beforeValue, After: afterValue}
+	}
+
+	return changes
+}
+
+// derefValue compares and shows pointers by what they point to
+func derefValue(value interface{}) interface{} {
+	reflected := reflect.ValueOf(value)
+	if reflected.Kind() != reflect.Ptr {
+		return value
+	}
+	if reflected.IsNil() {
+		return nil
+	}
+	return reflected.Elem().Interface()
+}
+
+// newEventRevisionDto describes what changed in the user's copy of the event for the repository to record with the row,
+// nil for an update that changed none of the tracked fields -- a created copy gets its EventId once it's stored
+func newEventRevisionDto(revisionType string, before *dto.MGraphEventDto, after *dto.MGraphEventDto, source dto.EventPayloadDto) *dto.EventRevisionDto {
+	changes := eventChanges(before, after)
+	if revisionType == dto.EventUpdated && len(changes) == 0 {
+		return nil
+	}
+
+	event := after
+	if event == nil {
+		event = before
+	}
+
+	revision := &dto.EventRevisionDto{
+		EventId:      event.ID,
+		UserId:       event.UserId,
+		ICalUid:      event.ICalUid,
+		GraphEventId: event.EventId,
+		RevisionType: revisionType,
+		Changes:      changes,
+		Source:       source.Source,
+		SyncRunId:    source.SyncRunId,
+		DeltaLink:    source.DeltaPageLink,
+		IsPrivate:    event.IsPrivate,
+		RecordedAt:   time.Now(),
+		EventType:    event.Type,
+	}
+
+	// Graph's own modification time, a removal happens on our side so it has none
+	if after != nil {
+		graphModifiedAt := after.UpdatedTime
+		revision.GraphModifiedAt = &graphModifiedAt
+	}
+
+	return revision
+}
+
+// GetEventHistory returns the revisions of the user's copy of the event, oldest first,
+// the history stays available after the copy was removed
+// -- for private events of anyone but the caller in the X-User-Id header only the busy time changes show
+func (h *Handler) GetEventHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": "id: " + err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	revisions, err := h.repo.GetEventRevisionsByEventId(&id)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if len(revisions) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		response := map[string]string{"error": utility.ErrNotFound.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// the latest revision knows whether the event is private now
+	latest := revisions[len(revisions)-1]
+	canSeeDetails := canSeeEventDetails(dto.MGraphEventDto{UserId: latest.UserId, IsPrivate: latest.IsPrivate}, h.callerUserId(r))
+
+	revisionResponses := []responseDto.EventRevisionResponseDto{}
+	for _, revision := range revisions {
+		if !canSeeDetails {
+			for field := range revision.Changes {
+				if !busyEventFields[field] {
+					delete(revision.Changes, field)
+				}
+			}
+			if revision.RevisionType == dto.EventUpdated && len(revision.Changes) == 0 {
+				continue
+			}
+		}
+
+		revisionResponses = append(revisionResponses, responseDto.NewEventRevisionResponseDto(revision))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(revisionResponses)
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/scheduler-prototype/dto"
+	responseDto "github.com/scheduler-prototype/dto/response"
+)
+
+// callerUserId is the graph user id the request is made on behalf of.
+// The service doesn't authenticate users itself: the proxy in front of it does, sets X-User-Id
+// to the authenticated user and proves itself with X-Proxy-Secret. Requests without the matching
+// secret -- or all of them when TRUSTED_PROXY_SECRET isn't set -- are treated as coming from nobody in particular
+func (h *Handler) callerUserId(r *http.Request) string {
+	if h.trustedProxySecret == "" {
+		return ""
+	}
+
+	proxySecret := r.Header.Get("X-Proxy-Secret")
+	if subtle.ConstantTimeCompare([]byte(proxySecret), []byte(h.trustedProxySecret)) != 1 {
+		return ""
+	}
+
+	return r.Header.Get("X-User-Id")
+}
+
+// canSeeEventDetails tells whether the caller may see more than the busy time of the event
+func canSeeEventDetails(event dto.MGraphEventDto, callerUserId string) bool {
+	return !event.IsPrivate || (callerUserId != "" && strings.EqualFold(event.UserId, callerUserId))
+}
+
+func newEventResponseDtoFor(event dto.MGraphEventDto, callerUserId string) responseDto.EventResponseDto {
+	if !canSeeEventDetails(event, callerUserId) {
+		return responseDto.NewBusyEventResponseDto(event)
+	}
+
+	return responseDto.NewEventResponseDto(event)
+}
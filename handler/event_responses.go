@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	responseDto "github.com/scheduler-prototype/dto/response"
+	"github.com/scheduler-prototype/utility"
+)
+
+// GetEventResponses returns who responded to the meeting and when, with the acceptance rate,
+// the attendees are shared by every synced copy of the meeting
+func (h *Handler) GetEventResponses(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": "id: " + err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	event, err := h.repo.GetEventById(&id)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrNotFound {
+			status = http.StatusNotFound
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// who was invited to a private meeting is as private as its title
+	if !canSeeEventDetails(event, h.callerUserId(r)) {
+		w.WriteHeader(http.StatusNotFound)
+		response := map[string]string{"error": utility.ErrNotFound.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	attendees, err := h.repo.GetAttendeesByICalUid(event.ICalUid)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	history, err := h.repo.GetAttendeeResponseHistoryByICalUid(event.ICalUid)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(responseDto.NewEventResponsesResponseDto(event, attendees, history))
+}
@@ -0,0 +1,299 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	graphmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/scheduler-prototype/dto"
+	requestDto "github.com/scheduler-prototype/dto/request"
+	responseDto "github.com/scheduler-prototype/dto/response"
+	"github.com/scheduler-prototype/freebusy"
+	"github.com/scheduler-prototype/mgraph"
+	"github.com/scheduler-prototype/timezone"
+	"github.com/scheduler-prototype/utility"
+)
+
+const defaultMaxMeetingTimeCandidates = 10
+
+var errUnknownAttendee = errors.New("must be a synced user id or an email address")
+
+// FindMeetingTimes ranks the slots of the range where the attendees can meet
+// -- synced users are answered from the events table, other email addresses through Graph's getSchedule
+func (h *Handler) FindMeetingTimes(w http.ResponseWriter, r *http.Request) {
+	req := &requestDto.FindMeetingTimesDto{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	search, err := newSlotSearch(req)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	attendees, err := h.meetingAttendees(req, search.Start, search.End)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == mgraph.ErrTooManySchedules || errors.Is(err, errUnknownAttendee) {
+			status = http.StatusBadRequest
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	slotResponses := []responseDto.MeetingTimeSlotResponseDto{}
+	for _, slot := range freebusy.FindSlots(attendees, search) {
+		slotResponses = append(slotResponses, responseDto.NewMeetingTimeSlotResponseDto(slot))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(slotResponses)
+}
+
+func newSlotSearch(req *requestDto.FindMeetingTimesDto) (freebusy.SlotSearch, error) {
+	search := freebusy.SlotSearch{
+		Duration:              time.Duration(req.Duration) * time.Minute,
+		Step:                  freebusy.DefaultInterval,
+		MinAttendeePercentage: 100,
+		WorkingHoursOnly:      req.WorkingHoursOnly,
+		MaxCandidates:         defaultMaxMeetingTimeCandidates,
+	}
+
+	if len(req.Attendees) == 0 {
+		return search, errors.New("attendees: at least one attendee is required")
+	}
+	if search.Duration <= 0 {
+		return search, errors.New("duration: must be a positive number of minutes")
+	}
+
+	var err error
+	search.Start, err = parseTimeParam(req.Start)
+	if err != nil {
+		return search, errors.New("start: " + err.Error())
+	}
+	search.End, err = parseTimeParam(req.End)
+	if err != nil {
+		return search, errors.New("end: " + err.Error())
+	}
+	if err := freebusy.ValidateRange(search.Start, search.End); err != nil {
+		return search, err
+	}
+
+	if req.SlotInterval != nil {
+		search.Step = time.Duration(*req.SlotInterval) * time.Minute
+		if err := freebusy.ValidateInterval(search.Step); err != nil {
+			return search, errors.New("slot_interval: " + err.Error())
+		}
+	}
+	if req.MinAttendeePercentage != nil {
+		search.MinAttendeePercentage = *req.MinAttendeePercentage
+	}
+	if req.MaxCandidates != nil {
+		search.MaxCandidates = *req.MaxCandidates
+	}
+
+	if req.WorkingHours != nil {
+		location, err := timezone.LoadLocation(req.WorkingHours.TimeZone)
+		if err != nil {
+			return search, errors.New("working_hours.time_zone: " + err.Error())
+		}
+		search.Override = &freebusy.WorkingHours{
+			Start:      req.WorkingHours.StartTime,
+			End:        req.WorkingHours.EndTime,
+			DaysOfWeek: req.WorkingHours.DaysOfWeek,
+			Location:   location,
+		}
+		search.WorkingHoursOnly = true
+	}
+
+	return search, nil
+}
+
+// meetingAttendees collects the free/busy of every attendee, in the order they were requested
+func (h *Handler) meetingAttendees(req *requestDto.FindMeetingTimesDto, startTime time.Time, endTime time.Time) ([]freebusy.Attendee, error) {
+	attendees := make([]freebusy.Attendee, len(req.Attendees))
+	emailAddresses := []string{}
+	emailIndexes := map[string]int{}
+
+	for i, attendeeId := range req.Attendees {
+		attendees[i].Id = attendeeId
+
+		var userDto dto.UserDto
+		if strings.Contains(attendeeId, "@") {
+			// addresses of synced users are answered locally as well
+			var err error
+			userDto, err = h.repo.GetUserByEmailAddress(attendeeId)
+			if err != nil {
+				if err != utility.ErrNotFound {
+					return nil, err
+				}
+
+				emailAddresses = append(emailAddresses, attendeeId)
+				emailIndexes[strings.ToLower(attendeeId)] = i
+				continue
+			}
+		} else {
+			userUuid, err := uuid.Parse(attendeeId)
+			if err != nil {
+				return nil, fmt.Errorf("attendees: %s %w", attendeeId, errUnknownAttendee)
+			}
+
+			userDto, err = h.repo.GetUserByUserId(&userUuid)
+			if err != nil {
+				if err == utility.ErrNotFound {
+					return nil, fmt.Errorf("attendees: %s is not synced, it %w", attendeeId, errUnknownAttendee)
+				}
+				return nil, err
+			}
+		}
+
+		busyIntervals, unavailableIntervals, err := h.userFreeBusy(userDto.UserId, startTime, endTime, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		attendees[i].BusyIntervals = busyIntervals
+		attendees[i].UnavailableIntervals = unavailableIntervals
+
+		// users without availability rules fall back to their mailbox working hours
+		if unavailableIntervals == nil {
+			attendees[i].WorkingHours = userWorkingHours(userDto)
+		}
+	}
+
+	if len(emailAddresses) == 0 {
+		return attendees, nil
+	}
+
+	organizerUserId := mgraph.DefaultUserId
+	if req.OrganizerUserId != nil {
+		organizerUserId = *req.OrganizerUserId
+	}
+
+	schedules, err := h.client.GetSchedule(organizerUserId, emailAddresses, startTime, endTime, int32(freebusy.DefaultInterval/time.Minute))
+	if err != nil {
+		return nil, err
+	}
+
+	// addresses Graph didn't answer for stay unavailable
+	for _, emailAddress := range emailAddresses {
+		attendees[emailIndexes[strings.ToLower(emailAddress)]].Unavailable = true
+	}
+	for _, schedule := range schedules {
+		if schedule.GetScheduleId() == nil {
+			continue
+		}
+		i, ok := emailIndexes[strings.ToLower(*schedule.GetScheduleId())]
+		if !ok || schedule.GetError() != nil {
+			continue
+		}
+
+		attendees[i].BusyIntervals = scheduleBusyIntervals(schedule, startTime, endTime)
+		attendees[i].WorkingHours = scheduleWorkingHours(schedule)
+		attendees[i].Unavailable = false
+	}
+
+	return attendees, nil
+}
+
+// userWorkingHours returns the working hours synced from the user's mailbox settings, nil if there are none
+func userWorkingHours(userDto dto.UserDto) *freebusy.WorkingHours {
+	if userDto.WorkingHoursStart == nil || userDto.WorkingHoursEnd == nil || len(userDto.WorkingDays) == 0 {
+		return nil
+	}
+
+	location := time.UTC
+	if userDto.WorkingHoursTimezone != nil {
+		if workingHoursLocation, err := timezone.LoadLocation(*userDto.WorkingHoursTimezone); err == nil {
+			location = workingHoursLocation
+		}
+	}
+
+	return &freebusy.WorkingHours{
+		Start:      *userDto.WorkingHoursStart,
+		End:        *userDto.WorkingHoursEnd,
+		DaysOfWeek: userDto.WorkingDays,
+		Location:   location,
+	}
+}
+
+// scheduleBusyIntervals converts Graph schedule items into merged busy intervals clipped to the range
+func scheduleBusyIntervals(schedule graphmodels.ScheduleInformationable, startTime time.Time, endTime time.Time) []freebusy.Interval {
+	intervals := []freebusy.Interval{}
+	for _, scheduleItem := range schedule.GetScheduleItems() {
+		if scheduleItem.GetStart() == nil || scheduleItem.GetEnd() == nil ||
+			scheduleItem.GetStart().GetDateTime() == nil || scheduleItem.GetEnd().GetDateTime() == nil {
+			continue
+		}
+
+		itemTimeZone := "UTC"
+		if scheduleItem.GetStart().GetTimeZone() != nil {
+			itemTimeZone = *scheduleItem.GetStart().GetTimeZone()
+		}
+
+		itemStart, err := timezone.ParseDateTimeTimeZone(*scheduleItem.GetStart().GetDateTime(), itemTimeZone)
+		if err != nil {
+			continue
+		}
+		itemEnd, err := timezone.ParseDateTimeTimeZone(*scheduleItem.GetEnd().GetDateTime(), itemTimeZone)
+		if err != nil {
+			continue
+		}
+
+		status := freebusy.Busy
+		if scheduleItem.GetStatus() != nil {
+			status = scheduleItem.GetStatus().String()
+		}
+
+		if itemStart.Before(startTime) {
+			itemStart = startTime
+		}
+		if itemEnd.After(endTime) {
+			itemEnd = endTime
+		}
+		intervals = append(intervals, freebusy.Interval{Start: itemStart, End: itemEnd, Status: status})
+	}
+
+	return freebusy.Merge(intervals)
+}
+
+// scheduleWorkingHours returns the working hours Graph sent along with the schedule, nil if there are none
+func scheduleWorkingHours(schedule graphmodels.ScheduleInformationable) *freebusy.WorkingHours {
+	workingHours := schedule.GetWorkingHours()
+	if workingHours == nil || workingHours.GetStartTime() == nil || workingHours.GetEndTime() == nil {
+		return nil
+	}
+
+	location := time.UTC
+	if workingHours.GetTimeZone() != nil && workingHours.GetTimeZone().GetName() != nil {
+		if workingHoursLocation, err := timezone.LoadLocation(*workingHours.GetTimeZone().GetName()); err == nil {
+			location = workingHoursLocation
+		}
+	}
+
+	daysOfWeek := []string{}
+	for _, dayOfWeek := range workingHours.GetDaysOfWeek() {
+		daysOfWeek = append(daysOfWeek, dayOfWeek.String())
+	}
+
+	// Graph sends times as 15:04:05.0000000
+	return &freebusy.WorkingHours{
+		Start:      workingHours.GetStartTime().String()[:8],
+		End:        workingHours.GetEndTime().String()[:8],
+		DaysOfWeek: daysOfWeek,
+		Location:   location,
+	}
+}
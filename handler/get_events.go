@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	graphmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/scheduler-prototype/dto"
+	responseDto "github.com/scheduler-prototype/dto/response"
+)
+
+// GetEvents returns the synced events overlapping the start and end query params
+// both accept either RFC3339 date times or plain dates
+// user_id, show_as, importance, sensitivity, category, organizer_email and is_reminder_on narrow them down
+// private events of anyone but the caller in the X-User-Id header only show their busy time
+func (h *Handler) GetEvents(w http.ResponseWriter, r *http.Request) {
+	startTime, err := parseTimeParam(r.URL.Query().Get("start"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": "start: " + err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	endTime, err := parseTimeParam(r.URL.Query().Get("end"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": "end: " + err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	filter, err := newEventFilterDto(r.URL.Query(), startTime, endTime)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	events, err := h.repo.GetEventsByFilter(filter)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	eventResponses := []responseDto.EventResponseDto{}
+	// matching a filter on its details would give those details of a private event away
+	filtersOnDetails := filter.Importance != nil || filter.Sensitivity != nil || filter.Category != nil || filter.OrganizerEmail != nil || filter.IsReminderOn != nil
+	for _, event := range events {
+		if filtersOnDetails && !canSeeEventDetails(event, h.callerUserId(r)) {
+			continue
+		}
+		eventResponses = append(eventResponses, newEventResponseDtoFor(event, h.callerUserId(r)))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(eventResponses)
+}
+
+func parseTimeParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, errors.New("is required")
+	}
+
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+
+	return time.Parse("2006-01-02", value)
+}
+
+func newEventFilterDto(query url.Values, startTime time.Time, endTime time.Time) (dto.EventFilterDto, error) {
+	filter := dto.EventFilterDto{
+		StartTime: startTime,
+		EndTime:   endTime,
+	}
+
+	optional := func(name string) *string {
+		if value := query.Get(name); value != "" {
+			return &value
+		}
+		return nil
+	}
+
+	filter.UserId = optional("user_id")
+	filter.Category = optional("category")
+	filter.OrganizerEmail = optional("organizer_email")
+
+	// enum values are checked against Graph's so a typo doesn't silently match nothing
+	filter.ShowAs = optional("show_as")
+	if filter.ShowAs != nil {
+		if _, err := graphmodels.ParseFreeBusyStatus(*filter.ShowAs); err != nil {
+			return filter, errors.New("show_as: " + err.Error())
+		}
+	}
+
+	filter.Importance = optional("importance")
+	if filter.Importance != nil {
+		if _, err := graphmodels.ParseImportance(*filter.Importance); err != nil {
+			return filter, errors.New("importance: " + err.Error())
+		}
+	}
+
+	filter.Sensitivity = optional("sensitivity")
+	if filter.Sensitivity != nil {
+		if _, err := graphmodels.ParseSensitivity(*filter.Sensitivity); err != nil {
+			return filter, errors.New("sensitivity: " + err.Error())
+		}
+	}
+
+	if value := optional("is_reminder_on"); value != nil {
+		isReminderOn, err := strconv.ParseBool(*value)
+		if err != nil {
+			return filter, errors.New("is_reminder_on: must be true or false")
+		}
+		filter.IsReminderOn = &isReminderOn
+	}
+
+	return filter, nil
+}
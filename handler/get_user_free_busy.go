@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	responseDto "github.com/scheduler-prototype/dto/response"
+	"github.com/scheduler-prototype/freebusy"
+)
+
+// GetUserFreeBusy computes the user's free/busy from the synced events instead of asking Graph
+// interval is the availability view slot size in minutes, 30 by default
+// the user's availability rules add their buffers to the busy intervals and are listed as unavailable intervals
+func (h *Handler) GetUserFreeBusy(w http.ResponseWriter, r *http.Request) {
+	userUuid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	startTime, err := parseTimeParam(r.URL.Query().Get("start"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": "start: " + err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	endTime, err := parseTimeParam(r.URL.Query().Get("end"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": "end: " + err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if err := freebusy.ValidateRange(startTime, endTime); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	interval := freebusy.DefaultInterval
+	if intervalParam := r.URL.Query().Get("interval"); intervalParam != "" {
+		minutes, err := strconv.Atoi(intervalParam)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			response := map[string]string{"error": "interval: " + err.Error()}
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		interval = time.Duration(minutes) * time.Minute
+	}
+
+	if err := freebusy.ValidateInterval(interval); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	busyIntervals, unavailableIntervals, err := h.userFreeBusy(userUuid, startTime, endTime, time.Now())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(responseDto.NewFreeBusyResponseDto(userUuid.String(), startTime, endTime, interval, busyIntervals, unavailableIntervals))
+}
+
+// userFreeBusy returns the user's merged busy intervals, widened by their buffers,
+// and the time their availability rules block, both clipped to the range
+// -- the unavailable intervals are nil when the user has no availability rules
+func (h *Handler) userFreeBusy(userUuid uuid.UUID, startTime time.Time, endTime time.Time, now time.Time) ([]freebusy.Interval, []freebusy.Interval, error) {
+	availability, err := h.userAvailability(userUuid, startTime, endTime)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if availability == nil {
+		busyIntervals, err := h.userBusyIntervals(userUuid.String(), startTime, endTime)
+		return busyIntervals, nil, err
+	}
+
+	// events just outside the range can reach into it with their buffers
+	busyIntervals, err := h.userBusyIntervals(userUuid.String(), startTime.Add(-availability.BufferAfter), endTime.Add(availability.BufferBefore))
+	if err != nil {
+		return nil, nil, err
+	}
+	busyIntervals = freebusy.Clip(availability.ApplyBuffers(busyIntervals), startTime, endTime)
+
+	return busyIntervals, availability.UnavailableIntervals(startTime, endTime, now), nil
+}
+
+// userBusyIntervals returns the user's merged busy intervals, clipped to the range
+func (h *Handler) userBusyIntervals(userId string, startTime time.Time, endTime time.Time) ([]freebusy.Interval, error) {
+	events, err := h.repo.GetBusyEventsByUserIdAndTimeRange(userId, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	intervals := []freebusy.Interval{}
+	for _, event := range events {
+		intervals = append(intervals, freebusy.Interval{Start: event.StartTime, End: event.EndTime, Status: event.ShowAs})
+	}
+
+	return freebusy.Merge(freebusy.Clip(intervals, startTime, endTime)), nil
+}
@@ -1,6 +1,9 @@
 package handler
 
 import (
+	"os"
+	"strconv"
+
 	"github.com/scheduler-prototype/mgraph"
 	"github.com/scheduler-prototype/repository"
 )
@@ -8,11 +11,24 @@ import (
 type Handler struct {
 	client *mgraph.MGraph
 	repo   *repository.Repository
+
+	// STORE_PRIVATE_EVENT_BODIES=false keeps the bodies of private events out of the database
+	storePrivateEventBodies bool
+	// TRUSTED_PROXY_SECRET is shared with the authenticating proxy in front of the service,
+	// X-User-Id is only believed on requests carrying it, see callerUserId
+	trustedProxySecret string
 }
 
 func NewHandler(client *mgraph.MGraph, repo *repository.Repository) *Handler {
+	storePrivateEventBodies, err := strconv.ParseBool(os.Getenv("STORE_PRIVATE_EVENT_BODIES"))
+	if err != nil {
+		storePrivateEventBodies = true
+	}
+
 	return &Handler{
-		client: client,
-		repo:   repo,
+		client:                  client,
+		repo:                    repo,
+		storePrivateEventBodies: storePrivateEventBodies,
+		trustedProxySecret:      os.Getenv("TRUSTED_PROXY_SECRET"),
 	}
 }
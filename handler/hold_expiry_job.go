@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"log"
+	"time"
+
+	"github.com/scheduler-prototype/dto"
+	"github.com/scheduler-prototype/mgraph"
+	"github.com/scheduler-prototype/utility"
+)
+
+// StartHoldExpiryJob deletes the events of the holds that expired unconfirmed every interval
+func (h *Handler) StartHoldExpiryJob(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for ; true; <-ticker.C {
+			h.expireHolds(time.Now())
+		}
+	}()
+}
+
+func (h *Handler) expireHolds(now time.Time) {
+	holds, err := h.repo.GetExpiredHolds(now)
+	if err != nil {
+		log.Printf("hold expiry: could not get expired holds: %s", err)
+		return
+	}
+
+	for _, hold := range holds {
+		// a hold confirmed in the meantime is left alone
+		err := h.repo.ClaimHold(&hold.ID, dto.HoldExpiring, now)
+		if err == utility.ErrConflict {
+			continue
+		}
+		if err != nil {
+			log.Printf("hold expiry: hold %s: %s", hold.ID, err)
+			continue
+		}
+
+		err = h.expireHold(hold)
+		if err != nil {
+			// put back so the next run tries again
+			if releaseErr := h.repo.UpdateHoldStatus(&hold, dto.HoldExpiring); releaseErr != nil {
+				log.Printf("hold expiry: hold %s: could not release the hold: %s", hold.ID, releaseErr)
+			}
+			log.Printf("hold expiry: hold %s: %s", hold.ID, err)
+			continue
+		}
+		log.Printf("hold expiry: hold %s expired", hold.ID)
+	}
+}
+
+// expireHold deletes the tentative event in Graph and locally, a failed delete is retried on the next run
+// -- an event the user already deleted in Outlook is as good as expired
+func (h *Handler) expireHold(hold dto.HoldDto) error {
+	err := h.client.DeleteEvent(hold.UserId, hold.EventId)
+	if err != nil && err != mgraph.ErrNotFound {
+		return err
+	}
+
+	err = h.removeEvent(hold.UserId, hold.ICalUid, dto.EventPayloadDto{Source: dto.EventPayloadFromApi})
+	if err != nil {
+		return err
+	}
+
+	hold.Status = dto.HoldExpired
+	return h.repo.UpdateHoldStatus(&hold, dto.HoldExpiring)
+}
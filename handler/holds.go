@@ -0,0 +1,288 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	graphmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/scheduler-prototype/dto"
+	requestDto "github.com/scheduler-prototype/dto/request"
+	responseDto "github.com/scheduler-prototype/dto/response"
+	"github.com/scheduler-prototype/mgraph"
+	"github.com/scheduler-prototype/utility"
+)
+
+const defaultHoldDuration = 24 * time.Hour
+
+// CreateHold blocks the time with a tentative event in the user's calendar until the hold expires
+func (h *Handler) CreateHold(w http.ResponseWriter, r *http.Request) {
+	req := &requestDto.CreateHoldDto{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	hold, err := newHoldDto(req, time.Now())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	showAs := graphmodels.TENTATIVE_FREEBUSYSTATUS.String()
+	event, err := h.client.PostCreateEvent(&requestDto.MGraphCreateEventDto{
+		UserId:    &hold.UserId,
+		Subject:   hold.Subject,
+		StartTime: hold.StartTime.UTC().Format(bookingDateTimeLayout),
+		EndTime:   hold.EndTime.UTC().Format(bookingDateTimeLayout),
+		TimeZone:  "UTC",
+		Attendees: []requestDto.MGraphCreateEventAttendeeDto{},
+		Locations: &[]requestDto.MGraphCreateEventLocationDto{},
+		ShowAs:    &showAs,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// without its row the hold would never expire, so the event is taken back when the row can't be written
+	if (*event).GetId() == nil || (*event).GetICalUId() == nil {
+		h.discardHoldEvent(hold.UserId, *event)
+		w.WriteHeader(http.StatusBadGateway)
+		response := map[string]string{"error": "Microsoft Graph returned the event without its ids"}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	hold.EventId = *(*event).GetId()
+	hold.ICalUid = *(*event).GetICalUId()
+	err = h.repo.CreateHold(hold)
+	if err != nil {
+		h.discardHoldEvent(hold.UserId, *event)
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// the hold stands from here on, a copy that can't be stored yet arrives with the next delta
+	err = h.storeEvent(*event, hold.UserId)
+	if err != nil {
+		log.Printf("hold %s: could not store event %s: %s", hold.ID, hold.EventId, err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(responseDto.NewHoldResponseDto(*hold))
+}
+
+func (h *Handler) GetHold(w http.ResponseWriter, r *http.Request) {
+	holdUuid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	hold, err := h.repo.GetHoldById(&holdUuid)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrNotFound {
+			status = http.StatusNotFound
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(responseDto.NewHoldResponseDto(hold))
+}
+
+// ConfirmHold turns the hold into a busy meeting and invites the attendees, it no longer expires
+func (h *Handler) ConfirmHold(w http.ResponseWriter, r *http.Request) {
+	holdUuid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	req := &requestDto.ConfirmHoldDto{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	hold, err := h.repo.GetHoldById(&holdUuid)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrNotFound {
+			status = http.StatusNotFound
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// an expired hold waiting for the job is as good as gone
+	if hold.Status != dto.HoldHeld || !hold.ExpiresAt.After(time.Now()) {
+		status := hold.Status
+		if status == dto.HoldHeld {
+			status = dto.HoldExpired
+		}
+		w.WriteHeader(http.StatusConflict)
+		response := map[string]string{"error": "hold is " + status}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// claim the hold before inviting anyone, the expiry job leaves a confirming hold alone
+	err = h.repo.ClaimHold(&hold.ID, dto.HoldConfirming, time.Now())
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrConflict {
+			status = http.StatusConflict
+			err = errors.New("hold is no longer held")
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	showAs := graphmodels.BUSY_FREEBUSYSTATUS.String()
+	updateEventDto := &requestDto.MGraphUpdateEventDto{
+		UserId:                hold.UserId,
+		EventId:               hold.EventId,
+		Subject:               req.Subject,
+		Content:               req.Content,
+		Locations:             req.Locations,
+		IsOnlineMeeting:       req.IsOnlineMeeting,
+		OnlineMeetingProvider: req.OnlineMeetingProvider,
+		ShowAs:                &showAs,
+	}
+	if len(req.Attendees) > 0 {
+		updateEventDto.Attendees = &req.Attendees
+	}
+
+	event, err := h.client.PatchUpdateEvent(updateEventDto)
+	if err != nil {
+		// nothing was sent, the hold can be confirmed again or expire
+		if releaseErr := h.repo.UpdateHoldStatus(&hold, dto.HoldConfirming); releaseErr != nil {
+			log.Printf("hold %s: could not release the hold: %s", hold.ID, releaseErr)
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// the meeting is confirmed in Graph now, a hold left confirming is never expired
+	confirmedAt := time.Now()
+	hold.Status = dto.HoldConfirmed
+	hold.ConfirmedAt = &confirmedAt
+	if req.Subject != nil {
+		hold.Subject = *req.Subject
+	}
+	err = h.repo.UpdateHoldStatus(&hold, dto.HoldConfirming)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	err = h.storeEvent(event, hold.UserId)
+	if err != nil {
+		log.Printf("hold %s: could not store event %s: %s", hold.ID, hold.EventId, err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(responseDto.NewHoldResponseDto(hold))
+}
+
+// discardHoldEvent deletes the tentative event of a hold that couldn't be recorded, it has no attendees to notify
+func (h *Handler) discardHoldEvent(userId string, event graphmodels.Eventable) {
+	if event.GetId() == nil {
+		return
+	}
+
+	if err := h.client.DeleteEvent(userId, *event.GetId()); err != nil && err != mgraph.ErrNotFound {
+		log.Printf("hold: could not delete event %s: %s", *event.GetId(), err)
+		return
+	}
+
+	if event.GetICalUId() != nil {
+		if err := h.removeEvent(userId, *event.GetICalUId(), dto.EventPayloadDto{Source: dto.EventPayloadFromApi}); err != nil {
+			log.Printf("hold: could not remove event %s: %s", *event.GetId(), err)
+		}
+	}
+}
+
+func newHoldDto(req *requestDto.CreateHoldDto, now time.Time) (*dto.HoldDto, error) {
+	if _, err := uuid.Parse(req.UserId); err != nil {
+		return nil, errors.New("user_id: " + err.Error())
+	}
+	if strings.TrimSpace(req.Subject) == "" {
+		return nil, errors.New("subject: is required")
+	}
+
+	startTime, err := time.Parse(time.RFC3339, req.Start)
+	if err != nil {
+		return nil, errors.New("start: " + err.Error())
+	}
+	endTime, err := time.Parse(time.RFC3339, req.End)
+	if err != nil {
+		return nil, errors.New("end: " + err.Error())
+	}
+	if !endTime.After(startTime) {
+		return nil, errors.New("end: must be after start")
+	}
+
+	expiresAt := now.Add(defaultHoldDuration)
+	if startTime.After(now) && startTime.Before(expiresAt) {
+		expiresAt = startTime
+	}
+	if req.ExpiresAt != nil {
+		expiresAt, err = time.Parse(time.RFC3339, *req.ExpiresAt)
+		if err != nil {
+			return nil, errors.New("expires_at: " + err.Error())
+		}
+	} else if req.ExpiresInMinutes != nil {
+		expiresAt = now.Add(time.Duration(*req.ExpiresInMinutes) * time.Minute)
+	}
+	if !expiresAt.After(now) {
+		return nil, errors.New("expires_at: must be in the future")
+	}
+
+	return &dto.HoldDto{
+		UserId:    req.UserId,
+		Subject:   req.Subject,
+		StartTime: startTime,
+		EndTime:   endTime,
+		ExpiresAt: expiresAt,
+		Status:    dto.HoldHeld,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
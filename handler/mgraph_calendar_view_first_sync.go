@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	graphmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
 	"github.com/scheduler-prototype/dto"
 	requestDto "github.com/scheduler-prototype/dto/request"
 	"github.com/scheduler-prototype/utility"
@@ -32,16 +33,24 @@ func (h *Handler) MGraphCalendarViewFirstSync(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	_, err = h.repo.GetUserByUserId(&newUserUuid)
+	existingUser, err := h.repo.GetUserByUserId(&newUserUuid)
 	if err != nil {
 		if err == utility.ErrNotFound {
 			// create user if not exists
 			newUser := &dto.UserDto{
-				UserId:        newUserUuid,
-				CurrentDelta:  nil,
-				PreviousDelta: nil,
-				CreatedAt:     time.Now(),
-				UpdatedAt:     time.Now(),
+				UserId:               newUserUuid,
+				CurrentDelta:         nil,
+				PreviousDelta:        nil,
+				CreatedAt:            time.Now(),
+				UpdatedAt:            time.Now(),
+				SyncWindowPastDays:   utility.DefaultSyncWindowPastDays,
+				SyncWindowFutureDays: utility.DefaultSyncWindowFutureDays,
+			}
+			if req.SyncWindowPastDays != nil {
+				newUser.SyncWindowPastDays = *req.SyncWindowPastDays
+			}
+			if req.SyncWindowFutureDays != nil {
+				newUser.SyncWindowFutureDays = *req.SyncWindowFutureDays
 			}
 
 			err = h.repo.CreateUser(newUser)
@@ -57,10 +66,26 @@ func (h *Handler) MGraphCalendarViewFirstSync(w http.ResponseWriter, r *http.Req
 			json.NewEncoder(w).Encode(response)
 			return
 		}
+	} else if req.SyncWindowPastDays != nil || req.SyncWindowFutureDays != nil {
+		// update the window of an existing user if a new one was requested
+		if req.SyncWindowPastDays != nil {
+			existingUser.SyncWindowPastDays = *req.SyncWindowPastDays
+		}
+		if req.SyncWindowFutureDays != nil {
+			existingUser.SyncWindowFutureDays = *req.SyncWindowFutureDays
+		}
+
+		err = h.repo.UpdateSyncWindowSettingsByUser(&existingUser)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			response := map[string]string{"error": err.Error()}
+			json.NewEncoder(w).Encode(response)
+			return
+		}
 	}
 
-	// once user is confirmed to be in database, grab the user and make first delta queries to Microsoft Graph
-	userDto, err := h.repo.GetUserByUserId(&newUserUuid)
+	// grab the user's mailbox time zone and working hours, the sync window is computed in that zone
+	mailboxSettings, err := h.client.GetMailboxSettings(newUserUuid.String())
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		response := map[string]string{"error": err.Error()}
@@ -68,22 +93,60 @@ func (h *Handler) MGraphCalendarViewFirstSync(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	// make first delta queries to Microsoft Graph
-	// Get the current time in the user's timezone
-	now := time.Now().UTC().Add(time.Duration(time.Hour * -8))
+	mailboxUser := newMailboxSettingsUserDto(newUserUuid, mailboxSettings)
+	err = h.repo.UpdateMailboxSettingsByUser(mailboxUser)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
 
-	// Calculate the start of the current month
-	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	// keep the user's address so attendees of other users' events can be matched to them
+	graphUser, err := h.client.GetUser(newUserUuid.String())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	emailAddress := graphUser.GetMail()
+	if emailAddress == nil || *emailAddress == "" {
+		emailAddress = graphUser.GetUserPrincipalName()
+	}
+	err = h.repo.UpdateEmailAddressByUser(&dto.UserDto{UserId: newUserUuid, EmailAddress: emailAddress})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// once user is confirmed to be in database, grab the user and make first delta queries to Microsoft Graph
+	userDto, err := h.repo.GetUserByUserId(&newUserUuid)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
 
-	// Calculate the start of the next month
-	nextMonth := now.AddDate(0, 1, 0)
-	startOfNextMonth := time.Date(nextMonth.Year(), nextMonth.Month(), 1, 0, 0, 0, 0, time.UTC)
+	// availability rules start out as the mailbox working hours
+	err = h.seedAvailability(userDto)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
 
-	// Calculate the end of the next month
-	endOfNextMonth := startOfNextMonth.Add(-time.Second)
+	// make first delta queries to Microsoft Graph
+	// -- the window is aligned to midnight in the user's mailbox time zone
+	syncWindowStart, syncWindowEnd := utility.SyncWindow(userDto.MailboxTimezone, userDto.SyncWindowPastDays, userDto.SyncWindowFutureDays, time.Now())
 
 	requestStart := time.Now()
-	deltaLink, events, err := h.client.GetCalendarViewDelta(startOfMonth.Format(time.RFC3339), endOfNextMonth.Format(time.RFC3339), userDto)
+	deltaLink, pages, err := h.client.GetCalendarViewDelta(syncWindowStart.Format(time.RFC3339), syncWindowEnd.Format(time.RFC3339), userDto)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		response := map[string]string{"error": err.Error()}
@@ -95,137 +158,17 @@ func (h *Handler) MGraphCalendarViewFirstSync(w http.ResponseWriter, r *http.Req
 	// Print the time the request took
 	fmt.Printf("Graph Delta Request took: %s\n", requestDuration)
 
-	for _, event := range *events {
-		iCalUid := event.GetICalUId()
-
-		// Check if event already exists in DB
-		_, err := h.repo.GetEventByICalUid(*iCalUid)
-		if err != nil {
-			if err == utility.ErrNotFound {
-				var meetingUrl *string
-				if event.GetOnlineMeeting() != nil {
-					meetingUrl = event.GetOnlineMeeting().GetJoinUrl()
-				} else {
-					meetingUrl = nil
-				}
-				// Event creation
-				eventDto := &dto.MGraphEventDto{
-					UserId:          "1",
-					ICalUid:         *iCalUid,
-					EventId:         *event.GetId(),
-					Title:           *event.GetSubject(),
-					Description:     *event.GetBody().GetContent(),
-					LocationsCount:  len(event.GetLocations()),
-					StartTime:       *event.GetStart().GetDateTime(),
-					EndTime:         *event.GetEnd().GetDateTime(),
-					IsOnline:        *event.GetIsOnlineMeeting(),
-					IsAllDay:        *event.GetIsAllDay(),
-					IsCancelled:     *event.GetIsCancelled(),
-					OrganizerUserId: "1",
-					CreatedTime:     *event.GetCreatedDateTime(),
-					UpdatedTime:     *event.GetLastModifiedDateTime(),
-					Timezone:        *event.GetStart().GetTimeZone(),
-					PlatformUrl:     *event.GetWebLink(),
-					MeetingUrl:      meetingUrl,
-					Type:            event.GetTypeEscaped().String(),
-					IsRecurring:     event.GetSeriesMasterId() != nil,
-					SeriesMasterId:  event.GetSeriesMasterId(),
-					CreatedAt:       time.Now(),
-					UpdatedAt:       time.Now(),
-				}
-
-				err = h.repo.CreateEvent(eventDto)
-				if err != nil {
-					w.WriteHeader(http.StatusInternalServerError)
-					response := map[string]string{"error": err.Error()}
-					json.NewEncoder(w).Encode(response)
-					return
-				}
-
-				// Attendees creation
-				for _, attendee := range event.GetAttendees() {
-					emailAddress := *attendee.GetEmailAddress().GetAddress()
-					_, err := h.repo.GetAttendeeByICalUidAndEmailAddress(*iCalUid, emailAddress)
-					if err != nil {
-						if err == utility.ErrNotFound {
-							attendeeDto := &dto.MGraphAttendeeDto{
-								UserId:       "1",
-								Name:         *attendee.GetEmailAddress().GetName(),
-								EmailAddress: *attendee.GetEmailAddress().GetAddress(),
-								ICalUid:      *iCalUid,
-								CreatedAt:    time.Now(),
-								UpdatedAt:    time.Now(),
-							}
-							err := h.repo.CreateAttendee(attendeeDto)
-							if err != nil {
-								w.WriteHeader(http.StatusInternalServerError)
-								response := map[string]string{"error": err.Error()}
-								json.NewEncoder(w).Encode(response)
-								return
-							}
-						} else {
-							w.WriteHeader(http.StatusInternalServerError)
-							response := map[string]string{"error": err.Error()}
-							json.NewEncoder(w).Encode(response)
-							return
-						}
-					}
-				}
-
-				// Location creation
-				if len(event.GetLocations()) > 0 {
-					for _, location := range event.GetLocations() {
-						displayName := location.GetDisplayName()
-						_, err := h.repo.GetLocationByICalUidAndDisplayName(*iCalUid, *displayName)
-						if err != nil {
-							if err == utility.ErrNotFound {
-								var address *string
-								// combine address props to create a single string
-								street := *location.GetAddress().GetStreet()
-								city := *location.GetAddress().GetCity()
-								state := *location.GetAddress().GetState()
-								postalCode := *location.GetAddress().GetPostalCode()
-								country := *location.GetAddress().GetCountryOrRegion()
-
-								fullAddress := street + ", " + city + ", " + state + ", " + postalCode + ", " + country
-								if fullAddress == ", , , , " {
-									address = nil
-								} else {
-									address = &fullAddress
-								}
-
-								locationDto := &dto.MGraphLocationDto{
-									ICalUid:     *iCalUid,
-									DisplayName: *displayName,
-									LocationUri: location.GetLocationUri(),
-									Address:     address,
-								}
-
-								err := h.repo.CreateLocation(locationDto)
-								if err != nil {
-									w.WriteHeader(http.StatusInternalServerError)
-									response := map[string]string{"error": err.Error()}
-									json.NewEncoder(w).Encode(response)
-									return
-								}
-							} else {
-								w.WriteHeader(http.StatusInternalServerError)
-								response := map[string]string{"error": err.Error()}
-								json.NewEncoder(w).Encode(response)
-								return
-							}
-						}
-
-					}
-				}
-			} else {
+	syncRunId := uuid.New()
+	for _, page := range pages {
+		for _, event := range page.Events {
+			err := h.storeEventFrom(event, newUserUuid.String(), newDeltaEventPayloadDto(syncRunId, page))
+			if err != nil {
 				w.WriteHeader(http.StatusInternalServerError)
 				response := map[string]string{"error": err.Error()}
 				json.NewEncoder(w).Encode(response)
 				return
 			}
 		}
-		continue
 	}
 	log.Println("completed processing events")
 
@@ -239,6 +182,17 @@ func (h *Handler) MGraphCalendarViewFirstSync(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	// remember the window the delta link is bound to
+	userDto.SyncWindowStart = &syncWindowStart
+	userDto.SyncWindowEnd = &syncWindowEnd
+	err = h.repo.UpdateSyncWindowByUser(&userDto)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
 	// Create subscription for the user
 	userUuid := userDto.UserId.String()
 	// create request to Microsoft Graph to create the event
@@ -273,3 +227,33 @@ func (h *Handler) MGraphCalendarViewFirstSync(w http.ResponseWriter, r *http.Req
 	response := map[string]string{"message": "Events successfully synced", "data": *deltaLink}
 	json.NewEncoder(w).Encode(response)
 }
+
+func newMailboxSettingsUserDto(userId uuid.UUID, mailboxSettings graphmodels.MailboxSettingsable) *dto.UserDto {
+	userDto := &dto.UserDto{
+		UserId:          userId,
+		MailboxTimezone: mailboxSettings.GetTimeZone(),
+	}
+
+	workingHours := mailboxSettings.GetWorkingHours()
+	if workingHours == nil {
+		return userDto
+	}
+
+	// Graph sends times as 15:04:05.0000000
+	if workingHours.GetStartTime() != nil {
+		startTime := workingHours.GetStartTime().String()[:8]
+		userDto.WorkingHoursStart = &startTime
+	}
+	if workingHours.GetEndTime() != nil {
+		endTime := workingHours.GetEndTime().String()[:8]
+		userDto.WorkingHoursEnd = &endTime
+	}
+	for _, dayOfWeek := range workingHours.GetDaysOfWeek() {
+		userDto.WorkingDays = append(userDto.WorkingDays, dayOfWeek.String())
+	}
+	if workingHours.GetTimeZone() != nil {
+		userDto.WorkingHoursTimezone = workingHours.GetTimeZone().GetName()
+	}
+
+	return userDto
+}
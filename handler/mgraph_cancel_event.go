@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/scheduler-prototype/dto"
+	requestDto "github.com/scheduler-prototype/dto/request"
+	"github.com/scheduler-prototype/utility"
+)
+
+func (h *Handler) MGraphCancelEvent(w http.ResponseWriter, r *http.Request) {
+	// read the request body and create a MGraphCancelEventDto
+	req := &requestDto.MGraphCancelEventDto{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// grab the event before cancelling so a cancelled occurrence can be recorded against its series
+	event, err := h.client.GetEvent(req.UserId, req.EventId, nil)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// create request to Microsoft Graph to cancel the event or occurrence
+	err = h.client.PostCancelEvent(req.UserId, req.EventId, req.Comment)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// keep the cancelled rows so the series still shows which occurrences were dropped
+	err = h.cancelEvent(req.EventId, dto.EventPayloadDto{Source: dto.EventPayloadFromApi})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if event.GetSeriesMasterId() != nil && event.GetOriginalStart() != nil {
+		series, err := h.repo.GetEventSeriesBySeriesMasterId(*event.GetSeriesMasterId())
+		if err != nil && err != utility.ErrNotFound {
+			w.WriteHeader(http.StatusInternalServerError)
+			response := map[string]string{"error": err.Error()}
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+
+		if err == nil {
+			exceptionDto := &dto.MGraphEventSeriesExceptionDto{
+				EventSeriesId: series.ID,
+				EventId:       req.EventId,
+				ExceptionType: dto.CancelledExceptionType,
+				OriginalStart: *event.GetOriginalStart(),
+				CreatedAt:     time.Now(),
+				UpdatedAt:     time.Now(),
+			}
+			err = h.repo.UpsertEventSeriesException(exceptionDto)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				response := map[string]string{"error": err.Error()}
+				json.NewEncoder(w).Encode(response)
+				return
+			}
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+	response := map[string]string{"message": "Event successfully cancelled"}
+	json.NewEncoder(w).Encode(response)
+}
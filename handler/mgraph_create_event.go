@@ -5,10 +5,11 @@ import (
 	"net/http"
 
 	requestDto "github.com/scheduler-prototype/dto/request"
+	responseDto "github.com/scheduler-prototype/dto/response"
+	"github.com/scheduler-prototype/mgraph"
 )
 
 func (h *Handler) MGraphCreateEvent(w http.ResponseWriter, r *http.Request) {
-	// TODO: Implement this handler
 	// read the request body and create a MGraphCreateEventDto
 	req := &requestDto.MGraphCreateEventDto{}
 	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
@@ -18,6 +19,50 @@ func (h *Handler) MGraphCreateEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	conflictPolicy, err := parseConflictPolicy(req.ConflictPolicy)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	organizerUserId := mgraph.DefaultUserId
+	if req.UserId != nil {
+		organizerUserId = *req.UserId
+	}
+
+	// check the organizer and internal attendees against their synced events
+	// -- for recurring events only the first occurrence is checked
+	conflicts := []responseDto.EventConflictResponseDto{}
+	if conflictPolicy != requestDto.ConflictPolicyIgnore {
+		startTime, endTime, err := eventRange(req.StartTime, req.EndTime, req.TimeZone, req.IsAllDay)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			response := map[string]string{"error": err.Error()}
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+
+		attendeeEmails := []string{}
+		for _, attendee := range req.Attendees {
+			attendeeEmails = append(attendeeEmails, attendee.EmailAddress)
+		}
+
+		conflicts, err = h.eventConflicts(h.callerUserId(r), organizerUserId, attendeeEmails, startTime, endTime, nil)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			response := map[string]string{"error": err.Error()}
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+
+		if conflictPolicy == requestDto.ConflictPolicyReject && len(conflicts) > 0 {
+			writeConflictResponse(w, conflicts)
+			return
+		}
+	}
+
 	// create request to Microsoft Graph to create the event
 	event, err := h.client.PostCreateEvent(req)
 	if err != nil {
@@ -27,6 +72,28 @@ func (h *Handler) MGraphCreateEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// reflect the new event locally instead of waiting for the next delta
+	err = h.storeEvent(*event, organizerUserId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if conflictPolicy == requestDto.ConflictPolicyWarn {
+		eventJson, err := serializeGraphModel(*event)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			response := map[string]string{"error": err.Error()}
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+
+		writeEventWithConflicts(w, eventJson, conflicts)
+		return
+	}
+
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(event)
 }
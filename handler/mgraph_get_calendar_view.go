@@ -8,7 +8,7 @@ import (
 
 	msjson "github.com/microsoft/kiota-serialization-json-go"
 	"github.com/scheduler-prototype/dto"
-	"github.com/scheduler-prototype/utility"
+	"github.com/scheduler-prototype/mgraph"
 )
 
 func (h *Handler) MGraphGetCalendarView(w http.ResponseWriter, r *http.Request) {
@@ -44,136 +44,13 @@ func (h *Handler) MGraphGetCalendarView(w http.ResponseWriter, r *http.Request)
 
 	// Iterating over events
 	for _, event := range events.GetValue() {
-		iCalUid := event.GetICalUId()
-
-		// Check if event already exists in DB
-		_, err := h.repo.GetEventByICalUid(*iCalUid)
+		err := h.storeEventFrom(event, mgraph.DefaultUserId, dto.EventPayloadDto{Source: dto.EventPayloadFromCalendarView})
 		if err != nil {
-			if err == utility.ErrNotFound {
-				var meetingUrl *string
-				if event.GetOnlineMeeting() != nil {
-					meetingUrl = event.GetOnlineMeeting().GetJoinUrl()
-				} else {
-					meetingUrl = nil
-				}
-				// Event creation
-				eventDto := &dto.MGraphEventDto{
-					UserId:          "1",
-					ICalUid:         *iCalUid,
-					EventId:         *event.GetId(),
-					Title:           *event.GetSubject(),
-					Description:     *event.GetBody().GetContent(),
-					LocationsCount:  len(event.GetLocations()),
-					StartTime:       *event.GetStart().GetDateTime(),
-					EndTime:         *event.GetEnd().GetDateTime(),
-					IsOnline:        *event.GetIsOnlineMeeting(),
-					IsAllDay:        *event.GetIsAllDay(),
-					IsCancelled:     *event.GetIsCancelled(),
-					OrganizerUserId: "1",
-					CreatedTime:     *event.GetCreatedDateTime(),
-					UpdatedTime:     *event.GetLastModifiedDateTime(),
-					Timezone:        *event.GetStart().GetTimeZone(),
-					PlatformUrl:     *event.GetWebLink(),
-					MeetingUrl:      meetingUrl,
-					Type:            event.GetTypeEscaped().String(),
-					IsRecurring:     event.GetSeriesMasterId() != nil,
-					SeriesMasterId:  event.GetSeriesMasterId(),
-					CreatedAt:       time.Now(),
-					UpdatedAt:       time.Now(),
-				}
-
-				err = h.repo.CreateEvent(eventDto)
-				if err != nil {
-					w.WriteHeader(http.StatusInternalServerError)
-					response := map[string]string{"error": err.Error()}
-					json.NewEncoder(w).Encode(response)
-					return
-				}
-
-				// Attendees creation
-				for _, attendee := range event.GetAttendees() {
-					emailAddress := *attendee.GetEmailAddress().GetAddress()
-					_, err := h.repo.GetAttendeeByICalUidAndEmailAddress(*iCalUid, emailAddress)
-					if err != nil {
-						if err == utility.ErrNotFound {
-							attendeeDto := &dto.MGraphAttendeeDto{
-								UserId:       "1",
-								Name:         *attendee.GetEmailAddress().GetName(),
-								EmailAddress: *attendee.GetEmailAddress().GetAddress(),
-								ICalUid:      *iCalUid,
-								CreatedAt:    time.Now(),
-								UpdatedAt:    time.Now(),
-							}
-							err := h.repo.CreateAttendee(attendeeDto)
-							if err != nil {
-								w.WriteHeader(http.StatusInternalServerError)
-								response := map[string]string{"error": err.Error()}
-								json.NewEncoder(w).Encode(response)
-								return
-							}
-						} else {
-							w.WriteHeader(http.StatusInternalServerError)
-							response := map[string]string{"error": err.Error()}
-							json.NewEncoder(w).Encode(response)
-							return
-						}
-					}
-				}
-
-				// Location creation
-				if len(event.GetLocations()) > 0 {
-					for _, location := range event.GetLocations() {
-						displayName := location.GetDisplayName()
-						_, err := h.repo.GetLocationByICalUidAndDisplayName(*iCalUid, *displayName)
-						if err != nil {
-							if err == utility.ErrNotFound {
-								var address *string
-								// combine address props to create a single string
-								street := *location.GetAddress().GetStreet()
-								city := *location.GetAddress().GetCity()
-								state := *location.GetAddress().GetState()
-								postalCode := *location.GetAddress().GetPostalCode()
-								country := *location.GetAddress().GetCountryOrRegion()
-
-								fullAddress := street + ", " + city + ", " + state + ", " + postalCode + ", " + country
-								if fullAddress == ", , , , " {
-									address = nil
-								} else {
-									address = &fullAddress
-								}
-
-								locationDto := &dto.MGraphLocationDto{
-									ICalUid:     *iCalUid,
-									DisplayName: *displayName,
-									LocationUri: location.GetLocationUri(),
-									Address:     address,
-								}
-
-								err := h.repo.CreateLocation(locationDto)
-								if err != nil {
-									w.WriteHeader(http.StatusInternalServerError)
-									response := map[string]string{"error": err.Error()}
-									json.NewEncoder(w).Encode(response)
-									return
-								}
-							} else {
-								w.WriteHeader(http.StatusInternalServerError)
-								response := map[string]string{"error": err.Error()}
-								json.NewEncoder(w).Encode(response)
-								return
-							}
-						}
-
-					}
-				}
-			} else {
-				w.WriteHeader(http.StatusInternalServerError)
-				response := map[string]string{"error": err.Error()}
-				json.NewEncoder(w).Encode(response)
-				return
-			}
+			w.WriteHeader(http.StatusInternalServerError)
+			response := map[string]string{"error": err.Error()}
+			json.NewEncoder(w).Encode(response)
+			return
 		}
-		continue
 	}
 
 	// Print the time the request took
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	requestDto "github.com/scheduler-prototype/dto/request"
+	responseDto "github.com/scheduler-prototype/dto/response"
+	"github.com/scheduler-prototype/freebusy"
+	"github.com/scheduler-prototype/mgraph"
+)
+
+// MGraphGetSchedule looks up the free/busy of users we don't sync through Graph's getSchedule
+// -- the answer has the same shape as GET /users/{id}/freebusy, one entry per requested address
+func (h *Handler) MGraphGetSchedule(w http.ResponseWriter, r *http.Request) {
+	req := &requestDto.MGraphGetScheduleDto{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	startTime, endTime, interval, err := parseScheduleRequest(req)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	userId := mgraph.DefaultUserId
+	if req.UserId != nil {
+		userId = *req.UserId
+	}
+
+	schedules, err := h.client.GetSchedule(userId, req.Schedules, startTime, endTime, int32(interval/time.Minute))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == mgraph.ErrTooManySchedules {
+			status = http.StatusBadRequest
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	scheduleResponses := []responseDto.FreeBusyResponseDto{}
+	for _, schedule := range schedules {
+		scheduleId := ""
+		if schedule.GetScheduleId() != nil {
+			scheduleId = *schedule.GetScheduleId()
+		}
+
+		if schedule.GetError() != nil {
+			scheduleResponse := responseDto.NewFreeBusyResponseDto(scheduleId, startTime, endTime, interval, nil, nil)
+			scheduleResponse.AvailabilityView = ""
+			scheduleResponse.Error = schedule.GetError().GetMessage()
+			scheduleResponses = append(scheduleResponses, scheduleResponse)
+			continue
+		}
+
+		busyIntervals := scheduleBusyIntervals(schedule, startTime, endTime)
+		scheduleResponses = append(scheduleResponses, responseDto.NewFreeBusyResponseDto(scheduleId, startTime, endTime, interval, busyIntervals, nil))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(scheduleResponses)
+}
+
+func parseScheduleRequest(req *requestDto.MGraphGetScheduleDto) (time.Time, time.Time, time.Duration, error) {
+	if len(req.Schedules) == 0 {
+		return time.Time{}, time.Time{}, 0, errors.New("schedules: at least one address is required")
+	}
+	if len(req.Schedules) > mgraph.MaxScheduleAddresses {
+		return time.Time{}, time.Time{}, 0, mgraph.ErrTooManySchedules
+	}
+
+	startTime, err := parseTimeParam(req.Start)
+	if err != nil {
+		return time.Time{}, time.Time{}, 0, errors.New("start: " + err.Error())
+	}
+
+	endTime, err := parseTimeParam(req.End)
+	if err != nil {
+		return time.Time{}, time.Time{}, 0, errors.New("end: " + err.Error())
+	}
+	if err := freebusy.ValidateRange(startTime, endTime); err != nil {
+		return time.Time{}, time.Time{}, 0, err
+	}
+
+	interval := freebusy.DefaultInterval
+	if req.Interval != nil {
+		interval = time.Duration(*req.Interval) * time.Minute
+	}
+	if err := freebusy.ValidateInterval(interval); err != nil {
+		return time.Time{}, time.Time{}, 0, errors.New("interval: " + err.Error())
+	}
+
+	return startTime, endTime, interval, nil
+}
@@ -1,12 +1,19 @@
 package handler
 
 import (
+	"crypto/subtle"
 	"encoding/json"
-	"fmt"
-	"io"
 	"log"
 	"net/http"
 	"net/url"
+	"os"
+
+	graphmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/scheduler-prototype/dto"
+	requestDto "github.com/scheduler-prototype/dto/request"
+	"github.com/scheduler-prototype/mapper"
+	"github.com/scheduler-prototype/mgraph"
+	"github.com/scheduler-prototype/utility"
 )
 
 func (h *Handler) MGraphHandleCalendarViewNotification(w http.ResponseWriter, r *http.Request) {
@@ -31,20 +38,112 @@ func (h *Handler) MGraphHandleCalendarViewNotification(w http.ResponseWriter, r
 		return
 	}
 
-	// Read the request body
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		http.Error(w, "Error reading request body", http.StatusInternalServerError)
+	// otherwise, apply the changes -- a failure answers 500 so Graph delivers the batch again
+	req := &requestDto.MGraphCalendarViewNotificationDto{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// without a secret any caller could pass for Graph, nothing is applied then
+	clientState := os.Getenv("AZURE_CLIENT_STATE_SECRET")
+	if clientState == "" {
+		w.WriteHeader(http.StatusForbidden)
+		response := map[string]string{"error": "AZURE_CLIENT_STATE_SECRET is not set, notifications are rejected"}
+		json.NewEncoder(w).Encode(response)
 		return
 	}
 
-	// Print the raw JSON data
-	fmt.Println("Received Raw JSON Request Body:")
-	fmt.Println(string(body))
+	for _, notification := range req.Value {
+		if subtle.ConstantTimeCompare([]byte(notification.ClientState), []byte(clientState)) != 1 {
+			log.Printf("notification for subscription %s: skipped, client state doesn't match", notification.SubscriptionId)
+			continue
+		}
 
-	// otherwise, check notification and update accordingly
+		err := h.applyChangeNotification(notification)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			response := map[string]string{"error": err.Error()}
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+	}
 
 	w.WriteHeader(http.StatusOK)
 	w.Header().Set("Content-Type", "text/plain")
 	w.Write([]byte("OK"))
 }
+
+// applyChangeNotification brings the user's copy of the notified event up to date,
+// notifications only carry the event id so created and updated events are read back from Graph
+func (h *Handler) applyChangeNotification(notification requestDto.MGraphChangeNotificationDto) error {
+	user, err := h.repo.GetUserBySubscriptionId(notification.SubscriptionId)
+	if err == utility.ErrNotFound {
+		log.Printf("notification for subscription %s: skipped, no user has it", notification.SubscriptionId)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	userId := user.UserId.String()
+	source := dto.EventPayloadDto{Source: dto.EventPayloadFromNotification}
+
+	if notification.ChangeType == "deleted" {
+		event, err := h.repo.GetEventByUserIdAndEventId(userId, notification.ResourceData.Id)
+		if err == utility.ErrNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		return h.removeEvent(userId, event.ICalUid, source)
+	}
+
+	// an event deleted since is left to its own deleted notification
+	event, err := h.client.GetEvent(userId, notification.ResourceData.Id, nil)
+	if err == mgraph.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// series masters aren't part of the windowed delta, their occurrences arrive with it
+	if event.GetTypeEscaped() != nil && *event.GetTypeEscaped() == graphmodels.SERIESMASTER_EVENTTYPE {
+		return nil
+	}
+
+	// the subscription covers the whole calendar, only what the sync window holds is kept as the delta does
+	// -- an event moved out of the window leaves it the same way
+	if !inSyncWindow(user, event) {
+		existingEvent, err := h.repo.GetEventByUserIdAndEventId(userId, notification.ResourceData.Id)
+		if err == utility.ErrNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		return h.removeEvent(userId, existingEvent.ICalUid, source)
+	}
+
+	return h.storeEventFrom(event, userId, source)
+}
+
+// inSyncWindow tells whether the event overlaps the user's sync window,
+// events that can't be mapped are left to storeEventFrom to report
+func inSyncWindow(user dto.UserDto, event graphmodels.Eventable) bool {
+	if user.SyncWindowStart == nil || user.SyncWindowEnd == nil {
+		return true
+	}
+
+	eventDto, _, err := mapper.Event(event, user.UserId.String())
+	if err != nil {
+		return true
+	}
+
+	return eventDto.StartTime.Before(*user.SyncWindowEnd) && eventDto.EndTime.After(*user.SyncWindowStart)
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	graphmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/scheduler-prototype/dto"
+	requestDto "github.com/scheduler-prototype/dto/request"
+	"github.com/scheduler-prototype/mgraph"
+	"github.com/scheduler-prototype/utility"
+)
+
+func (h *Handler) MGraphAcceptEvent(w http.ResponseWriter, r *http.Request) {
+	h.respondToEvent(w, r, graphmodels.ACCEPTED_RESPONSETYPE)
+}
+
+func (h *Handler) MGraphDeclineEvent(w http.ResponseWriter, r *http.Request) {
+	h.respondToEvent(w, r, graphmodels.DECLINED_RESPONSETYPE)
+}
+
+func (h *Handler) MGraphTentativelyAcceptEvent(w http.ResponseWriter, r *http.Request) {
+	h.respondToEvent(w, r, graphmodels.TENTATIVELYACCEPTED_RESPONSETYPE)
+}
+
+// respondToEvent answers an invitation in the user's calendar and records the response locally right away
+func (h *Handler) respondToEvent(w http.ResponseWriter, r *http.Request, responseType graphmodels.ResponseType) {
+	// read the request body and create a MGraphRespondEventDto
+	req := &requestDto.MGraphRespondEventDto{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	var proposedStartTime, proposedEndTime *time.Time
+	if req.ProposedNewTime != nil {
+		startTime, endTime, err := eventRange(req.ProposedNewTime.StartTime, req.ProposedNewTime.EndTime, req.ProposedNewTime.TimeZone, false)
+		if err == nil && !endTime.After(startTime) {
+			err = errors.New("end_time must be after start_time")
+		}
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			response := map[string]string{"error": "proposed_new_time: " + err.Error()}
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		proposedStartTime, proposedEndTime = &startTime, &endTime
+	}
+
+	var err error
+	switch responseType {
+	case graphmodels.ACCEPTED_RESPONSETYPE:
+		err = h.client.AcceptEvent(req)
+	case graphmodels.DECLINED_RESPONSETYPE:
+		err = h.client.DeclineEvent(req)
+	case graphmodels.TENTATIVELYACCEPTED_RESPONSETYPE:
+		err = h.client.TentativelyAcceptEvent(req)
+	}
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == mgraph.ErrProposedNewTimeNotAllowed {
+			status = http.StatusBadRequest
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// reflect the response locally instead of waiting for the next delta
+	err = h.storeEventResponse(req.UserId, req.EventId, responseType, proposedStartTime, proposedEndTime)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	response := map[string]string{"message": "Event successfully " + responseType.String()}
+	json.NewEncoder(w).Encode(response)
+}
+
+// storeEventResponse updates the user's attendee row of the event and adds the change to the response history,
+// events or users that aren't synced are left to the delta
+// -- a declined event leaves the user's calendar, so their copy is removed as well
+func (h *Handler) storeEventResponse(userId string, eventId string, responseType graphmodels.ResponseType, proposedStartTime *time.Time, proposedEndTime *time.Time) error {
+	userUuid, err := uuid.Parse(userId)
+	if err != nil {
+		return nil
+	}
+
+	user, err := h.repo.GetUserByUserId(&userUuid)
+	if err != nil {
+		if err == utility.ErrNotFound {
+			return nil
+		}
+		return err
+	}
+
+	event, err := h.repo.GetEventByEventId(eventId)
+	if err != nil {
+		if err == utility.ErrNotFound {
+			return nil
+		}
+		return err
+	}
+
+	if user.EmailAddress != nil {
+		attendees, err := h.repo.GetAttendeesByICalUid(event.ICalUid)
+		if err != nil {
+			return err
+		}
+
+		// Graph may return the address in a different case than the user's
+		for _, attendee := range attendees {
+			if !strings.EqualFold(attendee.EmailAddress, *user.EmailAddress) {
+				continue
+			}
+
+			responseStatus := responseType.String()
+			responseTime := time.Now().UTC()
+			attendee.ResponseStatus = &responseStatus
+			attendee.ResponseTime = &responseTime
+			attendee.ProposedStartTime = proposedStartTime
+			attendee.ProposedEndTime = proposedEndTime
+			attendee.UpdatedAt = time.Now()
+
+			err = h.repo.UpdateAttendee(&attendee, newAttendeeResponseHistoryDto(attendee, dto.ResponseFromApi))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if responseType == graphmodels.DECLINED_RESPONSETYPE {
+		return h.removeEvent(userId, event.ICalUid, dto.EventPayloadDto{Source: dto.EventPayloadFromApi})
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/microsoft/kiota-abstractions-go/serialization"
+	msjson "github.com/microsoft/kiota-serialization-json-go"
+	graphmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+)
+
+// serializeGraphModel converts a Microsoft Graph model into its JSON representation
+func serializeGraphModel(model serialization.Parsable) ([]byte, error) {
+	serializer := msjson.NewJsonSerializationWriter()
+	if err := serializer.WriteObjectValue("", model); err != nil {
+		return nil, err
+	}
+
+	return serializer.GetSerializedContent()
+}
+
+// parseGraphEvent reads an event back from the JSON serializeGraphModel wrote
+func parseGraphEvent(content []byte) (graphmodels.Eventable, error) {
+	parseNode, err := msjson.NewJsonParseNode(content)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := parseNode.GetObjectValue(graphmodels.CreateEventFromDiscriminatorValue)
+	if err != nil {
+		return nil, err
+	}
+
+	event, ok := value.(graphmodels.Eventable)
+	if !ok {
+		return nil, errors.New("payload is not an event")
+	}
+
+	return event, nil
+}
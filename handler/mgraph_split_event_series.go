@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/scheduler-prototype/dto"
+	requestDto "github.com/scheduler-prototype/dto/request"
+	"github.com/scheduler-prototype/mgraph"
+	"github.com/scheduler-prototype/timezone"
+	"github.com/scheduler-prototype/utility"
+)
+
+func (h *Handler) MGraphSplitEventSeries(w http.ResponseWriter, r *http.Request) {
+	// read the request body and create a MGraphSplitEventSeriesDto
+	req := &requestDto.MGraphSplitEventSeriesDto{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// grab the occurrence before splitting, it no longer exists in the truncated series afterwards
+	occurrence, err := h.client.GetEvent(req.UserId, req.EventId, nil)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == mgraph.ErrNotFound {
+			status = http.StatusNotFound
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+	start := occurrence.GetStart()
+	if start == nil || start.GetDateTime() == nil || start.GetTimeZone() == nil {
+		w.WriteHeader(http.StatusBadGateway)
+		response := map[string]string{"error": mgraph.ErrIncompleteEvent.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+	splitStartTime, err := timezone.ParseDateTimeTimeZone(*start.GetDateTime(), *start.GetTimeZone())
+	if err != nil {
+		w.WriteHeader(http.StatusBadGateway)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	truncatedMaster, newMaster, err := h.client.SplitEventSeries(req, occurrence)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == mgraph.ErrSplitAtSeriesStart {
+			status = http.StatusBadRequest
+		} else if err == mgraph.ErrIncompleteEvent {
+			status = http.StatusBadGateway
+		} else if err == mgraph.ErrNotFound {
+			status = http.StatusNotFound
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// the occurrences from the split onwards now belong to the new series
+	// -- remember how far they were synced so the new series is synced over the same range
+	lastEndTime, err := h.repo.GetLastEndTimeBySeriesMasterIdFromStartTime(*truncatedMaster.GetId(), splitStartTime)
+	if err != nil && err != utility.ErrNotFound {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	movedEvents, err := h.repo.GetEventsBySeriesMasterIdFromStartTime(*truncatedMaster.GetId(), splitStartTime)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	for _, movedEvent := range movedEvents {
+		err = h.removeEvent(movedEvent.UserId, movedEvent.ICalUid, dto.EventPayloadDto{Source: dto.EventPayloadFromApi})
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			response := map[string]string{"error": err.Error()}
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+	}
+
+	// exceptions recorded from the split onwards belonged to the moved occurrences
+	series, err := h.repo.GetEventSeriesBySeriesMasterId(*truncatedMaster.GetId())
+	if err != nil && err != utility.ErrNotFound {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if err == nil {
+		err = h.repo.DeleteEventSeriesExceptionsFromOriginalStart(series.ID, splitStartTime)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			response := map[string]string{"error": err.Error()}
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+	}
+
+	err = h.storeEvent(truncatedMaster, req.UserId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	err = h.storeEvent(newMaster, req.UserId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if lastEndTime != nil {
+		instances, err := h.client.GetEventSeriesMasterInstance(splitStartTime.Format(time.RFC3339), lastEndTime.UTC().Format(time.RFC3339), req.UserId, *newMaster.GetId())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			response := map[string]string{"error": err.Error()}
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+
+		for _, instance := range instances.GetValue() {
+			err = h.storeEvent(instance, req.UserId)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				response := map[string]string{"error": err.Error()}
+				json.NewEncoder(w).Encode(response)
+				return
+			}
+		}
+	}
+
+	truncatedMasterJson, err := serializeGraphModel(truncatedMaster)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	newMasterJson, err := serializeGraphModel(newMaster)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	response := map[string]json.RawMessage{"truncated_series": truncatedMasterJson, "new_series": newMasterJson}
+	json.NewEncoder(w).Encode(response)
+}
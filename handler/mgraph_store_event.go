@@ -0,0 +1,415 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	graphmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/scheduler-prototype/dto"
+	"github.com/scheduler-prototype/mapper"
+	"github.com/scheduler-prototype/mgraph"
+	"github.com/scheduler-prototype/utility"
+)
+
+// storeEvent stores a graph event that was written through the API rather than synced
+func (h *Handler) storeEvent(event graphmodels.Eventable, userId string) error {
+	return h.storeEventFrom(event, userId, dto.EventPayloadDto{Source: dto.EventPayloadFromApi})
+}
+
+// storeEventFrom keeps the raw graph payload of the event before normalizing it,
+// payload carries where the event came from -- a payload identical to the last one of the copy isn't kept again
+func (h *Handler) storeEventFrom(event graphmodels.Eventable, userId string, payload dto.EventPayloadDto) error {
+	// a delta lists the events that left the calendar or the window as @removed entries carrying only their id
+	if _, removed := event.GetAdditionalData()["@removed"]; removed {
+		return h.removeDeltaEntry(event, userId, payload)
+	}
+
+	// without its ids the payload can't be tied to a copy, the mapping reports and skips the event
+	if event.GetId() == nil || event.GetICalUId() == nil {
+		return h.normalizeEvent(event, userId, payload)
+	}
+
+	eventJson, err := serializeGraphModel(event)
+	if err != nil {
+		return err
+	}
+
+	// the payload holds the body as well, so it's dropped from both when private bodies aren't stored
+	// -- a copy is redacted, callers still hand the event back to its owner
+	if event.GetSensitivity() != nil && *event.GetSensitivity() == graphmodels.PRIVATE_SENSITIVITY && !h.storePrivateEventBodies && event.GetBody() != nil {
+		redactedEvent, err := parseGraphEvent(eventJson)
+		if err != nil {
+			return err
+		}
+
+		if redactedEvent.GetBody() != nil {
+			emptyContent := ""
+			redactedEvent.GetBody().SetContent(&emptyContent)
+			eventJson, err = serializeGraphModel(redactedEvent)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	hash := sha256.Sum256(eventJson)
+	payloadHash := hex.EncodeToString(hash[:])
+
+	latestPayload, err := h.repo.GetLatestEventPayloadByUserIdAndICalUid(userId, *event.GetICalUId())
+	if err != nil && err != utility.ErrNotFound {
+		return err
+	}
+
+	if err == utility.ErrNotFound || latestPayload.PayloadHash != payloadHash || latestPayload.RemovedAt != nil {
+		payload.UserId = userId
+		payload.EventId = *event.GetId()
+		payload.ICalUid = *event.GetICalUId()
+		payload.Payload = eventJson
+		payload.PayloadHash = payloadHash
+		payload.ReceivedAt = time.Now()
+		err = h.repo.CreateEventPayload(&payload)
+		if err != nil {
+			return err
+		}
+	}
+
+	return h.normalizeEvent(event, userId, payload)
+}
+
+// removeDeltaEntry removes the user's copy an @removed entry points at, the entry has no iCalUId so the copy is found by its event id
+func (h *Handler) removeDeltaEntry(event graphmodels.Eventable, userId string, payload dto.EventPayloadDto) error {
+	if event.GetId() == nil {
+		log.Printf("removed delta entry of user %s: skipped, id: missing", userId)
+		return nil
+	}
+
+	existingEvent, err := h.repo.GetEventByUserIdAndEventId(userId, *event.GetId())
+	if err == utility.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return h.removeEvent(userId, existingEvent.ICalUid, payload)
+}
+
+// newDeltaEventPayloadDto describes the payloads of a delta page, syncRunId groups the pages of one delta query
+func newDeltaEventPayloadDto(syncRunId uuid.UUID, page mgraph.DeltaPage) dto.EventPayloadDto {
+	pageNumber := page.Number
+	return dto.EventPayloadDto{
+		Source:        dto.EventPayloadFromDelta,
+		SyncRunId:     &syncRunId,
+		DeltaPage:     &pageNumber,
+		DeltaPageLink: page.Link,
+	}
+}
+
+// normalizeEvent inserts the graph event of the given user along with its attendees and locations,
+// or refreshes the stored event row if the event was already synced
+// -- source tells where the event came from for the revision recorded of the change
+func (h *Handler) normalizeEvent(event graphmodels.Eventable, userId string, source dto.EventPayloadDto) error {
+	// an event that can't be mapped is skipped instead of failing the whole sync, its payload is kept for a reprocess
+	eventDto, warnings, err := mapper.Event(event, userId)
+	if err != nil {
+		log.Printf("mapping event %s of user %s: skipped, %v", graphEventId(event), userId, err)
+		return nil
+	}
+	logMappingWarnings("event "+eventDto.EventId, warnings)
+	iCalUid := &eventDto.ICalUid
+
+	// their body isn't kept at all when configured so
+	if eventDto.IsPrivate && !h.storePrivateEventBodies {
+		eventDto.Description = ""
+	}
+
+	// Series creation for series masters, instances are linked to the series of their master
+	var series *dto.MGraphEventSeriesDto
+	eventType := event.GetTypeEscaped()
+	if eventType != nil && *eventType == graphmodels.SERIESMASTER_EVENTTYPE && event.GetRecurrence() != nil {
+		series, warnings, err = mapper.EventSeries(event)
+		if err != nil {
+			return err
+		}
+		logMappingWarnings("series "+eventDto.EventId, warnings)
+
+		err := h.repo.UpsertEventSeries(series)
+		if err != nil {
+			return err
+		}
+		eventDto.EventSeriesId = &series.ID
+	} else if event.GetSeriesMasterId() != nil {
+		masterSeries, err := h.repo.GetEventSeriesBySeriesMasterId(*event.GetSeriesMasterId())
+		if err != nil && err != utility.ErrNotFound {
+			return err
+		}
+
+		if err == nil {
+			eventDto.EventSeriesId = &masterSeries.ID
+
+			// record the occurrence that was modified into an exception
+			if exceptionDto := mapper.EventSeriesException(event, masterSeries.ID); exceptionDto != nil {
+				err = h.repo.UpsertEventSeriesException(exceptionDto)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	// Check if event already exists in DB for this user
+	existingEvent, err := h.repo.GetEventByUserIdAndICalUid(userId, *iCalUid)
+	if err != nil {
+		if err != utility.ErrNotFound {
+			return err
+		}
+
+		// Event creation, the row and its revision are written together
+		err = h.repo.CreateEvent(eventDto, newEventRevisionDto(dto.EventCreated, nil, eventDto, source))
+		if err != nil {
+			return err
+		}
+	} else {
+		// Event update, keeping the original row identity
+		eventDto.ID = existingEvent.ID
+		eventDto.CreatedAt = existingEvent.CreatedAt
+		err = h.repo.UpdateEvent(eventDto, newEventRevisionDto(dto.EventUpdated, &existingEvent, eventDto, source))
+		if err != nil {
+			return err
+		}
+	}
+
+	// instances synced before their master are linked once the master arrives
+	if series != nil {
+		err = h.repo.LinkEventsToEventSeries(series)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Attendees creation
+	err = h.storeAttendees(event, userId)
+	if err != nil {
+		return err
+	}
+
+	// Location creation
+	for _, location := range event.GetLocations() {
+		locationDto, warnings, err := mapper.Location(location, *iCalUid)
+		if err != nil {
+			log.Printf("mapping location of event %s: skipped, %v", eventDto.EventId, err)
+			continue
+		}
+		logMappingWarnings("location of event "+eventDto.EventId, warnings)
+
+		_, err = h.repo.GetLocationByICalUidAndDisplayName(*iCalUid, locationDto.DisplayName)
+		if err != nil {
+			if err != utility.ErrNotFound {
+				return err
+			}
+
+			err := h.repo.CreateLocation(&locationDto)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// storeAttendees creates or refreshes the attendees of the event and records every response change,
+// only the organizer's copy knows everyone's response -- the other copies report none for the rest
+// and only carry the user's own response
+func (h *Handler) storeAttendees(event graphmodels.Eventable, userId string) error {
+	iCalUid := *event.GetICalUId()
+	isOrganizer := event.GetIsOrganizer() != nil && *event.GetIsOrganizer()
+
+	var userEmailAddress *string
+	if !isOrganizer && event.GetResponseStatus() != nil {
+		userUuid, err := uuid.Parse(userId)
+		if err == nil {
+			user, err := h.repo.GetUserByUserId(&userUuid)
+			if err != nil && err != utility.ErrNotFound {
+				return err
+			}
+			userEmailAddress = user.EmailAddress
+		}
+	}
+
+	for _, attendee := range event.GetAttendees() {
+		incoming, warnings, err := mapper.Attendee(attendee, iCalUid)
+		if err != nil {
+			log.Printf("mapping attendee of event %s: skipped, %v", graphEventId(event), err)
+			continue
+		}
+		logMappingWarnings("attendee "+incoming.EmailAddress+" of event "+graphEventId(event), warnings)
+
+		if userEmailAddress != nil && strings.EqualFold(incoming.EmailAddress, *userEmailAddress) {
+			incoming.ResponseStatus, incoming.ResponseTime = mapper.ResponseStatus(event.GetResponseStatus())
+		}
+
+		// a response of none means nothing was received yet, or the copy doesn't know
+		known := isOrganizer || (incoming.ResponseStatus != nil && *incoming.ResponseStatus != graphmodels.NONE_RESPONSETYPE.String())
+
+		existing, err := h.repo.GetAttendeeByICalUidAndEmailAddress(iCalUid, incoming.EmailAddress)
+		if err != nil {
+			if err != utility.ErrNotFound {
+				return err
+			}
+
+			if !known {
+				incoming.ResponseStatus, incoming.ResponseTime = nil, nil
+			}
+
+			err := h.repo.CreateAttendee(&incoming)
+			if err != nil {
+				return err
+			}
+
+			if incoming.ResponseStatus != nil {
+				err = h.repo.CreateAttendeeResponseHistory(newAttendeeResponseHistoryDto(incoming, dto.ResponseFromSync))
+				if err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		updated := existing
+		updated.Name = incoming.Name
+		updated.AttendeeType = incoming.AttendeeType
+		if known {
+			updated.ResponseStatus = incoming.ResponseStatus
+			updated.ResponseTime = incoming.ResponseTime
+			updated.ProposedStartTime = incoming.ProposedStartTime
+			updated.ProposedEndTime = incoming.ProposedEndTime
+		}
+
+		responseChanged := !equalStringPtr(existing.ResponseStatus, updated.ResponseStatus) ||
+			!equalTimePtr(existing.ProposedStartTime, updated.ProposedStartTime) ||
+			!equalTimePtr(existing.ProposedEndTime, updated.ProposedEndTime)
+		if !responseChanged && existing.Name == updated.Name && equalStringPtr(existing.AttendeeType, updated.AttendeeType) &&
+			equalTimePtr(existing.ResponseTime, updated.ResponseTime) {
+			continue
+		}
+
+		var history *dto.AttendeeResponseHistoryDto
+		if responseChanged && updated.ResponseStatus != nil {
+			history = newAttendeeResponseHistoryDto(updated, dto.ResponseFromSync)
+		}
+
+		updated.UpdatedAt = time.Now()
+		err = h.repo.UpdateAttendee(&updated, history)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func newAttendeeResponseHistoryDto(attendee dto.MGraphAttendeeDto, source string) *dto.AttendeeResponseHistoryDto {
+	return &dto.AttendeeResponseHistoryDto{
+		ICalUid:           attendee.ICalUid,
+		EmailAddress:      attendee.EmailAddress,
+		ResponseStatus:    *attendee.ResponseStatus,
+		ResponseTime:      attendee.ResponseTime,
+		ProposedStartTime: attendee.ProposedStartTime,
+		ProposedEndTime:   attendee.ProposedEndTime,
+		Source:            source,
+		RecordedAt:        time.Now(),
+	}
+}
+
+// logMappingWarnings reports the fields of a graph model that fell back to their default
+func logMappingWarnings(subject string, warnings []mapper.Warning) {
+	for _, warning := range warnings {
+		log.Printf("mapping %s: %s", subject, warning)
+	}
+}
+
+// graphEventId names the event in logs, the id itself may be what's missing
+func graphEventId(event graphmodels.Eventable) string {
+	if event == nil || event.GetId() == nil {
+		return "<no id>"
+	}
+	return *event.GetId()
+}
+
+func equalStringPtr(a *string, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func equalTimePtr(a *time.Time, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// removeEvent deletes the user's copy of the event, source is recorded on its removed revision,
+// attendees and locations are shared between copies and go with the last one
+func (h *Handler) removeEvent(userId string, iCalUid string, source dto.EventPayloadDto) error {
+	existingEvent, err := h.repo.GetEventByUserIdAndICalUid(userId, iCalUid)
+	if err != nil && err != utility.ErrNotFound {
+		return err
+	}
+	wasStored := err == nil
+
+	var revision *dto.EventRevisionDto
+	if wasStored {
+		revision = newEventRevisionDto(dto.EventRemoved, &existingEvent, nil, source)
+	}
+	if err := h.repo.DeleteEventByUserIdAndICalUid(userId, iCalUid, revision); err != nil {
+		return err
+	}
+
+	// a reprocess must not bring the copy back
+	if err := h.repo.MarkEventPayloadsRemoved(userId, iCalUid); err != nil {
+		return err
+	}
+
+	remainingCopies, err := h.repo.CountEventsByICalUid(iCalUid)
+	if err != nil {
+		return err
+	}
+	if remainingCopies > 0 {
+		return nil
+	}
+
+	if err := h.repo.DeleteAttendeesByICalUid(iCalUid); err != nil {
+		return err
+	}
+
+	return h.repo.DeleteLocationsByICalUid(iCalUid)
+}
+
+// cancelEvent marks the stored copies of the event cancelled, the occurrences with it for a series master,
+// each copy that wasn't cancelled yet gets its own revision, written together with the row
+func (h *Handler) cancelEvent(eventId string, source dto.EventPayloadDto) error {
+	events, err := h.repo.GetUncancelledEventsByEventId(eventId)
+	if err != nil {
+		return err
+	}
+
+	for i := range events {
+		before := events[i]
+		after := before
+		after.IsCancelled = true
+		after.UpdatedAt = time.Now()
+
+		err = h.repo.CancelEvent(&after, newEventRevisionDto(dto.EventUpdated, &before, &after, source))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,7 +1,144 @@
 package handler
 
-import "net/http"
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	requestDto "github.com/scheduler-prototype/dto/request"
+	responseDto "github.com/scheduler-prototype/dto/response"
+	"github.com/scheduler-prototype/utility"
+)
 
 func (h *Handler) MGraphUpdateEvent(w http.ResponseWriter, r *http.Request) {
-	// TODO: Implement this handler
+	// read the request body and create a MGraphUpdateEventDto
+	req := &requestDto.MGraphUpdateEventDto{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	conflictPolicy, err := parseConflictPolicy(req.ConflictPolicy)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// only a new time or new attendees can introduce conflicts
+	conflicts := []responseDto.EventConflictResponseDto{}
+	if conflictPolicy != requestDto.ConflictPolicyIgnore && (req.StartTime != nil || req.Attendees != nil) {
+		conflicts, err = h.updateEventConflicts(req, h.callerUserId(r))
+		if err != nil {
+			status := http.StatusInternalServerError
+			if err == errIncompleteEventTime {
+				status = http.StatusBadRequest
+			}
+			w.WriteHeader(status)
+			response := map[string]string{"error": err.Error()}
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+
+		if conflictPolicy == requestDto.ConflictPolicyReject && len(conflicts) > 0 {
+			writeConflictResponse(w, conflicts)
+			return
+		}
+	}
+
+	// create request to Microsoft Graph to update the event
+	// -- updating an occurrence of a series turns it into an exception
+	event, err := h.client.PatchUpdateEvent(req)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// reflect the change locally instead of waiting for the next delta
+	err = h.storeEvent(event, req.UserId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	eventJson, err := serializeGraphModel(event)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if conflictPolicy == requestDto.ConflictPolicyWarn {
+		writeEventWithConflicts(w, eventJson, conflicts)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(eventJson)
+}
+
+var errIncompleteEventTime = errors.New("start_time, end_time and time_zone must be set together")
+
+// updateEventConflicts checks the event as it will be after the update,
+// fields that aren't changed are taken from the synced event
+// -- events that aren't synced yet can only be checked when the new time is sent
+func (h *Handler) updateEventConflicts(req *requestDto.MGraphUpdateEventDto, callerUserId string) ([]responseDto.EventConflictResponseDto, error) {
+	storedEvent, err := h.repo.GetEventByEventId(req.EventId)
+	if err != nil && err != utility.ErrNotFound {
+		return nil, err
+	}
+	isSynced := err == nil
+
+	var startTime, endTime time.Time
+	if req.StartTime != nil {
+		if req.EndTime == nil || req.TimeZone == nil {
+			return nil, errIncompleteEventTime
+		}
+
+		isAllDay := isSynced && storedEvent.IsAllDay
+		if req.IsAllDay != nil {
+			isAllDay = *req.IsAllDay
+		}
+
+		startTime, endTime, err = eventRange(*req.StartTime, *req.EndTime, *req.TimeZone, isAllDay)
+		if err != nil {
+			return nil, err
+		}
+	} else if isSynced {
+		startTime, endTime = storedEvent.StartTime, storedEvent.EndTime
+	} else {
+		return []responseDto.EventConflictResponseDto{}, nil
+	}
+
+	attendeeEmails := []string{}
+	if req.Attendees != nil {
+		for _, attendee := range *req.Attendees {
+			attendeeEmails = append(attendeeEmails, attendee.EmailAddress)
+		}
+	} else if isSynced {
+		attendees, err := h.repo.GetAttendeesByICalUid(storedEvent.ICalUid)
+		if err != nil {
+			return nil, err
+		}
+		for _, attendee := range attendees {
+			attendeeEmails = append(attendeeEmails, attendee.EmailAddress)
+		}
+	}
+
+	var iCalUid *string
+	if isSynced {
+		iCalUid = &storedEvent.ICalUid
+	}
+
+	return h.eventConflicts(callerUserId, req.UserId, attendeeEmails, startTime, endTime, iCalUid)
 }
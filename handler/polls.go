@@ -0,0 +1,605 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"html"
+	"log"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/scheduler-prototype/dto"
+	requestDto "github.com/scheduler-prototype/dto/request"
+	responseDto "github.com/scheduler-prototype/dto/response"
+	"github.com/scheduler-prototype/freebusy"
+	"github.com/scheduler-prototype/utility"
+)
+
+const maxPollOptions = 20
+
+// CreatePoll proposes the options to the invitees, each invitee gets a token to vote with
+// -- the tokens are only returned here
+func (h *Handler) CreatePoll(w http.ResponseWriter, r *http.Request) {
+	userUuid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	req := &requestDto.CreatePollDto{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	poll, options, invitees, inviteTokens, err := newPollDtos(userUuid, req)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// the event is created in the organizer's calendar once the poll is finalized
+	_, err = h.repo.GetUserByUserId(&userUuid)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrNotFound {
+			status = http.StatusNotFound
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	err = h.repo.CreatePoll(poll, options, invitees)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	availability, err := h.pollAvailability(*poll, options, invitees)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	pollResponse := responseDto.NewPollResponseDto(*poll, options, nil, availability)
+	for i, invitee := range invitees {
+		pollResponse.Invitees = append(pollResponse.Invitees, responseDto.NewPollInviteeResponseDto(invitee, nil, &inviteTokens[i]))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(pollResponse)
+}
+
+// GetPoll is the organizer's view of the poll, with every invitee's votes
+func (h *Handler) GetPoll(w http.ResponseWriter, r *http.Request) {
+	poll, err := h.organizerPoll(chi.URLParam(r, "id"), chi.URLParam(r, "pollId"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrNotFound {
+			status = http.StatusNotFound
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	pollResponse, err := h.pollResponse(poll, nil)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(pollResponse)
+}
+
+// GetInviteePoll is the invitee's view of the poll, the live tallies along with their own votes
+func (h *Handler) GetInviteePoll(w http.ResponseWriter, r *http.Request) {
+	invitee, poll, err := h.inviteePoll(chi.URLParam(r, "token"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrNotFound {
+			status = http.StatusNotFound
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	pollResponse, err := h.pollResponse(poll, &invitee)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(pollResponse)
+}
+
+// UpdatePollVotes replaces the invitee's votes, options left out count as not voted
+func (h *Handler) UpdatePollVotes(w http.ResponseWriter, r *http.Request) {
+	invitee, poll, err := h.inviteePoll(chi.URLParam(r, "token"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrNotFound {
+			status = http.StatusNotFound
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if poll.Status != dto.PollOpen {
+		w.WriteHeader(http.StatusConflict)
+		response := map[string]string{"error": "poll is " + poll.Status}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	req := &requestDto.UpdatePollVotesDto{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	options, err := h.repo.GetPollOptionsByPollId(&poll.ID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	votes, err := newPollVoteDtos(invitee, options, req)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	err = h.repo.ReplacePollVotes(&invitee, votes)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	pollResponse, err := h.pollResponse(poll, &invitee)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(pollResponse)
+}
+
+// FinalizePoll creates the event for the chosen option with every invitee as attendee, and closes the poll
+func (h *Handler) FinalizePoll(w http.ResponseWriter, r *http.Request) {
+	poll, err := h.organizerPoll(chi.URLParam(r, "id"), chi.URLParam(r, "pollId"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrNotFound {
+			status = http.StatusNotFound
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if poll.Status != dto.PollOpen {
+		w.WriteHeader(http.StatusConflict)
+		response := map[string]string{"error": "poll is " + poll.Status}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	req := &requestDto.FinalizePollDto{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	options, err := h.repo.GetPollOptionsByPollId(&poll.ID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	var finalOption *dto.PollOptionDto
+	for i := range options {
+		if options[i].ID.String() == req.OptionId {
+			finalOption = &options[i]
+		}
+	}
+	if finalOption == nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": "option_id: not an option of the poll"}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	invitees, err := h.repo.GetPollInviteesByPollId(&poll.ID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	attendees := []requestDto.MGraphCreateEventAttendeeDto{}
+	for _, invitee := range invitees {
+		attendees = append(attendees, requestDto.MGraphCreateEventAttendeeDto{EmailAddress: invitee.EmailAddress, Name: invitee.Name, AttendeeType: "required"})
+	}
+
+	organizerUserId := poll.UserId.String()
+	createEventDto := &requestDto.MGraphCreateEventDto{
+		UserId:                &organizerUserId,
+		Subject:               poll.Title,
+		Content:               html.EscapeString(poll.Description),
+		StartTime:             finalOption.StartTime.UTC().Format(bookingDateTimeLayout),
+		EndTime:               finalOption.EndTime.UTC().Format(bookingDateTimeLayout),
+		TimeZone:              "UTC",
+		Attendees:             attendees,
+		Locations:             &[]requestDto.MGraphCreateEventLocationDto{},
+		IsOnlineMeeting:       poll.IsOnlineMeeting,
+		OnlineMeetingProvider: poll.OnlineMeetingProvider,
+	}
+	if poll.LocationDisplayName != nil {
+		createEventDto.Locations = &[]requestDto.MGraphCreateEventLocationDto{
+			{DisplayName: *poll.LocationDisplayName, Address: &requestDto.MGraphCreateEventLocationAddressDto{}},
+		}
+	}
+
+	// claim the poll before the invitations go out, a concurrent finalize gets turned away here
+	err = h.repo.UpdatePollStatus(&poll.ID, dto.PollOpen, dto.PollFinalizing)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrConflict {
+			status = http.StatusConflict
+			err = errors.New("poll is no longer open")
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	event, err := h.client.PostCreateEvent(createEventDto)
+	if err != nil {
+		if releaseErr := h.repo.UpdatePollStatus(&poll.ID, dto.PollFinalizing, dto.PollOpen); releaseErr != nil {
+			log.Printf("poll %s: could not reopen the poll: %s", poll.ID, releaseErr)
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// the invitations went out, the poll is closed even when storing the event fails, the sync picks it up later
+	closedAt := time.Now()
+	poll.Status = dto.PollClosed
+	poll.FinalOptionId = &finalOption.ID
+	poll.EventId = (*event).GetId()
+	poll.ICalUid = (*event).GetICalUId()
+	poll.ClosedAt = &closedAt
+	err = h.repo.ClosePoll(&poll)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	err = h.storeEvent(*event, organizerUserId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	pollResponse, err := h.pollResponse(poll, nil)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(pollResponse)
+}
+
+// organizerPoll finds the poll of the organizer, polls of other users are not found
+func (h *Handler) organizerPoll(userId string, pollId string) (dto.PollDto, error) {
+	userUuid, err := uuid.Parse(userId)
+	if err != nil {
+		return dto.PollDto{}, utility.ErrNotFound
+	}
+
+	pollUuid, err := uuid.Parse(pollId)
+	if err != nil {
+		return dto.PollDto{}, utility.ErrNotFound
+	}
+
+	poll, err := h.repo.GetPollById(&pollUuid)
+	if err != nil {
+		return dto.PollDto{}, err
+	}
+
+	if poll.UserId != userUuid {
+		return dto.PollDto{}, utility.ErrNotFound
+	}
+
+	return poll, nil
+}
+
+// inviteePoll finds the invitee of a token along with their poll
+func (h *Handler) inviteePoll(token string) (dto.PollInviteeDto, dto.PollDto, error) {
+	invitee, err := h.repo.GetPollInviteeByTokenHash(utility.HashToken(token))
+	if err != nil {
+		return dto.PollInviteeDto{}, dto.PollDto{}, err
+	}
+
+	poll, err := h.repo.GetPollById(&invitee.PollId)
+	if err != nil {
+		return dto.PollInviteeDto{}, dto.PollDto{}, err
+	}
+
+	return invitee, poll, nil
+}
+
+// pollResponse lists the tallies of the poll, with every invitee for the organizer
+// or with the invitee's own votes when one is given
+func (h *Handler) pollResponse(poll dto.PollDto, invitee *dto.PollInviteeDto) (responseDto.PollResponseDto, error) {
+	options, err := h.repo.GetPollOptionsByPollId(&poll.ID)
+	if err != nil {
+		return responseDto.PollResponseDto{}, err
+	}
+
+	invitees, err := h.repo.GetPollInviteesByPollId(&poll.ID)
+	if err != nil {
+		return responseDto.PollResponseDto{}, err
+	}
+
+	votes, err := h.repo.GetPollVotesByPollId(&poll.ID)
+	if err != nil {
+		return responseDto.PollResponseDto{}, err
+	}
+
+	availability, err := h.pollAvailability(poll, options, invitees)
+	if err != nil {
+		return responseDto.PollResponseDto{}, err
+	}
+
+	pollResponse := responseDto.NewPollResponseDto(poll, options, votes, availability)
+	if invitee != nil {
+		pollResponse.MyVotes = responseDto.NewPollVotesResponse(*invitee, votes)
+		return pollResponse, nil
+	}
+
+	for _, pollInvitee := range invitees {
+		pollResponse.Invitees = append(pollResponse.Invitees, responseDto.NewPollInviteeResponseDto(pollInvitee, votes, nil))
+	}
+	return pollResponse, nil
+}
+
+// pollAvailability annotates every option with the synced free/busy of the organizer and the invitees,
+// invitees that aren't synced users are unknown
+func (h *Handler) pollAvailability(poll dto.PollDto, options []dto.PollOptionDto, invitees []dto.PollInviteeDto) (map[uuid.UUID][]responseDto.PollAvailabilityResponseDto, error) {
+	availability := map[uuid.UUID][]responseDto.PollAvailabilityResponseDto{}
+	if len(options) == 0 {
+		return availability, nil
+	}
+
+	startTime, endTime := options[0].StartTime, options[0].EndTime
+	for _, option := range options {
+		if option.StartTime.Before(startTime) {
+			startTime = option.StartTime
+		}
+		if option.EndTime.After(endTime) {
+			endTime = option.EndTime
+		}
+	}
+
+	type participant struct {
+		emailAddress  string
+		busyIntervals []freebusy.Interval
+		isSynced      bool
+	}
+
+	participants := []participant{}
+	organizer, err := h.repo.GetUserByUserId(&poll.UserId)
+	if err != nil && err != utility.ErrNotFound {
+		return nil, err
+	}
+	if err == nil {
+		busyIntervals, err := h.userBusyIntervals(organizer.UserId.String(), startTime, endTime)
+		if err != nil {
+			return nil, err
+		}
+
+		emailAddress := organizer.UserId.String()
+		if organizer.EmailAddress != nil {
+			emailAddress = *organizer.EmailAddress
+		}
+		participants = append(participants, participant{emailAddress: emailAddress, busyIntervals: busyIntervals, isSynced: true})
+	}
+
+	for _, invitee := range invitees {
+		user, err := h.repo.GetUserByEmailAddress(invitee.EmailAddress)
+		if err != nil {
+			if err == utility.ErrNotFound {
+				participants = append(participants, participant{emailAddress: invitee.EmailAddress})
+				continue
+			}
+			return nil, err
+		}
+
+		busyIntervals, err := h.userBusyIntervals(user.UserId.String(), startTime, endTime)
+		if err != nil {
+			return nil, err
+		}
+		participants = append(participants, participant{emailAddress: invitee.EmailAddress, busyIntervals: busyIntervals, isSynced: true})
+	}
+
+	for _, option := range options {
+		optionAvailability := []responseDto.PollAvailabilityResponseDto{}
+		for _, participant := range participants {
+			status := freebusy.Unknown
+			if participant.isSynced {
+				status = freebusy.StatusOf(participant.busyIntervals, option.StartTime, option.EndTime)
+			}
+			optionAvailability = append(optionAvailability, responseDto.PollAvailabilityResponseDto{EmailAddress: participant.emailAddress, Status: status})
+		}
+		availability[option.ID] = optionAvailability
+	}
+
+	return availability, nil
+}
+
+// newPollDtos validates the request, the invite tokens are in the order of the invitees
+func newPollDtos(userUuid uuid.UUID, req *requestDto.CreatePollDto) (*dto.PollDto, []dto.PollOptionDto, []dto.PollInviteeDto, []string, error) {
+	if strings.TrimSpace(req.Title) == "" {
+		return nil, nil, nil, nil, errors.New("title: is required")
+	}
+	if len(req.Options) == 0 || len(req.Options) > maxPollOptions {
+		return nil, nil, nil, nil, errors.New("options: between 1 and 20 options are required")
+	}
+	if len(req.Invitees) == 0 {
+		return nil, nil, nil, nil, errors.New("invitees: at least one invitee is required")
+	}
+
+	options := []dto.PollOptionDto{}
+	for _, optionReq := range req.Options {
+		startTime, err := time.Parse(time.RFC3339, optionReq.Start)
+		if err != nil {
+			return nil, nil, nil, nil, errors.New("options: start: " + err.Error())
+		}
+		endTime, err := time.Parse(time.RFC3339, optionReq.End)
+		if err != nil {
+			return nil, nil, nil, nil, errors.New("options: end: " + err.Error())
+		}
+		if !endTime.After(startTime) {
+			return nil, nil, nil, nil, errors.New("options: end must be after start")
+		}
+		options = append(options, dto.PollOptionDto{StartTime: startTime, EndTime: endTime, CreatedAt: time.Now()})
+	}
+
+	invitees := []dto.PollInviteeDto{}
+	inviteTokens := []string{}
+	seenEmailAddresses := map[string]bool{}
+	for _, inviteeReq := range req.Invitees {
+		if _, err := mail.ParseAddress(inviteeReq.EmailAddress); err != nil {
+			return nil, nil, nil, nil, errors.New("invitees: " + err.Error())
+		}
+		if seenEmailAddresses[strings.ToLower(inviteeReq.EmailAddress)] {
+			continue
+		}
+		seenEmailAddresses[strings.ToLower(inviteeReq.EmailAddress)] = true
+
+		name := inviteeReq.Name
+		if name == "" {
+			name = inviteeReq.EmailAddress
+		}
+
+		token, tokenHash, err := utility.NewToken()
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+
+		invitees = append(invitees, dto.PollInviteeDto{EmailAddress: inviteeReq.EmailAddress, Name: name, TokenHash: tokenHash, CreatedAt: time.Now()})
+		inviteTokens = append(inviteTokens, token)
+	}
+
+	poll := &dto.PollDto{
+		UserId:                userUuid,
+		Title:                 req.Title,
+		Description:           req.Description,
+		LocationDisplayName:   req.LocationDisplayName,
+		IsOnlineMeeting:       req.IsOnlineMeeting,
+		OnlineMeetingProvider: req.OnlineMeetingProvider,
+		Status:                dto.PollOpen,
+		CreatedAt:             time.Now(),
+		UpdatedAt:             time.Now(),
+	}
+
+	return poll, options, invitees, inviteTokens, nil
+}
+
+func newPollVoteDtos(invitee dto.PollInviteeDto, options []dto.PollOptionDto, req *requestDto.UpdatePollVotesDto) ([]dto.PollVoteDto, error) {
+	optionIds := map[string]uuid.UUID{}
+	for _, option := range options {
+		optionIds[option.ID.String()] = option.ID
+	}
+
+	votes := []dto.PollVoteDto{}
+	seenOptionIds := map[uuid.UUID]bool{}
+	for _, voteReq := range req.Votes {
+		optionId, ok := optionIds[strings.ToLower(voteReq.OptionId)]
+		if !ok {
+			return nil, errors.New("votes: " + voteReq.OptionId + " is not an option of the poll")
+		}
+		if seenOptionIds[optionId] {
+			return nil, errors.New("votes: more than one vote for " + voteReq.OptionId)
+		}
+		seenOptionIds[optionId] = true
+
+		switch voteReq.Vote {
+		case dto.VoteYes, dto.VoteIfNeeded, dto.VoteNo:
+		default:
+			return nil, errors.New("votes: vote must be one of yes, ifNeeded or no")
+		}
+
+		votes = append(votes, dto.PollVoteDto{
+			PollId:    invitee.PollId,
+			OptionId:  optionId,
+			InviteeId: invitee.ID,
+			Vote:      voteReq.Vote,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		})
+	}
+
+	return votes, nil
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/scheduler-prototype/dto"
+)
+
+// Reprocess rebuilds the normalized rows from the last stored payload of every copy without calling Graph,
+// only the copies of the given user when userId is set
+// -- events and attendees are refreshed in place so their ids stay, locations are only ever inserted so they're recreated
+func (h *Handler) Reprocess(userId *string) (int, error) {
+	payloads, err := h.repo.GetLatestEventPayloads(userId)
+	if err != nil {
+		return 0, err
+	}
+
+	// payloads come grouped by iCalUId, the copies of a meeting share its locations
+	previousICalUid := ""
+	for _, payload := range payloads {
+		if payload.ICalUid != previousICalUid {
+			err = h.repo.DeleteLocationsByICalUid(payload.ICalUid)
+			if err != nil {
+				return 0, err
+			}
+			previousICalUid = payload.ICalUid
+		}
+
+		event, err := parseGraphEvent(payload.Payload)
+		if err != nil {
+			return 0, fmt.Errorf("payload %s: %w", payload.ID, err)
+		}
+
+		err = h.normalizeEvent(event, payload.UserId, dto.EventPayloadDto{Source: dto.EventPayloadFromReprocess})
+		if err != nil {
+			return 0, fmt.Errorf("payload %s: %w", payload.ID, err)
+		}
+	}
+
+	return len(payloads), nil
+}
@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/scheduler-prototype/dto"
+	"github.com/scheduler-prototype/utility"
+)
+
+// the delta link stays bound to the window it was created for,
+// once the window a user should have drifts this far from it a new window is synced
+const syncWindowRollThreshold = 7 * 24 * time.Hour
+
+// StartRollingSyncWindowJob checks every interval whether a user's sync window is aging and moves it forward
+func (h *Handler) StartRollingSyncWindowJob(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for ; true; <-ticker.C {
+			h.rollSyncWindows(time.Now())
+		}
+	}()
+}
+
+func (h *Handler) rollSyncWindows(now time.Time) {
+	users, err := h.repo.GetUsersWithCurrentDelta()
+	if err != nil {
+		log.Printf("rolling sync window: could not get users: %s", err)
+		return
+	}
+
+	for _, userDto := range users {
+		if !isSyncWindowAging(userDto, now) {
+			continue
+		}
+
+		err := h.rollSyncWindow(userDto, now)
+		if err != nil {
+			log.Printf("rolling sync window: user %s: %s", userDto.UserId, err)
+			continue
+		}
+		log.Printf("rolling sync window: user %s moved", userDto.UserId)
+	}
+}
+
+func isSyncWindowAging(userDto dto.UserDto, now time.Time) bool {
+	// users synced before windows were recorded
+	if userDto.SyncWindowStart == nil || userDto.SyncWindowEnd == nil {
+		return true
+	}
+
+	// also catches users whose window settings changed since the last sync
+	start, end := utility.SyncWindow(userDto.MailboxTimezone, userDto.SyncWindowPastDays, userDto.SyncWindowFutureDays, now)
+	return absDuration(start.Sub(*userDto.SyncWindowStart)) >= syncWindowRollThreshold ||
+		absDuration(end.Sub(*userDto.SyncWindowEnd)) >= syncWindowRollThreshold
+}
+
+// rollSyncWindow starts a new windowed delta for the shifted range and swaps it in once its events are stored
+// -- the current delta link is only replaced after every event was merged, so a failure leaves the user untouched
+func (h *Handler) rollSyncWindow(userDto dto.UserDto, now time.Time) error {
+	syncWindowStart, syncWindowEnd := utility.SyncWindow(userDto.MailboxTimezone, userDto.SyncWindowPastDays, userDto.SyncWindowFutureDays, now)
+
+	deltaLink, pages, err := h.client.GetCalendarViewDelta(syncWindowStart.Format(time.RFC3339), syncWindowEnd.Format(time.RFC3339), userDto)
+	if err != nil {
+		return err
+	}
+
+	// events overlapping both windows are matched on their iCalUId and updated in place
+	syncRunId := uuid.New()
+	for _, page := range pages {
+		for _, event := range page.Events {
+			err := h.storeEventFrom(event, userDto.UserId.String(), newDeltaEventPayloadDto(syncRunId, page))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	previousWindowStart := userDto.SyncWindowStart
+	userDto.CurrentDelta = deltaLink
+	userDto.SyncWindowStart = &syncWindowStart
+	userDto.SyncWindowEnd = &syncWindowEnd
+	err = h.repo.RotateDeltaByUser(&userDto)
+	if err != nil {
+		return err
+	}
+
+	if previousWindowStart == nil || !syncWindowStart.After(*previousWindowStart) {
+		return nil
+	}
+
+	return h.pruneSyncWindow(userDto.UserId.String(), *previousWindowStart, syncWindowStart)
+}
+
+// pruneSyncWindow removes the copies the window moved past, the delta link no longer tracks them
+// -- only the part the previous window covered is pruned, backfilled events before it stay
+func (h *Handler) pruneSyncWindow(userId string, previousWindowStart time.Time, windowStart time.Time) error {
+	events, err := h.repo.GetEventsByUserIdEndingBetween(userId, previousWindowStart, windowStart)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		err := h.removeEvent(userId, event.ICalUid, dto.EventPayloadDto{Source: dto.EventPayloadFromDelta})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func absDuration(duration time.Duration) time.Duration {
+	if duration < 0 {
+		return -duration
+	}
+	return duration
+}
+
+// RollUserSyncWindow moves the user's sync window forward right away instead of waiting for the job
+func (h *Handler) RollUserSyncWindow(w http.ResponseWriter, r *http.Request) {
+	userUuid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	userDto, err := h.repo.GetUserByUserId(&userUuid)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrNotFound {
+			status = http.StatusNotFound
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	err = h.rollSyncWindow(userDto, time.Now())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	response := map[string]string{"message": "Sync window successfully moved"}
+	json.NewEncoder(w).Encode(response)
+}
+
+// RollbackUserSyncWindow restores the previous delta link and window of the user
+func (h *Handler) RollbackUserSyncWindow(w http.ResponseWriter, r *http.Request) {
+	userUuid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	userDto := &dto.UserDto{UserId: userUuid}
+	err = h.repo.RollbackDeltaByUser(userDto)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrNotFound {
+			status = http.StatusNotFound
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	response := map[string]string{"message": "Sync window successfully rolled back"}
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	requestDto "github.com/scheduler-prototype/dto/request"
+	"github.com/scheduler-prototype/utility"
+)
+
+// UpdateUserSyncWindow changes how many days around today are synced for the user
+// -- the new window applies from the next time the user's window is synced
+func (h *Handler) UpdateUserSyncWindow(w http.ResponseWriter, r *http.Request) {
+	userUuid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	req := &requestDto.UpdateUserSyncWindowDto{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	userDto, err := h.repo.GetUserByUserId(&userUuid)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrNotFound {
+			status = http.StatusNotFound
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if req.SyncWindowPastDays != nil {
+		userDto.SyncWindowPastDays = *req.SyncWindowPastDays
+	}
+	if req.SyncWindowFutureDays != nil {
+		userDto.SyncWindowFutureDays = *req.SyncWindowFutureDays
+	}
+
+	if userDto.SyncWindowPastDays < 0 || userDto.SyncWindowFutureDays < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": "sync window days can't be negative"}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	err = h.repo.UpdateSyncWindowSettingsByUser(&userDto)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	response := map[string]string{"message": "Sync window successfully updated"}
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/scheduler-prototype/dto"
+	responseDto "github.com/scheduler-prototype/dto/response"
+)
+
+const (
+	webhookDeliveryBatchSize   = 100
+	webhookDeliveryWorkers     = 10
+	webhookDeliveryTimeout     = 10 * time.Second
+	webhookMaxAttempts         = 8
+	webhookInitialRetryBackoff = 30 * time.Second
+	webhookMaxRetryBackoff     = 6 * time.Hour
+
+	// a claimed delivery is attempted again after the lease when its worker stopped mid-send,
+	// a whole batch takes at most batch size / workers * timeout (100s) so it's sent well within it
+	webhookDeliveryLease = 5 * time.Minute
+)
+
+// the address is checked again when connecting, the host may resolve elsewhere than at registration
+var webhookHttpClient = &http.Client{
+	Timeout: webhookDeliveryTimeout,
+	Transport: &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout: webhookDeliveryTimeout,
+			Control: func(network string, address string, conn syscall.RawConn) error {
+				host, _, err := net.SplitHostPort(address)
+				if err != nil {
+					return err
+				}
+				if ip := net.ParseIP(host); ip != nil && isInternalWebhookIP(ip) {
+					return fmt.Errorf("webhook address %s is loopback or link-local", host)
+				}
+				return nil
+			},
+		}).DialContext,
+	},
+}
+
+// StartWebhookDeliveryJob sends the queued webhook deliveries that are due every interval
+func (h *Handler) StartWebhookDeliveryJob(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for ; true; <-ticker.C {
+			h.deliverWebhooks(time.Now())
+		}
+	}()
+}
+
+func (h *Handler) deliverWebhooks(now time.Time) {
+	deliveries, err := h.repo.ClaimWebhookDeliveries(now, webhookDeliveryLease, webhookDeliveryBatchSize)
+	if err != nil {
+		log.Printf("webhook delivery: could not claim deliveries: %s", err)
+		return
+	}
+
+	// deliveries are sent side by side so a batch of slow consumers still finishes within the lease
+	workers := make(chan struct{}, webhookDeliveryWorkers)
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		workers <- struct{}{}
+		wg.Add(1)
+		go func(delivery dto.WebhookDeliveryDto) {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+
+			err := h.deliverWebhook(delivery)
+			if err != nil {
+				log.Printf("webhook delivery: delivery %s: %s", delivery.ID, err)
+			}
+		}(delivery)
+	}
+	wg.Wait()
+}
+
+// deliverWebhook makes one attempt at the delivery and schedules the next one or dead-letters it when it failed
+func (h *Handler) deliverWebhook(delivery dto.WebhookDeliveryDto) error {
+	webhook, err := h.repo.GetWebhookById(&delivery.WebhookId)
+	if err != nil {
+		return err
+	}
+
+	change, err := h.repo.GetEventChangeById(delivery.ChangeId)
+	if err != nil {
+		return err
+	}
+
+	// consumers aren't the owner, a private event only tells its busy time changed
+	if change.IsPrivate {
+		change.ChangedFields = busyChangedFields(change.ChangedFields)
+	}
+
+	body, err := json.Marshal(responseDto.NewWebhookPayloadDto(delivery, change))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	delivery.Attempts++
+	sendErr := postWebhook(webhook, delivery, body, now)
+	if sendErr == nil {
+		delivery.Status = dto.WebhookDeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.LastError = nil
+		return h.repo.UpdateWebhookDelivery(&delivery)
+	}
+
+	lastError := sendErr.Error()
+	delivery.LastError = &lastError
+
+	if delivery.Attempts >= webhookMaxAttempts {
+		log.Printf("webhook delivery: delivery %s failed %d times, moving it to the dead letters: %s", delivery.ID, delivery.Attempts, sendErr)
+		return h.repo.DeadLetterWebhookDelivery(&delivery, &dto.WebhookDeadLetterDto{
+			DeliveryId: delivery.ID,
+			WebhookId:  delivery.WebhookId,
+			ChangeId:   delivery.ChangeId,
+			Attempts:   delivery.Attempts,
+			LastError:  delivery.LastError,
+			FailedAt:   now,
+		})
+	}
+
+	delivery.NextAttemptAt = now.Add(webhookRetryBackoff(delivery.Attempts))
+	return h.repo.UpdateWebhookDelivery(&delivery)
+}
+
+// postWebhook sends the body signed with the webhook's secret, any 2xx response counts as delivered
+// -- X-Webhook-Signature is the hex HMAC-SHA256 of "<X-Webhook-Timestamp>.<body>", the timestamp lets consumers reject replays
+func postWebhook(webhook dto.WebhookDto, delivery dto.WebhookDeliveryDto, body []byte, now time.Time) error {
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	request, err := http.NewRequest(http.MethodPost, webhook.Url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Webhook-Delivery", delivery.ID.String())
+	request.Header.Set("X-Webhook-Timestamp", timestamp)
+	request.Header.Set("X-Webhook-Signature", "sha256="+webhookSignature(webhook.Secret, timestamp, body))
+
+	response, err := webhookHttpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with %s", response.Status)
+	}
+
+	return nil
+}
+
+func webhookSignature(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookRetryBackoff doubles the wait after every failed attempt, starting at webhookInitialRetryBackoff
+func webhookRetryBackoff(attempts int) time.Duration {
+	backoff := webhookInitialRetryBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= webhookMaxRetryBackoff {
+			return webhookMaxRetryBackoff
+		}
+	}
+	return backoff
+}
@@ -0,0 +1,264 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	graphmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/scheduler-prototype/dto"
+	requestDto "github.com/scheduler-prototype/dto/request"
+	responseDto "github.com/scheduler-prototype/dto/response"
+	"github.com/scheduler-prototype/utility"
+)
+
+// CreateWebhook registers an endpoint the changes of the synced events are posted to,
+// the secret in the response is the only time it's shown
+func (h *Handler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	req := &requestDto.CreateWebhookDto{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	webhook, err := newWebhookDto(req, time.Now())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	err = h.repo.CreateWebhook(webhook)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	webhookResponse := responseDto.NewWebhookResponseDto(*webhook)
+	webhookResponse.Secret = &webhook.Secret
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(webhookResponse)
+}
+
+func (h *Handler) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := h.repo.GetActiveWebhooks()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := []responseDto.WebhookResponseDto{}
+	for _, webhook := range webhooks {
+		response = append(response, responseDto.NewWebhookResponseDto(webhook))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// DeleteWebhook stops the deliveries to the webhook, the pending ones are never sent
+func (h *Handler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	webhookUuid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	err = h.repo.DeactivateWebhook(&webhookUuid)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrNotFound {
+			status = http.StatusNotFound
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetWebhookDeadLetters returns the changes the webhook didn't accept after every retry, latest first
+func (h *Handler) GetWebhookDeadLetters(w http.ResponseWriter, r *http.Request) {
+	webhookUuid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	_, err = h.repo.GetWebhookById(&webhookUuid)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrNotFound {
+			status = http.StatusNotFound
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	deadLetters, err := h.repo.GetWebhookDeadLettersByWebhookId(&webhookUuid)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := []responseDto.WebhookDeadLetterResponseDto{}
+	for _, deadLetter := range deadLetters {
+		response = append(response, responseDto.NewWebhookDeadLetterResponseDto(deadLetter))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// ReplayWebhookDeadLetter queues the change of the dead letter for delivery again, with a fresh set of retries
+func (h *Handler) ReplayWebhookDeadLetter(w http.ResponseWriter, r *http.Request) {
+	webhookUuid, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	deadLetterUuid, err := uuid.Parse(chi.URLParam(r, "deadLetterId"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	webhook, err := h.repo.GetWebhookById(&webhookUuid)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrNotFound {
+			status = http.StatusNotFound
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	if !webhook.IsActive {
+		w.WriteHeader(http.StatusConflict)
+		response := map[string]string{"error": "webhook was deleted"}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	delivery, err := h.repo.ReplayWebhookDeadLetter(&webhookUuid, &deadLetterUuid, time.Now())
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == utility.ErrNotFound {
+			status = http.StatusNotFound
+		} else if err == utility.ErrConflict {
+			status = http.StatusConflict
+			err = errors.New("dead letter was replayed already")
+		}
+		w.WriteHeader(status)
+		response := map[string]string{"error": err.Error()}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(responseDto.NewWebhookDeliveryResponseDto(delivery))
+}
+
+func newWebhookDto(req *requestDto.CreateWebhookDto, now time.Time) (*dto.WebhookDto, error) {
+	endpoint, err := url.Parse(req.Url)
+	if err != nil || (endpoint.Scheme != "https" && endpoint.Scheme != "http") || endpoint.Host == "" {
+		return nil, errors.New("url: must be an absolute http or https url")
+	}
+	if err := checkWebhookHost(endpoint.Hostname()); err != nil {
+		return nil, errors.New("url: " + err.Error())
+	}
+
+	if req.UserId != nil {
+		if _, err := uuid.Parse(*req.UserId); err != nil {
+			return nil, errors.New("user_id: " + err.Error())
+		}
+	}
+
+	for _, eventType := range req.EventTypes {
+		if _, err := graphmodels.ParseEventType(eventType); err != nil {
+			return nil, errors.New("event_types: " + err.Error())
+		}
+	}
+
+	for _, changeType := range req.ChangeTypes {
+		if changeType != dto.EventCreated && changeType != dto.EventUpdated && changeType != dto.EventRemoved {
+			return nil, errors.New("change_types: must be created, updated or removed")
+		}
+	}
+
+	secret, _, err := utility.NewToken()
+	if err != nil {
+		return nil, err
+	}
+
+	webhook := &dto.WebhookDto{
+		Url:         req.Url,
+		Secret:      secret,
+		UserId:      req.UserId,
+		EventTypes:  req.EventTypes,
+		ChangeTypes: req.ChangeTypes,
+		IsActive:    true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if webhook.EventTypes == nil {
+		webhook.EventTypes = []string{}
+	}
+	if webhook.ChangeTypes == nil {
+		webhook.ChangeTypes = []string{}
+	}
+
+	return webhook, nil
+}
+
+// checkWebhookHost keeps webhooks off this host and the link-local network, where the cloud metadata endpoints live
+func checkWebhookHost(host string) error {
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return err
+	}
+	for _, ip := range ips {
+		if isInternalWebhookIP(ip) {
+			return errors.New("must not point to a loopback or link-local address")
+		}
+	}
+	return nil
+}
+
+func isInternalWebhookIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified()
+}
@@ -2,13 +2,16 @@ package main
 
 import (
 	"database/sql"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
+	"github.com/google/uuid"
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
 	"github.com/scheduler-prototype/handler"
@@ -51,6 +54,27 @@ func main() {
 	// initialize handlers
 	controller := handler.NewHandler(client, repo)
 
+	// events stored before iana_timezone existed can't be read until it's filled
+	backfilledTimezones, err := controller.BackfillIanaTimezones()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if backfilledTimezones > 0 {
+		log.Printf("Filled the IANA time zone of %d events", backfilledTimezones)
+	}
+
+	// one-off commands run instead of the server, e.g. `go run . backfill -user <id> -until 2022-01-01`
+	if len(os.Args) > 1 && os.Args[1] == "backfill" {
+		runBackfillCommand(controller, os.Args[2:])
+		return
+	}
+
+	// rebuilds events, attendees and locations from the stored graph payloads, e.g. `go run . reprocess -user <id>`
+	if len(os.Args) > 1 && os.Args[1] == "reprocess" {
+		runReprocessCommand(controller, os.Args[2:])
+		return
+	}
+
 	// chi router
 	r := chi.NewRouter()
 	r.Use(middleware.Logger) // <--<< Logger should come before Recoverer
@@ -59,11 +83,131 @@ func main() {
 	subRouter := chi.NewRouter()
 	subRouter.Get("/calendarview", controller.MGraphGetCalendarView)
 	subRouter.Post("/event/create", controller.MGraphCreateEvent)
+	subRouter.Post("/event/update", controller.MGraphUpdateEvent)
+	subRouter.Post("/event/cancel", controller.MGraphCancelEvent)
+	subRouter.Post("/event/split", controller.MGraphSplitEventSeries)
+	subRouter.Post("/event/accept", controller.MGraphAcceptEvent)
+	subRouter.Post("/event/decline", controller.MGraphDeclineEvent)
+	subRouter.Post("/event/tentatively-accept", controller.MGraphTentativelyAcceptEvent)
+	subRouter.Post("/schedule", controller.MGraphGetSchedule)
 	subRouter.Post("/calendarview/first-sync", controller.MGraphCalendarViewFirstSync)
 	subRouter.Post("/calendarview/subscription/notification", controller.MGraphHandleCalendarViewNotification)
 	subRouter.Post("/calendarview/subscription/renew", controller.MGraphHandleCalendarViewSubscriptionRenew)
 
 	r.Mount("/mgraph", subRouter)
 
+	r.Get("/events", controller.GetEvents)
+	r.Get("/events/{id}/responses", controller.GetEventResponses)
+	r.Get("/events/{id}/history", controller.GetEventHistory)
+	r.Get("/changes", controller.GetChanges)
+	r.Post("/webhooks", controller.CreateWebhook)
+	r.Get("/webhooks", controller.GetWebhooks)
+	r.Delete("/webhooks/{id}", controller.DeleteWebhook)
+	r.Get("/webhooks/{id}/dead-letters", controller.GetWebhookDeadLetters)
+	r.Post("/webhooks/{id}/dead-letters/{deadLetterId}/replay", controller.ReplayWebhookDeadLetter)
+	r.Patch("/users/{id}/sync-window", controller.UpdateUserSyncWindow)
+	r.Post("/users/{id}/sync-window/roll", controller.RollUserSyncWindow)
+	r.Post("/users/{id}/sync-window/rollback", controller.RollbackUserSyncWindow)
+	r.Post("/users/{id}/backfill", controller.PostBackfill)
+	r.Get("/users/{id}/backfill", controller.GetBackfill)
+	r.Get("/users/{id}/freebusy", controller.GetUserFreeBusy)
+	r.Get("/users/{id}/availability", controller.GetUserAvailability)
+	r.Put("/users/{id}/availability", controller.UpdateUserAvailability)
+	r.Post("/users/{id}/availability/overrides", controller.CreateUserAvailabilityOverride)
+	r.Delete("/users/{id}/availability/overrides/{overrideId}", controller.DeleteUserAvailabilityOverride)
+	r.Post("/scheduling/find-times", controller.FindMeetingTimes)
+	r.Post("/users/{id}/booking-types", controller.CreateBookingType)
+	r.Get("/users/{id}/booking-types", controller.GetBookingTypes)
+	r.Delete("/users/{id}/booking-types/{bookingTypeId}", controller.DeleteBookingType)
+	r.Get("/users/{id}/booking-types/{bookingTypeId}/assignments", controller.GetBookingAssignments)
+
+	// public booking pages, guests manage their booking with the token they got when booking
+	r.Get("/book/{slug}", controller.GetPublicBookingType)
+	r.Get("/book/{slug}/slots", controller.GetBookingSlots)
+	r.Post("/book/{slug}/bookings", controller.CreateBooking)
+	r.Get("/bookings/{token}", controller.GetBooking)
+	r.Post("/bookings/{token}/reschedule", controller.RescheduleBooking)
+	r.Post("/bookings/{token}/cancel", controller.CancelBooking)
+
+	r.Post("/users/{id}/polls", controller.CreatePoll)
+	r.Get("/users/{id}/polls/{pollId}", controller.GetPoll)
+	r.Post("/users/{id}/polls/{pollId}/finalize", controller.FinalizePoll)
+
+	// invitees vote through the link with their token
+	r.Get("/polls/{token}", controller.GetInviteePoll)
+	r.Put("/polls/{token}/votes", controller.UpdatePollVotes)
+
+	r.Post("/holds", controller.CreateHold)
+	r.Get("/holds/{id}", controller.GetHold)
+	r.Post("/holds/{id}/confirm", controller.ConfirmHold)
+
+	// background jobs
+	syncWindowRollInterval, err := time.ParseDuration(os.Getenv("SYNC_WINDOW_ROLL_INTERVAL"))
+	if err != nil {
+		syncWindowRollInterval = time.Hour
+	}
+	controller.StartRollingSyncWindowJob(syncWindowRollInterval)
+
+	holdExpiryInterval, err := time.ParseDuration(os.Getenv("HOLD_EXPIRY_INTERVAL"))
+	if err != nil {
+		holdExpiryInterval = time.Minute
+	}
+	controller.StartHoldExpiryJob(holdExpiryInterval)
+
+	webhookDeliveryInterval, err := time.ParseDuration(os.Getenv("WEBHOOK_DELIVERY_INTERVAL"))
+	if err != nil {
+		webhookDeliveryInterval = 10 * time.Second
+	}
+	controller.StartWebhookDeliveryJob(webhookDeliveryInterval)
+
 	http.ListenAndServe(":8080", r)
 }
+
+func runBackfillCommand(controller *handler.Handler, args []string) {
+	backfillCmd := flag.NewFlagSet("backfill", flag.ExitOnError)
+	userId := backfillCmd.String("user", "", "graph user id to backfill")
+	until := backfillCmd.String("until", "", "date to backfill back to, e.g. 2022-01-01")
+	chunkUnit := backfillCmd.String("chunk-unit", "month", "size unit of each chunk: month, week or day")
+	chunkSize := backfillCmd.Int("chunk-size", 1, "number of chunk units synced per request")
+	backfillCmd.Parse(args)
+
+	userUuid, err := uuid.Parse(*userId)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	untilDate, err := time.Parse("2006-01-02", *until)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	backfillJob, err := controller.StartBackfill(userUuid, untilDate, *chunkUnit, *chunkSize)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = controller.RunBackfill(backfillJob)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("Backfill %s completed, %d events synced\n", backfillJob.ID, backfillJob.EventsSynced)
+}
+
+func runReprocessCommand(controller *handler.Handler, args []string) {
+	reprocessCmd := flag.NewFlagSet("reprocess", flag.ExitOnError)
+	userId := reprocessCmd.String("user", "", "graph user id to reprocess, every user when empty")
+	reprocessCmd.Parse(args)
+
+	var userFilter *string
+	if *userId != "" {
+		userFilter = userId
+	}
+
+	reprocessed, err := controller.Reprocess(userFilter)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("Reprocess completed, %d events rebuilt\n", reprocessed)
+}
@@ -0,0 +1,86 @@
+package mapper
+
+import (
+	"errors"
+	"time"
+
+	graphmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/scheduler-prototype/dto"
+	"github.com/scheduler-prototype/timezone"
+)
+
+// Attendee maps an attendee of the meeting, attendees are matched on their address so it can't be missing
+func Attendee(attendee graphmodels.Attendeeable, iCalUid string) (dto.MGraphAttendeeDto, []Warning, error) {
+	ws := warnings{}
+
+	if attendee == nil || attendee.GetEmailAddress() == nil || attendee.GetEmailAddress().GetAddress() == nil {
+		return dto.MGraphAttendeeDto{}, nil, errors.New("emailAddress.address: missing")
+	}
+	emailAddress := *attendee.GetEmailAddress().GetAddress()
+
+	attendeeDto := dto.MGraphAttendeeDto{
+		UserId:       "1",
+		Name:         stringOr(attendee.GetEmailAddress().GetName(), emailAddress, "emailAddress.name", &ws),
+		EmailAddress: emailAddress,
+		ICalUid:      iCalUid,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+
+	if attendee.GetTypeEscaped() != nil {
+		attendeeType := attendee.GetTypeEscaped().String()
+		attendeeDto.AttendeeType = &attendeeType
+	}
+
+	attendeeDto.ResponseStatus, attendeeDto.ResponseTime = ResponseStatus(attendee.GetStatus())
+
+	// a proposal that can't be read is dropped rather than failing the attendee
+	if proposedNewTime := attendee.GetProposedNewTime(); proposedNewTime != nil {
+		start, end, err := timeSlot(proposedNewTime)
+		if err != nil {
+			ws.add("proposedNewTime", err.Error())
+		} else {
+			attendeeDto.ProposedStartTime = &start
+			attendeeDto.ProposedEndTime = &end
+		}
+	}
+
+	return attendeeDto, ws, nil
+}
+
+// ResponseStatus returns the response and its time, Graph sends the zero time until there is a response
+func ResponseStatus(status graphmodels.ResponseStatusable) (*string, *time.Time) {
+	if status == nil || status.GetResponse() == nil {
+		return nil, nil
+	}
+
+	response := status.GetResponse().String()
+	if status.GetTime() == nil || status.GetTime().Year() <= 1 {
+		return &response, nil
+	}
+
+	responseTime := status.GetTime().UTC()
+	return &response, &responseTime
+}
+
+func timeSlot(slot graphmodels.TimeSlotable) (time.Time, time.Time, error) {
+	startDateTime, startTimeZone, err := dateTimeTimeZone(slot.GetStart(), "start")
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	endDateTime, endTimeZone, err := dateTimeTimeZone(slot.GetEnd(), "end")
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	start, err := timezone.ParseDateTimeTimeZone(startDateTime, startTimeZone)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	end, err := timezone.ParseDateTimeTimeZone(endDateTime, endTimeZone)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	return start, end, nil
+}
@@ -0,0 +1,133 @@
+package mapper
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	graphmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/scheduler-prototype/dto"
+)
+
+const cannedAttendee = `{
+	"type": "required",
+	"status": {"response": "accepted", "time": "2023-10-03T05:30:00Z"},
+	"emailAddress": {"name": "Grace", "address": "grace@example.com"}
+}`
+
+func mappedCannedAttendee() dto.MGraphAttendeeDto {
+	responseTime := time.Date(2023, 10, 3, 5, 30, 0, 0, time.UTC)
+
+	return dto.MGraphAttendeeDto{
+		UserId:         "1",
+		Name:           "Grace",
+		EmailAddress:   "grace@example.com",
+		ICalUid:        "040000008200E00074C5B7101A82E00800000000",
+		ResponseStatus: stringPtr("accepted"),
+		ResponseTime:   &responseTime,
+		AttendeeType:   stringPtr("required"),
+	}
+}
+
+func TestAttendee(t *testing.T) {
+	tests := []struct {
+		name         string
+		patch        func(map[string]interface{})
+		want         func(*dto.MGraphAttendeeDto)
+		wantWarnings []Warning
+		wantErr      string
+	}{
+		{
+			name: "complete attendee",
+		},
+		{
+			name:    "attendee without emailAddress",
+			patch:   func(a map[string]interface{}) { delete(a, "emailAddress") },
+			wantErr: "emailAddress.address: missing",
+		},
+		{
+			name:    "emailAddress without address",
+			patch:   func(a map[string]interface{}) { a["emailAddress"] = map[string]interface{}{"name": "Grace"} },
+			wantErr: "emailAddress.address: missing",
+		},
+		{
+			name: "emailAddress without name",
+			patch: func(a map[string]interface{}) {
+				a["emailAddress"] = map[string]interface{}{"address": "grace@example.com"}
+			},
+			want:         func(d *dto.MGraphAttendeeDto) { d.Name = "grace@example.com" },
+			wantWarnings: []Warning{{Field: "emailAddress.name", Message: "missing"}},
+		},
+		{
+			name: "not responded yet",
+			patch: func(a map[string]interface{}) {
+				a["status"] = map[string]interface{}{"response": "none", "time": "0001-01-01T00:00:00Z"}
+			},
+			want: func(d *dto.MGraphAttendeeDto) {
+				d.ResponseStatus = stringPtr("none")
+				d.ResponseTime = nil
+			},
+		},
+		{
+			name: "without type and status",
+			patch: func(a map[string]interface{}) {
+				delete(a, "type")
+				delete(a, "status")
+			},
+			want: func(d *dto.MGraphAttendeeDto) {
+				d.AttendeeType = nil
+				d.ResponseStatus = nil
+				d.ResponseTime = nil
+			},
+		},
+		{
+			name: "proposed new time",
+			patch: func(a map[string]interface{}) {
+				a["status"] = map[string]interface{}{"response": "tentativelyAccepted", "time": "2023-10-03T05:30:00Z"}
+				a["proposedNewTime"] = map[string]interface{}{
+					"start": map[string]interface{}{"dateTime": "2023-10-20T11:00:00.0000000", "timeZone": "Tokyo Standard Time"},
+					"end":   map[string]interface{}{"dateTime": "2023-10-20T12:00:00.0000000", "timeZone": "Tokyo Standard Time"},
+				}
+			},
+			want: func(d *dto.MGraphAttendeeDto) {
+				proposedStart := time.Date(2023, 10, 20, 2, 0, 0, 0, time.UTC)
+				proposedEnd := time.Date(2023, 10, 20, 3, 0, 0, 0, time.UTC)
+				d.ResponseStatus = stringPtr("tentativelyAccepted")
+				d.ProposedStartTime = &proposedStart
+				d.ProposedEndTime = &proposedEnd
+			},
+		},
+		{
+			name: "unreadable proposed new time",
+			patch: func(a map[string]interface{}) {
+				a["proposedNewTime"] = map[string]interface{}{
+					"start": map[string]interface{}{"dateTime": "2023-10-20T11:00:00.0000000", "timeZone": "Tokyo Standard Time"},
+				}
+			},
+			wantWarnings: []Warning{{Field: "proposedNewTime", Message: "end: missing"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attendee := graphModel(t, cannedAttendee, tt.patch, graphmodels.CreateAttendeeFromDiscriminatorValue).(graphmodels.Attendeeable)
+
+			got, warnings, err := Attendee(attendee, "040000008200E00074C5B7101A82E00800000000")
+			if assertError(t, err, tt.wantErr) {
+				return
+			}
+
+			want := mappedCannedAttendee()
+			if tt.want != nil {
+				tt.want(&want)
+			}
+			want.CreatedAt = got.CreatedAt
+			want.UpdatedAt = got.UpdatedAt
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Attendee() = %+v, want %+v", got, want)
+			}
+			assertWarnings(t, warnings, tt.wantWarnings)
+		})
+	}
+}
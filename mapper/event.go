@@ -0,0 +1,222 @@
+package mapper
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	graphmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/scheduler-prototype/dto"
+	"github.com/scheduler-prototype/timezone"
+)
+
+// Event maps the graph event of the given user, the event can't be stored without its ids, start and end
+func Event(event graphmodels.Eventable, userId string) (*dto.MGraphEventDto, []Warning, error) {
+	ws := warnings{}
+
+	if event == nil {
+		return nil, nil, errors.New("event is nil")
+	}
+	if event.GetId() == nil {
+		return nil, nil, errors.New("id: missing")
+	}
+	if event.GetICalUId() == nil {
+		return nil, nil, errors.New("iCalUId: missing")
+	}
+
+	startDateTime, startTimeZone, err := dateTimeTimeZone(event.GetStart(), "start")
+	if err != nil {
+		return nil, nil, err
+	}
+	endDateTime, endTimeZone, err := dateTimeTimeZone(event.GetEnd(), "end")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Graph returns start and end as wall clock times in the given zone, store them as UTC instants
+	startTime, err := timezone.ParseDateTimeTimeZone(startDateTime, startTimeZone)
+	if err != nil {
+		return nil, nil, errors.New("start: " + err.Error())
+	}
+
+	endTime, err := timezone.ParseDateTimeTimeZone(endDateTime, endTimeZone)
+	if err != nil {
+		return nil, nil, errors.New("end: " + err.Error())
+	}
+
+	// keep the zone the event was created in for display, it's usually a Windows zone name
+	displayTimezone := startTimeZone
+	if event.GetOriginalStartTimeZone() != nil && *event.GetOriginalStartTimeZone() != "" {
+		displayTimezone = *event.GetOriginalStartTimeZone()
+	}
+
+	ianaTimezone, err := timezone.ToIANA(displayTimezone)
+	if err != nil {
+		ws.add("originalStartTimeZone", "unknown zone "+displayTimezone+", using Etc/UTC")
+		ianaTimezone = "Etc/UTC"
+	}
+
+	// all day events are floating dates, midnight to midnight in whatever zone they are viewed in
+	// -- keep the dates as they are and anchor the instants to midnight in the event's own zone
+	isAllDay := boolOr(event.GetIsAllDay(), false, "isAllDay", &ws)
+	var startDate, endDate *time.Time
+	if isAllDay {
+		location, err := time.LoadLocation(ianaTimezone)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		startDay, err := parseDate(startDateTime)
+		if err != nil {
+			return nil, nil, errors.New("start: " + err.Error())
+		}
+
+		endDay, err := parseDate(endDateTime)
+		if err != nil {
+			return nil, nil, errors.New("end: " + err.Error())
+		}
+
+		startDate = &startDay
+		endDate = &endDay
+		startTime = time.Date(startDay.Year(), startDay.Month(), startDay.Day(), 0, 0, 0, 0, location).UTC()
+		endTime = time.Date(endDay.Year(), endDay.Month(), endDay.Day(), 0, 0, 0, 0, location).UTC()
+	}
+
+	var meetingUrl *string
+	if event.GetOnlineMeeting() != nil {
+		meetingUrl = event.GetOnlineMeeting().GetJoinUrl()
+	}
+
+	// free/busy status, Graph omits it on some event types so default to busy
+	showAs := graphmodels.BUSY_FREEBUSYSTATUS.String()
+	if event.GetShowAs() != nil {
+		showAs = event.GetShowAs().String()
+	}
+
+	description := ""
+	if event.GetBody() != nil {
+		description = stringOr(event.GetBody().GetContent(), "", "body.content", &ws)
+	} else {
+		ws.missing("body")
+	}
+
+	eventType := graphmodels.SINGLEINSTANCE_EVENTTYPE.String()
+	if event.GetTypeEscaped() != nil {
+		eventType = event.GetTypeEscaped().String()
+	} else {
+		ws.add("type", "missing, using "+eventType)
+	}
+
+	now := time.Now()
+	createdTime := now
+	if event.GetCreatedDateTime() != nil {
+		createdTime = *event.GetCreatedDateTime()
+	} else {
+		ws.add("createdDateTime", "missing, using the time of the sync")
+	}
+
+	updatedTime := createdTime
+	if event.GetLastModifiedDateTime() != nil {
+		updatedTime = *event.GetLastModifiedDateTime()
+	} else {
+		ws.add("lastModifiedDateTime", "missing, using createdDateTime")
+	}
+
+	// the organizer's user id is only known when the copy is theirs
+	var organizerUserId *string
+	if boolOrDefault(event.GetIsOrganizer(), false) {
+		organizerUserId = &userId
+	}
+
+	eventDto := &dto.MGraphEventDto{
+		UserId:          userId,
+		ICalUid:         *event.GetICalUId(),
+		EventId:         *event.GetId(),
+		Title:           stringOr(event.GetSubject(), "", "subject", &ws),
+		Description:     description,
+		LocationsCount:  len(event.GetLocations()),
+		StartTime:       startTime,
+		EndTime:         endTime,
+		IsOnline:        boolOr(event.GetIsOnlineMeeting(), false, "isOnlineMeeting", &ws),
+		IsAllDay:        isAllDay,
+		IsCancelled:     boolOr(event.GetIsCancelled(), false, "isCancelled", &ws),
+		OrganizerUserId: organizerUserId,
+		CreatedTime:     createdTime,
+		UpdatedTime:     updatedTime,
+		Timezone:        displayTimezone,
+		PlatformUrl:     stringOr(event.GetWebLink(), "", "webLink", &ws),
+		MeetingUrl:      meetingUrl,
+		Type:            eventType,
+		IsRecurring:     event.GetSeriesMasterId() != nil,
+		SeriesMasterId:  event.GetSeriesMasterId(),
+		CreatedAt:       now,
+		UpdatedAt:       now,
+		IanaTimezone:    ianaTimezone,
+		StartDate:       startDate,
+		EndDate:         endDate,
+		ShowAs:          showAs,
+
+		Importance:                 graphmodels.NORMAL_IMPORTANCE.String(),
+		Sensitivity:                graphmodels.NORMAL_SENSITIVITY.String(),
+		Categories:                 event.GetCategories(),
+		IsReminderOn:               boolOrDefault(event.GetIsReminderOn(), true),
+		ReminderMinutesBeforeStart: event.GetReminderMinutesBeforeStart(),
+		ResponseRequested:          boolOrDefault(event.GetResponseRequested(), true),
+		AllowNewTimeProposals:      boolOrDefault(event.GetAllowNewTimeProposals(), true),
+		HideAttendees:              boolOrDefault(event.GetHideAttendees(), false),
+	}
+
+	if event.GetImportance() != nil {
+		eventDto.Importance = event.GetImportance().String()
+	}
+	if event.GetSensitivity() != nil {
+		eventDto.Sensitivity = event.GetSensitivity().String()
+	}
+	if eventDto.Categories == nil {
+		eventDto.Categories = []string{}
+	}
+	if event.GetOrganizer() != nil && event.GetOrganizer().GetEmailAddress() != nil {
+		eventDto.OrganizerEmail = event.GetOrganizer().GetEmailAddress().GetAddress()
+	}
+
+	// private events are flagged so only their owner sees them in full
+	eventDto.IsPrivate = eventDto.Sensitivity == graphmodels.PRIVATE_SENSITIVITY.String()
+
+	return eventDto, ws, nil
+}
+
+// EventSeriesException maps a modified occurrence of the series, nil when the event isn't an exception
+func EventSeriesException(event graphmodels.Eventable, eventSeriesId uuid.UUID) *dto.MGraphEventSeriesExceptionDto {
+	if event.GetTypeEscaped() == nil || *event.GetTypeEscaped() != graphmodels.EXCEPTION_EVENTTYPE ||
+		event.GetOriginalStart() == nil || event.GetId() == nil {
+		return nil
+	}
+
+	return &dto.MGraphEventSeriesExceptionDto{
+		EventSeriesId: eventSeriesId,
+		EventId:       *event.GetId(),
+		ExceptionType: dto.ModifiedExceptionType,
+		OriginalStart: *event.GetOriginalStart(),
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+}
+
+func dateTimeTimeZone(value graphmodels.DateTimeTimeZoneable, field string) (string, string, error) {
+	if value == nil || value.GetDateTime() == nil {
+		return "", "", errors.New(field + ": missing")
+	}
+	if value.GetTimeZone() == nil {
+		return "", "", errors.New(field + ".timeZone: missing")
+	}
+
+	return *value.GetDateTime(), *value.GetTimeZone(), nil
+}
+
+func parseDate(dateTime string) (time.Time, error) {
+	if len(dateTime) < 10 {
+		return time.Time{}, errors.New("not a date " + dateTime)
+	}
+
+	return time.Parse("2006-01-02", dateTime[:10])
+}
@@ -0,0 +1,85 @@
+package mapper
+
+import (
+	"errors"
+	"time"
+
+	graphmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/scheduler-prototype/dto"
+)
+
+// EventSeries maps the recurrence of a series master
+func EventSeries(event graphmodels.Eventable) (*dto.MGraphEventSeriesDto, []Warning, error) {
+	ws := warnings{}
+
+	if event == nil || event.GetId() == nil || event.GetICalUId() == nil {
+		return nil, nil, errors.New("id and iCalUId: missing")
+	}
+	if event.GetRecurrence() == nil {
+		return nil, nil, errors.New("recurrence: missing")
+	}
+
+	pattern := event.GetRecurrence().GetPattern()
+	recurrenceRange := event.GetRecurrence().GetRangeEscaped()
+
+	series := &dto.MGraphEventSeriesDto{
+		SeriesMasterId: *event.GetId(),
+		ICalUid:        *event.GetICalUId(),
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+
+	if pattern != nil {
+		if pattern.GetTypeEscaped() != nil {
+			series.PatternType = pattern.GetTypeEscaped().String()
+		}
+		if pattern.GetInterval() != nil {
+			series.PatternInterval = *pattern.GetInterval()
+		}
+		for _, dayOfWeek := range pattern.GetDaysOfWeek() {
+			series.PatternDaysOfWeek = append(series.PatternDaysOfWeek, dayOfWeek.String())
+		}
+		series.PatternDayOfMonth = pattern.GetDayOfMonth()
+		series.PatternMonth = pattern.GetMonth()
+		if pattern.GetIndex() != nil {
+			index := pattern.GetIndex().String()
+			series.PatternIndex = &index
+		}
+		if pattern.GetFirstDayOfWeek() != nil {
+			firstDayOfWeek := pattern.GetFirstDayOfWeek().String()
+			series.PatternFirstDayOfWeek = &firstDayOfWeek
+		}
+	} else {
+		ws.missing("recurrence.pattern")
+	}
+
+	if recurrenceRange != nil {
+		if recurrenceRange.GetTypeEscaped() != nil {
+			series.RangeType = recurrenceRange.GetTypeEscaped().String()
+		}
+		if recurrenceRange.GetStartDate() != nil {
+			startDate, err := time.Parse("2006-01-02", recurrenceRange.GetStartDate().String())
+			if err != nil {
+				ws.add("recurrence.range.startDate", err.Error())
+			}
+			series.RangeStartDate = startDate
+		}
+		// Graph sends 0001-01-01 as the end date of series without one
+		if recurrenceRange.GetEndDate() != nil && recurrenceRange.GetEndDate().String() != "0001-01-01" {
+			endDate, err := time.Parse("2006-01-02", recurrenceRange.GetEndDate().String())
+			if err != nil {
+				ws.add("recurrence.range.endDate", err.Error())
+			} else {
+				series.RangeEndDate = &endDate
+			}
+		}
+		if recurrenceRange.GetNumberOfOccurrences() != nil && *recurrenceRange.GetNumberOfOccurrences() > 0 {
+			series.RangeNumberOfOccurrences = recurrenceRange.GetNumberOfOccurrences()
+		}
+		series.RecurrenceTimeZone = recurrenceRange.GetRecurrenceTimeZone()
+	} else {
+		ws.missing("recurrence.range")
+	}
+
+	return series, ws, nil
+}
@@ -0,0 +1,172 @@
+package mapper
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	graphmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/scheduler-prototype/dto"
+)
+
+const cannedSeriesMaster = `{
+	"id": "AAMkAGI1AAAt9AHjMASTER=",
+	"iCalUId": "040000008200E00074C5B7101A82E00800000000",
+	"type": "seriesMaster",
+	"recurrence": {
+		"pattern": {
+			"type": "weekly",
+			"interval": 2,
+			"month": 0,
+			"dayOfMonth": 0,
+			"daysOfWeek": ["monday", "thursday"],
+			"firstDayOfWeek": "sunday",
+			"index": "first"
+		},
+		"range": {
+			"type": "endDate",
+			"startDate": "2023-10-02",
+			"endDate": "2023-12-28",
+			"recurrenceTimeZone": "Tokyo Standard Time",
+			"numberOfOccurrences": 0
+		}
+	}
+}`
+
+func mappedCannedSeriesMaster() *dto.MGraphEventSeriesDto {
+	endDate := time.Date(2023, 12, 28, 0, 0, 0, 0, time.UTC)
+	var zero int32
+
+	return &dto.MGraphEventSeriesDto{
+		SeriesMasterId:        "AAMkAGI1AAAt9AHjMASTER=",
+		ICalUid:               "040000008200E00074C5B7101A82E00800000000",
+		PatternType:           "weekly",
+		PatternInterval:       2,
+		PatternDaysOfWeek:     []string{"monday", "thursday"},
+		PatternDayOfMonth:     &zero,
+		PatternMonth:          &zero,
+		PatternIndex:          stringPtr("first"),
+		PatternFirstDayOfWeek: stringPtr("sunday"),
+		RangeType:             "endDate",
+		RangeStartDate:        time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC),
+		RangeEndDate:          &endDate,
+		RecurrenceTimeZone:    stringPtr("Tokyo Standard Time"),
+	}
+}
+
+func recurrenceOf(e map[string]interface{}) map[string]interface{} {
+	return e["recurrence"].(map[string]interface{})
+}
+
+func TestEventSeries(t *testing.T) {
+	tests := []struct {
+		name         string
+		patch        func(map[string]interface{})
+		want         func(*dto.MGraphEventSeriesDto)
+		wantWarnings []Warning
+		wantErr      string
+	}{
+		{
+			name: "series with an end date",
+		},
+		{
+			name: "series without an end",
+			patch: func(e map[string]interface{}) {
+				recurrenceOf(e)["range"] = map[string]interface{}{
+					"type": "noEnd", "startDate": "2023-10-02", "endDate": "0001-01-01", "recurrenceTimeZone": "Tokyo Standard Time",
+				}
+			},
+			want: func(d *dto.MGraphEventSeriesDto) {
+				d.RangeType = "noEnd"
+				d.RangeEndDate = nil
+			},
+		},
+		{
+			name: "numbered series",
+			patch: func(e map[string]interface{}) {
+				recurrenceOf(e)["range"] = map[string]interface{}{
+					"type": "numbered", "startDate": "2023-10-02", "endDate": "0001-01-01", "numberOfOccurrences": 10,
+				}
+			},
+			want: func(d *dto.MGraphEventSeriesDto) {
+				d.RangeType = "numbered"
+				d.RangeEndDate = nil
+				d.RangeNumberOfOccurrences = int32Ptr(10)
+				d.RecurrenceTimeZone = nil
+			},
+		},
+		{
+			name: "monthly on a day of the month",
+			patch: func(e map[string]interface{}) {
+				recurrenceOf(e)["pattern"] = map[string]interface{}{"type": "absoluteMonthly", "interval": 1, "dayOfMonth": 15}
+			},
+			want: func(d *dto.MGraphEventSeriesDto) {
+				d.PatternType = "absoluteMonthly"
+				d.PatternInterval = 1
+				d.PatternDaysOfWeek = nil
+				d.PatternDayOfMonth = int32Ptr(15)
+				d.PatternMonth = nil
+				d.PatternIndex = nil
+				d.PatternFirstDayOfWeek = nil
+			},
+		},
+		{
+			name:  "missing pattern",
+			patch: func(e map[string]interface{}) { delete(recurrenceOf(e), "pattern") },
+			want: func(d *dto.MGraphEventSeriesDto) {
+				d.PatternType = ""
+				d.PatternInterval = 0
+				d.PatternDaysOfWeek = nil
+				d.PatternDayOfMonth = nil
+				d.PatternMonth = nil
+				d.PatternIndex = nil
+				d.PatternFirstDayOfWeek = nil
+			},
+			wantWarnings: []Warning{{Field: "recurrence.pattern", Message: "missing"}},
+		},
+		{
+			name:  "missing range",
+			patch: func(e map[string]interface{}) { delete(recurrenceOf(e), "range") },
+			want: func(d *dto.MGraphEventSeriesDto) {
+				d.RangeType = ""
+				d.RangeStartDate = time.Time{}
+				d.RangeEndDate = nil
+				d.RecurrenceTimeZone = nil
+			},
+			wantWarnings: []Warning{{Field: "recurrence.range", Message: "missing"}},
+		},
+		{
+			name:    "missing recurrence",
+			patch:   func(e map[string]interface{}) { delete(e, "recurrence") },
+			wantErr: "recurrence: missing",
+		},
+		{
+			name:    "missing iCalUId",
+			patch:   func(e map[string]interface{}) { delete(e, "iCalUId") },
+			wantErr: "id and iCalUId: missing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := graphModel(t, cannedSeriesMaster, tt.patch, graphmodels.CreateEventFromDiscriminatorValue).(graphmodels.Eventable)
+
+			got, warnings, err := EventSeries(event)
+			if assertError(t, err, tt.wantErr) {
+				return
+			}
+
+			want := mappedCannedSeriesMaster()
+			if tt.want != nil {
+				tt.want(want)
+			}
+			want.CreatedAt = got.CreatedAt
+			want.UpdatedAt = got.UpdatedAt
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("EventSeries() = %+v, want %+v", got, want)
+			}
+			assertWarnings(t, warnings, tt.wantWarnings)
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package mapper
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	graphmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/scheduler-prototype/dto"
+)
+
+const cannedEvent = `{
+	"id": "AAMkAGI1AAAt9AHjAAA=",
+	"iCalUId": "040000008200E00074C5B7101A82E00800000000",
+	"subject": "Planning",
+	"body": {"contentType": "html", "content": "<p>Agenda</p>"},
+	"start": {"dateTime": "2023-10-20T09:00:00.0000000", "timeZone": "Tokyo Standard Time"},
+	"end": {"dateTime": "2023-10-20T10:00:00.0000000", "timeZone": "Tokyo Standard Time"},
+	"originalStartTimeZone": "Tokyo Standard Time",
+	"isAllDay": false,
+	"isOnlineMeeting": true,
+	"onlineMeeting": {"joinUrl": "https://teams.microsoft.com/l/meetup-join/1"},
+	"isCancelled": false,
+	"isOrganizer": true,
+	"webLink": "https://outlook.office365.com/owa/?itemid=1",
+	"type": "singleInstance",
+	"createdDateTime": "2023-10-01T08:00:00Z",
+	"lastModifiedDateTime": "2023-10-02T08:00:00Z",
+	"showAs": "tentative",
+	"importance": "high",
+	"sensitivity": "normal",
+	"categories": ["Blue category"],
+	"reminderMinutesBeforeStart": 15,
+	"organizer": {"emailAddress": {"name": "Ada", "address": "ada@example.com"}},
+	"locations": [{"displayName": "Room 1"}]
+}`
+
+// mappedCannedEvent is cannedEvent as it's stored for user-1
+func mappedCannedEvent() *dto.MGraphEventDto {
+	return &dto.MGraphEventDto{
+		UserId:          "user-1",
+		ICalUid:         "040000008200E00074C5B7101A82E00800000000",
+		EventId:         "AAMkAGI1AAAt9AHjAAA=",
+		Title:           "Planning",
+		Description:     "<p>Agenda</p>",
+		LocationsCount:  1,
+		StartTime:       time.Date(2023, 10, 20, 0, 0, 0, 0, time.UTC),
+		EndTime:         time.Date(2023, 10, 20, 1, 0, 0, 0, time.UTC),
+		IsOnline:        true,
+		OrganizerUserId: stringPtr("user-1"),
+		CreatedTime:     time.Date(2023, 10, 1, 8, 0, 0, 0, time.UTC),
+		UpdatedTime:     time.Date(2023, 10, 2, 8, 0, 0, 0, time.UTC),
+		Timezone:        "Tokyo Standard Time",
+		PlatformUrl:     "https://outlook.office365.com/owa/?itemid=1",
+		MeetingUrl:      stringPtr("https://teams.microsoft.com/l/meetup-join/1"),
+		Type:            "singleInstance",
+		IanaTimezone:    "Asia/Tokyo",
+		ShowAs:          "tentative",
+
+		Importance:                 "high",
+		Sensitivity:                "normal",
+		Categories:                 []string{"Blue category"},
+		IsReminderOn:               true,
+		ReminderMinutesBeforeStart: int32Ptr(15),
+		ResponseRequested:          true,
+		AllowNewTimeProposals:      true,
+		OrganizerEmail:             stringPtr("ada@example.com"),
+	}
+}
+
+func TestEvent(t *testing.T) {
+	tests := []struct {
+		name         string
+		patch        func(map[string]interface{})
+		want         func(*dto.MGraphEventDto)
+		wantWarnings []Warning
+		wantErr      string
+	}{
+		{
+			name: "complete event",
+		},
+		{
+			name:         "missing body",
+			patch:        func(e map[string]interface{}) { delete(e, "body") },
+			want:         func(d *dto.MGraphEventDto) { d.Description = "" },
+			wantWarnings: []Warning{{Field: "body", Message: "missing"}},
+		},
+		{
+			name:         "body without content",
+			patch:        func(e map[string]interface{}) { e["body"] = map[string]interface{}{"contentType": "text"} },
+			want:         func(d *dto.MGraphEventDto) { d.Description = "" },
+			wantWarnings: []Warning{{Field: "body.content", Message: "missing"}},
+		},
+		{
+			name:    "missing iCalUId",
+			patch:   func(e map[string]interface{}) { delete(e, "iCalUId") },
+			wantErr: "iCalUId: missing",
+		},
+		{
+			name:    "missing id",
+			patch:   func(e map[string]interface{}) { delete(e, "id") },
+			wantErr: "id: missing",
+		},
+		{
+			name: "missing start time zone",
+			patch: func(e map[string]interface{}) {
+				e["start"] = map[string]interface{}{"dateTime": "2023-10-20T09:00:00.0000000"}
+			},
+			wantErr: "start.timeZone: missing",
+		},
+		{
+			name: "occurrence of a series",
+			patch: func(e map[string]interface{}) {
+				e["type"] = "occurrence"
+				e["seriesMasterId"] = "AAMkAGI1AAAt9AHjMASTER="
+			},
+			want: func(d *dto.MGraphEventDto) {
+				d.Type = "occurrence"
+				d.IsRecurring = true
+				d.SeriesMasterId = stringPtr("AAMkAGI1AAAt9AHjMASTER=")
+			},
+		},
+		{
+			name:  "copy of an attendee",
+			patch: func(e map[string]interface{}) { e["isOrganizer"] = false },
+			want:  func(d *dto.MGraphEventDto) { d.OrganizerUserId = nil },
+		},
+		{
+			name:  "private event",
+			patch: func(e map[string]interface{}) { e["sensitivity"] = "private" },
+			want: func(d *dto.MGraphEventDto) {
+				d.Sensitivity = "private"
+				d.IsPrivate = true
+			},
+		},
+		{
+			name:  "unknown time zone",
+			patch: func(e map[string]interface{}) { e["originalStartTimeZone"] = "Customized Time Zone" },
+			want: func(d *dto.MGraphEventDto) {
+				d.Timezone = "Customized Time Zone"
+				d.IanaTimezone = "Etc/UTC"
+			},
+			wantWarnings: []Warning{{Field: "originalStartTimeZone", Message: "unknown zone Customized Time Zone, using Etc/UTC"}},
+		},
+		{
+			name: "all day event",
+			patch: func(e map[string]interface{}) {
+				e["isAllDay"] = true
+				e["start"] = map[string]interface{}{"dateTime": "2023-10-20T00:00:00.0000000", "timeZone": "UTC"}
+				e["end"] = map[string]interface{}{"dateTime": "2023-10-21T00:00:00.0000000", "timeZone": "UTC"}
+			},
+			want: func(d *dto.MGraphEventDto) {
+				startDate := time.Date(2023, 10, 20, 0, 0, 0, 0, time.UTC)
+				endDate := time.Date(2023, 10, 21, 0, 0, 0, 0, time.UTC)
+				d.IsAllDay = true
+				d.StartDate = &startDate
+				d.EndDate = &endDate
+				// midnight in Tokyo
+				d.StartTime = time.Date(2023, 10, 19, 15, 0, 0, 0, time.UTC)
+				d.EndTime = time.Date(2023, 10, 20, 15, 0, 0, 0, time.UTC)
+			},
+		},
+		{
+			name: "sparse event",
+			patch: func(e map[string]interface{}) {
+				for _, property := range []string{"subject", "isAllDay", "isOnlineMeeting", "onlineMeeting", "isCancelled", "webLink", "type",
+					"createdDateTime", "lastModifiedDateTime", "showAs", "importance", "sensitivity", "categories", "organizer"} {
+					delete(e, property)
+				}
+			},
+			want: func(d *dto.MGraphEventDto) {
+				d.Title = ""
+				d.IsOnline = false
+				d.MeetingUrl = nil
+				d.PlatformUrl = ""
+				d.ShowAs = "busy"
+				d.Importance = "normal"
+				d.Sensitivity = "normal"
+				d.Categories = []string{}
+				d.OrganizerEmail = nil
+				// left to the time of the sync
+				d.CreatedTime = time.Time{}
+				d.UpdatedTime = time.Time{}
+			},
+			wantWarnings: []Warning{
+				{Field: "isAllDay", Message: "missing"},
+				{Field: "type", Message: "missing, using singleInstance"},
+				{Field: "createdDateTime", Message: "missing, using the time of the sync"},
+				{Field: "lastModifiedDateTime", Message: "missing, using createdDateTime"},
+				{Field: "subject", Message: "missing"},
+				{Field: "isOnlineMeeting", Message: "missing"},
+				{Field: "isCancelled", Message: "missing"},
+				{Field: "webLink", Message: "missing"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := graphModel(t, cannedEvent, tt.patch, graphmodels.CreateEventFromDiscriminatorValue).(graphmodels.Eventable)
+
+			got, warnings, err := Event(event, "user-1")
+			if assertError(t, err, tt.wantErr) {
+				return
+			}
+
+			want := mappedCannedEvent()
+			if tt.want != nil {
+				tt.want(want)
+			}
+
+			// the sync times are taken when mapping
+			if want.CreatedTime.IsZero() {
+				if !got.UpdatedTime.Equal(got.CreatedTime) {
+					t.Errorf("UpdatedTime = %v, want CreatedTime %v", got.UpdatedTime, got.CreatedTime)
+				}
+				want.CreatedTime = got.CreatedTime
+				want.UpdatedTime = got.UpdatedTime
+			}
+			want.CreatedAt = got.CreatedAt
+			want.UpdatedAt = got.UpdatedAt
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Event() = %+v, want %+v", got, want)
+			}
+			assertWarnings(t, warnings, tt.wantWarnings)
+		})
+	}
+}
+
+func TestEventSeriesException(t *testing.T) {
+	seriesId := uuid.MustParse("6f1d7c1e-3b1a-4c55-9a53-0d6cb1a5f0a1")
+	originalStart := time.Date(2023, 10, 27, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		patch func(map[string]interface{})
+		want  *dto.MGraphEventSeriesExceptionDto
+	}{
+		{
+			name: "modified occurrence",
+			patch: func(e map[string]interface{}) {
+				e["type"] = "exception"
+				e["originalStart"] = "2023-10-27T00:00:00Z"
+			},
+			want: &dto.MGraphEventSeriesExceptionDto{
+				EventSeriesId: seriesId,
+				EventId:       "AAMkAGI1AAAt9AHjAAA=",
+				ExceptionType: dto.ModifiedExceptionType,
+				OriginalStart: originalStart,
+			},
+		},
+		{
+			name:  "plain occurrence",
+			patch: func(e map[string]interface{}) { e["type"] = "occurrence" },
+		},
+		{
+			name:  "exception without its original start",
+			patch: func(e map[string]interface{}) { e["type"] = "exception" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := graphModel(t, cannedEvent, tt.patch, graphmodels.CreateEventFromDiscriminatorValue).(graphmodels.Eventable)
+
+			got := EventSeriesException(event, seriesId)
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("EventSeriesException() = %+v, want nil", got)
+				}
+				return
+			}
+
+			if got == nil {
+				t.Fatalf("EventSeriesException() = nil, want %+v", tt.want)
+			}
+			tt.want.CreatedAt = got.CreatedAt
+			tt.want.UpdatedAt = got.UpdatedAt
+			if !got.OriginalStart.Equal(tt.want.OriginalStart) {
+				t.Errorf("OriginalStart = %v, want %v", got.OriginalStart, tt.want.OriginalStart)
+			}
+			tt.want.OriginalStart = got.OriginalStart
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EventSeriesException() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package mapper
+
+import (
+	"errors"
+
+	graphmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/scheduler-prototype/dto"
+)
+
+// Location maps a location of the meeting, locations are matched on their display name so it can't be missing
+func Location(location graphmodels.Locationable, iCalUid string) (dto.MGraphLocationDto, []Warning, error) {
+	ws := warnings{}
+
+	if location == nil || location.GetDisplayName() == nil {
+		return dto.MGraphLocationDto{}, nil, errors.New("displayName: missing")
+	}
+
+	// combine address props to create a single string, nil when the location has no address
+	var address *string
+	if physicalAddress := location.GetAddress(); physicalAddress != nil {
+		fullAddress := stringValue(physicalAddress.GetStreet()) + ", " +
+			stringValue(physicalAddress.GetCity()) + ", " +
+			stringValue(physicalAddress.GetState()) + ", " +
+			stringValue(physicalAddress.GetPostalCode()) + ", " +
+			stringValue(physicalAddress.GetCountryOrRegion())
+		if fullAddress != ", , , , " {
+			address = &fullAddress
+		}
+	}
+
+	return dto.MGraphLocationDto{
+		ICalUid:     iCalUid,
+		DisplayName: *location.GetDisplayName(),
+		LocationUri: location.GetLocationUri(),
+		Address:     address,
+	}, ws, nil
+}
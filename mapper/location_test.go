@@ -0,0 +1,92 @@
+package mapper
+
+import (
+	"reflect"
+	"testing"
+
+	graphmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/scheduler-prototype/dto"
+)
+
+const cannedLocation = `{
+	"displayName": "Room 1",
+	"locationUri": "room1@example.com",
+	"address": {
+		"street": "1-1 Marunouchi",
+		"city": "Chiyoda",
+		"state": "Tokyo",
+		"postalCode": "100-0005",
+		"countryOrRegion": "Japan"
+	}
+}`
+
+func TestLocation(t *testing.T) {
+	tests := []struct {
+		name         string
+		patch        func(map[string]interface{})
+		want         dto.MGraphLocationDto
+		wantWarnings []Warning
+		wantErr      string
+	}{
+		{
+			name: "complete location",
+			want: dto.MGraphLocationDto{
+				ICalUid:     "040000008200E00074C5B7101A82E00800000000",
+				DisplayName: "Room 1",
+				LocationUri: stringPtr("room1@example.com"),
+				Address:     stringPtr("1-1 Marunouchi, Chiyoda, Tokyo, 100-0005, Japan"),
+			},
+		},
+		{
+			name:  "missing location address",
+			patch: func(l map[string]interface{}) { delete(l, "address") },
+			want: dto.MGraphLocationDto{
+				ICalUid:     "040000008200E00074C5B7101A82E00800000000",
+				DisplayName: "Room 1",
+				LocationUri: stringPtr("room1@example.com"),
+			},
+		},
+		{
+			name:  "empty location address",
+			patch: func(l map[string]interface{}) { l["address"] = map[string]interface{}{} },
+			want: dto.MGraphLocationDto{
+				ICalUid:     "040000008200E00074C5B7101A82E00800000000",
+				DisplayName: "Room 1",
+				LocationUri: stringPtr("room1@example.com"),
+			},
+		},
+		{
+			name: "partial location address",
+			patch: func(l map[string]interface{}) {
+				delete(l, "locationUri")
+				l["address"] = map[string]interface{}{"city": "Chiyoda"}
+			},
+			want: dto.MGraphLocationDto{
+				ICalUid:     "040000008200E00074C5B7101A82E00800000000",
+				DisplayName: "Room 1",
+				Address:     stringPtr(", Chiyoda, , , "),
+			},
+		},
+		{
+			name:    "missing displayName",
+			patch:   func(l map[string]interface{}) { delete(l, "displayName") },
+			wantErr: "displayName: missing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			location := graphModel(t, cannedLocation, tt.patch, graphmodels.CreateLocationFromDiscriminatorValue).(graphmodels.Locationable)
+
+			got, warnings, err := Location(location, "040000008200E00074C5B7101A82E00800000000")
+			if assertError(t, err, tt.wantErr) {
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Location() = %+v, want %+v", got, tt.want)
+			}
+			assertWarnings(t, warnings, tt.wantWarnings)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+// Package mapper converts Microsoft Graph models into the DTOs we store.
+// Graph leaves out properties freely, so nothing is dereferenced unchecked:
+// a missing optional field falls back to its default and is reported as a Warning,
+// only fields a row can't exist without make the mapping fail.
+package mapper
+
+import "fmt"
+
+// Warning is a field that was missing or unreadable and got its default instead
+type Warning struct {
+	Field   string
+	Message string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: %s", w.Field, w.Message)
+}
+
+// warnings collects the warnings of a single mapping
+type warnings []Warning
+
+func (ws *warnings) add(field string, message string) {
+	*ws = append(*ws, Warning{Field: field, Message: message})
+}
+
+func (ws *warnings) missing(field string) {
+	ws.add(field, "missing")
+}
+
+func stringOr(value *string, fallback string, field string, ws *warnings) string {
+	if value == nil {
+		ws.missing(field)
+		return fallback
+	}
+	return *value
+}
+
+func boolOr(value *bool, fallback bool, field string, ws *warnings) bool {
+	if value == nil {
+		ws.missing(field)
+		return fallback
+	}
+	return *value
+}
+
+// boolOrDefault is for fields Graph only sends when they differ from the default, they aren't warned about
+func boolOrDefault(value *bool, fallback bool) bool {
+	if value == nil {
+		return fallback
+	}
+	return *value
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,75 @@
+package mapper
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/microsoft/kiota-abstractions-go/serialization"
+	msjson "github.com/microsoft/kiota-serialization-json-go"
+)
+
+// graphModel parses the canned Graph JSON after patch has changed its properties,
+// so a case only spells out what it leaves out or adds
+func graphModel(t *testing.T, content string, patch func(map[string]interface{}), factory serialization.ParsableFactory) serialization.Parsable {
+	t.Helper()
+
+	properties := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(content), &properties); err != nil {
+		t.Fatalf("canned json: %v", err)
+	}
+	if patch != nil {
+		patch(properties)
+	}
+
+	patched, err := json.Marshal(properties)
+	if err != nil {
+		t.Fatalf("canned json: %v", err)
+	}
+
+	parseNode, err := msjson.NewJsonParseNode(patched)
+	if err != nil {
+		t.Fatalf("parse node: %v", err)
+	}
+	model, err := parseNode.GetObjectValue(factory)
+	if err != nil {
+		t.Fatalf("parse model: %v", err)
+	}
+
+	return model
+}
+
+func assertWarnings(t *testing.T, got []Warning, want []Warning) {
+	t.Helper()
+
+	if len(got) == 0 && len(want) == 0 {
+		return
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("warnings = %v, want %v", got, want)
+	}
+}
+
+func assertError(t *testing.T, err error, want string) bool {
+	t.Helper()
+
+	if want == "" {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return false
+	}
+
+	if err == nil || err.Error() != want {
+		t.Fatalf("error = %v, want %q", err, want)
+	}
+	return true
+}
+
+func stringPtr(value string) *string {
+	return &value
+}
+
+func int32Ptr(value int32) *int32 {
+	return &value
+}
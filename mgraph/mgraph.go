@@ -1,7 +1,9 @@
 package mgraph
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 
 	azidentity "github.com/Azure/azure-sdk-for-go/sdk/azidentity"
@@ -13,16 +15,20 @@ import (
 	requestDto "github.com/scheduler-prototype/dto/request"
 )
 
+// DefaultUserId is the mailbox used by the endpoints that don't take a user yet
+const DefaultUserId = "24dc94f1-08bf-4d47-850b-5690533b8236"
+
 type MGraphInterface interface {
 	GetCalendarView(requestStartDateTime string, requestEndDateTime string) (graphmodels.EventCollectionResponseable, error)
 	PostCreateEvent(requestDto *requestDto.MGraphCreateEventDto) (*graphmodels.Event, error)
 }
 
 type MGraph struct {
-	adapter     *msgraphsdk.GraphRequestAdapter
-	credentials *azidentity.ClientSecretCredential
-	graphClient *msgraphsdk.GraphServiceClient
-	scopes      []string
+	adapter       *msgraphsdk.GraphRequestAdapter
+	credentials   *azidentity.ClientSecretCredential
+	graphClient   *msgraphsdk.GraphServiceClient
+	scopes        []string
+	scheduleCache *scheduleCache
 }
 
 func NewMGraphClient() (*MGraph, error) {
@@ -51,19 +57,45 @@ func NewMGraphClient() (*MGraph, error) {
 	// client, err := msgraphsdk.NewGraphServiceClientWithCredentials(cred, scopes)
 	client := msgraphsdk.NewGraphServiceClient(adapter)
 
-	return &MGraph{adapter: adapter, credentials: cred, graphClient: client, scopes: scopes}, nil
+	return &MGraph{adapter: adapter, credentials: cred, graphClient: client, scopes: scopes, scheduleCache: newScheduleCache()}, nil
 }
 
 func printOdataError(err error) {
-	switch err.(type) {
-	case *odataerrors.ODataError:
-		typed := err.(*odataerrors.ODataError)
-		fmt.Printf("error: %s", typed.Error())
-		if terr := typed.GetErrorEscaped(); terr != nil {
+	var odataError *odataerrors.ODataError
+	if !errors.As(err, &odataError) {
+		fmt.Printf("%T > error: %#v", err, err)
+		return
+	}
+
+	fmt.Printf("error: %s", odataError.Error())
+	if terr := odataError.GetErrorEscaped(); terr != nil {
+		if terr.GetCode() != nil {
 			fmt.Printf("code: %s", *terr.GetCode())
+		}
+		if terr.GetMessage() != nil {
 			fmt.Printf("msg: %s", *terr.GetMessage())
 		}
-	default:
-		fmt.Printf("%T > error: %#v", err, err)
 	}
 }
+
+// ErrNotFound is returned when Graph doesn't know the requested item, e.g. an event deleted in Outlook
+var ErrNotFound = errors.New("item was not found in Microsoft Graph")
+
+// graphError turns a failed request into an error with the message Graph sent,
+// network errors and anything else that didn't come from Graph are returned as they are
+func graphError(err error) error {
+	var odataError *odataerrors.ODataError
+	if !errors.As(err, &odataError) {
+		return err
+	}
+
+	if odataError.ResponseStatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+
+	if terr := odataError.GetErrorEscaped(); terr != nil && terr.GetMessage() != nil {
+		return errors.New(*terr.GetMessage())
+	}
+
+	return err
+}
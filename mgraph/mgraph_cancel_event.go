@@ -0,0 +1,35 @@
+package mgraph
+
+import (
+	"context"
+
+	graphusers "github.com/microsoftgraph/msgraph-sdk-go/users"
+)
+
+func (m *MGraph) PostCancelEvent(userId string, eventId string, comment *string) error {
+	// cancelling an occurrence only removes that occurrence from the series,
+	// cancelling the series master removes the whole series
+	// -- only the organizer can cancel, attendees receive a cancellation message with the comment
+	requestBody := graphusers.NewItemEventsItemCancelPostRequestBody()
+	if comment != nil {
+		requestBody.SetComment(comment)
+	}
+
+	err := m.graphClient.Users().ByUserId(userId).Events().ByEventId(eventId).Cancel().Post(context.Background(), requestBody, nil)
+	if err != nil {
+		printOdataError(err)
+		return graphError(err)
+	}
+
+	return nil
+}
+
+func (m *MGraph) DeleteEvent(userId string, eventId string) error {
+	err := m.graphClient.Users().ByUserId(userId).Events().ByEventId(eventId).Delete(context.Background(), nil)
+	if err != nil {
+		printOdataError(err)
+		return graphError(err)
+	}
+
+	return nil
+}
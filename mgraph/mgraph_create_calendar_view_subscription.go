@@ -2,14 +2,12 @@ package mgraph
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"log"
 	"os"
 	"time"
 
 	graphmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
-	"github.com/microsoftgraph/msgraph-sdk-go/models/odataerrors"
 )
 
 func (m *MGraph) CreateCalendarViewSubscription(userId *string) (graphmodels.Subscriptionable, error) {
@@ -51,8 +49,7 @@ func (m *MGraph) CreateCalendarViewSubscription(userId *string) (graphmodels.Sub
 	subscriptions, err := m.graphClient.Subscriptions().Post(context.Background(), requestBody, nil)
 	if err != nil {
 		printOdataError(err)
-		errorMessage := err.(*odataerrors.ODataError).GetErrorEscaped().GetMessage()
-		return nil, errors.New(*errorMessage)
+		return nil, graphError(err)
 	}
 
 	return subscriptions, nil
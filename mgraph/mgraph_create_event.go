@@ -4,10 +4,10 @@ import (
 	"context"
 	"errors"
 	"log"
+	"time"
 
 	"github.com/microsoft/kiota-abstractions-go/serialization"
 	graphmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
-	"github.com/microsoftgraph/msgraph-sdk-go/models/odataerrors"
 	requestDto "github.com/scheduler-prototype/dto/request"
 )
 
@@ -63,15 +63,27 @@ func (m *MGraph) PostCreateEvent(requestDto *requestDto.MGraphCreateEventDto) (*
 	contentBody.SetContent(&requestDto.Content)
 	requestBody.SetBody(contentBody)
 
+	// All day events take plain dates, the end date being exclusive
+	startTime := requestDto.StartTime
+	endTime := requestDto.EndTime
+	if requestDto.IsAllDay {
+		var err error
+		startTime, endTime, err = allDayDateTimes(requestDto.StartTime, requestDto.EndTime)
+		if err != nil {
+			return nil, err
+		}
+		requestBody.SetIsAllDay(&requestDto.IsAllDay)
+	}
+
 	// Set the start time
 	start := graphmodels.NewDateTimeTimeZone()
-	start.SetDateTime(&requestDto.StartTime)
+	start.SetDateTime(&startTime)
 	start.SetTimeZone(&requestDto.TimeZone)
 	requestBody.SetStart(start)
 
 	// Set the end time
 	end := graphmodels.NewDateTimeTimeZone()
-	end.SetDateTime(&requestDto.EndTime)
+	end.SetDateTime(&endTime)
 	end.SetTimeZone(&requestDto.TimeZone)
 	requestBody.SetEnd(end)
 
@@ -87,8 +99,7 @@ func (m *MGraph) PostCreateEvent(requestDto *requestDto.MGraphCreateEventDto) (*
 			patternType, err := graphmodels.ParseRecurrencePatternType(*requestDto.PatternType)
 			if err != nil {
 				printOdataError(err)
-				errorMessage := err.(*odataerrors.ODataError).GetErrorEscaped().GetMessage()
-				return nil, errors.New(*errorMessage)
+				return nil, graphError(err)
 			}
 
 			if pt, ok := (patternType).(*graphmodels.RecurrencePatternType); ok {
@@ -108,8 +119,7 @@ func (m *MGraph) PostCreateEvent(requestDto *requestDto.MGraphCreateEventDto) (*
 					dayOfWeek, err := graphmodels.ParseDayOfWeek(dayOfWeek)
 					if err != nil {
 						printOdataError(err)
-						errorMessage := err.(*odataerrors.ODataError).GetErrorEscaped().GetMessage()
-						return nil, errors.New(*errorMessage)
+						return nil, graphError(err)
 					}
 
 					if dow, ok := (dayOfWeek).(*graphmodels.DayOfWeek); ok {
@@ -131,8 +141,7 @@ func (m *MGraph) PostCreateEvent(requestDto *requestDto.MGraphCreateEventDto) (*
 			rangeType, err := graphmodels.ParseRecurrenceRangeType(*requestDto.RecurrenceType)
 			if err != nil {
 				printOdataError(err)
-				errorMessage := err.(*odataerrors.ODataError).GetErrorEscaped().GetMessage()
-				return nil, errors.New(*errorMessage)
+				return nil, graphError(err)
 			}
 
 			if rt, ok := (rangeType).(*graphmodels.RecurrenceRangeType); ok {
@@ -143,16 +152,14 @@ func (m *MGraph) PostCreateEvent(requestDto *requestDto.MGraphCreateEventDto) (*
 		serializedRangeStart, err := serialization.ParseDateOnly(*requestDto.RecurrenceStart)
 		if err != nil {
 			printOdataError(err)
-			errorMessage := err.(*odataerrors.ODataError).GetErrorEscaped().GetMessage()
-			return nil, errors.New(*errorMessage)
+			return nil, graphError(err)
 		}
 		recurrenceRange.SetStartDate(serializedRangeStart)
 
 		serializedRangeEnd, err := serialization.ParseDateOnly(*requestDto.RecurrenceEnd)
 		if err != nil {
 			printOdataError(err)
-			errorMessage := err.(*odataerrors.ODataError).GetErrorEscaped().GetMessage()
-			return nil, errors.New(*errorMessage)
+			return nil, graphError(err)
 		}
 		recurrenceRange.SetEndDate(serializedRangeEnd)
 
@@ -161,30 +168,10 @@ func (m *MGraph) PostCreateEvent(requestDto *requestDto.MGraphCreateEventDto) (*
 		requestBody.SetRecurrence(recurrenceObj)
 	}
 
-	// Set attendees
-	attendees := []graphmodels.Attendeeable{}
-	for _, attendee := range requestDto.Attendees {
-		attendeeObj := graphmodels.NewAttendee()
-
-		// Set attendee email information
-		emailObj := graphmodels.NewEmailAddress()
-		emailObj.SetAddress(&attendee.EmailAddress)
-		emailObj.SetName(&attendee.Name)
-		attendeeObj.SetEmailAddress(emailObj)
-
-		// Set attendee type
-		attendeeType, err := graphmodels.ParseAttendeeType(attendee.AttendeeType)
-		if err != nil {
-			printOdataError(err)
-			errorMessage := err.(*odataerrors.ODataError).GetErrorEscaped().GetMessage()
-			return nil, errors.New(*errorMessage)
-		}
-
-		if at, ok := attendeeType.(graphmodels.AttendeeType); ok {
-			attendeeObj.SetTypeEscaped(&at)
-		}
-
-		attendees = append(attendees, attendeeObj)
+	// Set attendees, the type has to reach Graph for the stored attendee type to be right
+	attendees, err := newAttendees(requestDto.Attendees)
+	if err != nil {
+		return nil, err
 	}
 	requestBody.SetAttendees(attendees)
 
@@ -241,8 +228,7 @@ func (m *MGraph) PostCreateEvent(requestDto *requestDto.MGraphCreateEventDto) (*
 			onlineMeetingProvider, err := graphmodels.ParseOnlineMeetingProviderType(*requestDto.OnlineMeetingProvider)
 			if err != nil {
 				printOdataError(err)
-				errorMessage := err.(*odataerrors.ODataError).GetErrorEscaped().GetMessage()
-				return nil, errors.New(*errorMessage)
+				return nil, graphError(err)
 			}
 
 			if omp, ok := (onlineMeetingProvider).(*graphmodels.OnlineMeetingProviderType); ok {
@@ -251,13 +237,121 @@ func (m *MGraph) PostCreateEvent(requestDto *requestDto.MGraphCreateEventDto) (*
 		}
 	}
 
-	event, err := m.graphClient.Users().ByUserId("24dc94f1-08bf-4d47-850b-5690533b8236").Events().Post(context.Background(), requestBody, nil)
+	// Set free/busy status
+	if requestDto.ShowAs != nil {
+		showAs, err := newShowAs(*requestDto.ShowAs)
+		if err != nil {
+			return nil, err
+		}
+		requestBody.SetShowAs(showAs)
+	}
+
+	if err := setEventMetadata(requestBody, requestDto.MGraphEventMetadataDto); err != nil {
+		return nil, err
+	}
+
+	userId := DefaultUserId
+	if requestDto.UserId != nil {
+		userId = *requestDto.UserId
+	}
+
+	event, err := m.graphClient.Users().ByUserId(userId).Events().Post(context.Background(), requestBody, nil)
 	if err != nil {
 		printOdataError(err)
-		errorMessage := err.(*odataerrors.ODataError).GetErrorEscaped().GetMessage()
-		return nil, errors.New(*errorMessage)
+		return nil, graphError(err)
 	}
 	// better way to handle creation of events and sync? should we wait for delta? or just return the event?
 	log.Println(event.GetBody().GetContent())
 	return &event, nil
 }
+
+// allDayDateTimes converts the start and end of an all day event into the midnight date times Graph expects
+// -- both plain dates and midnight date times are accepted
+func allDayDateTimes(startTime string, endTime string) (string, string, error) {
+	startDate, err := parseAllDayDate(startTime)
+	if err != nil {
+		return "", "", err
+	}
+
+	endDate, err := parseAllDayDate(endTime)
+	if err != nil {
+		return "", "", err
+	}
+
+	if !endDate.After(startDate) {
+		return "", "", errors.New("end date of an all day event must be after its start date")
+	}
+
+	return startDate.Format("2006-01-02T15:04:05"), endDate.Format("2006-01-02T15:04:05"), nil
+}
+
+func parseAllDayDate(value string) (time.Time, error) {
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date, nil
+	}
+
+	dateTime, err := time.Parse("2006-01-02T15:04:05.9999999", value)
+	if err != nil {
+		return time.Time{}, errors.New("all day events need a date, got " + value)
+	}
+
+	if dateTime.Hour() != 0 || dateTime.Minute() != 0 || dateTime.Second() != 0 || dateTime.Nanosecond() != 0 {
+		return time.Time{}, errors.New("all day events must start and end at midnight, got " + value)
+	}
+
+	return dateTime, nil
+}
+
+func newShowAs(value string) (*graphmodels.FreeBusyStatus, error) {
+	showAs, err := graphmodels.ParseFreeBusyStatus(value)
+	if err != nil {
+		return nil, err
+	}
+
+	status, ok := showAs.(*graphmodels.FreeBusyStatus)
+	if !ok {
+		return nil, errors.New("unknown show_as " + value)
+	}
+
+	return status, nil
+}
+
+// setEventMetadata sets the metadata fields that were sent on the event
+func setEventMetadata(event graphmodels.Eventable, metadata requestDto.MGraphEventMetadataDto) error {
+	if metadata.Importance != nil {
+		importance, err := graphmodels.ParseImportance(*metadata.Importance)
+		if err != nil {
+			return err
+		}
+		event.SetImportance(importance.(*graphmodels.Importance))
+	}
+
+	if metadata.Sensitivity != nil {
+		sensitivity, err := graphmodels.ParseSensitivity(*metadata.Sensitivity)
+		if err != nil {
+			return err
+		}
+		event.SetSensitivity(sensitivity.(*graphmodels.Sensitivity))
+	}
+
+	if metadata.Categories != nil {
+		event.SetCategories(*metadata.Categories)
+	}
+	if metadata.IsReminderOn != nil {
+		event.SetIsReminderOn(metadata.IsReminderOn)
+	}
+	if metadata.ReminderMinutesBeforeStart != nil {
+		event.SetReminderMinutesBeforeStart(metadata.ReminderMinutesBeforeStart)
+	}
+	if metadata.ResponseRequested != nil {
+		event.SetResponseRequested(metadata.ResponseRequested)
+	}
+	if metadata.AllowNewTimeProposals != nil {
+		event.SetAllowNewTimeProposals(metadata.AllowNewTimeProposals)
+	}
+	if metadata.HideAttendees != nil {
+		event.SetHideAttendees(metadata.HideAttendees)
+	}
+
+	return nil
+}
@@ -2,10 +2,8 @@ package mgraph
 
 import (
 	"context"
-	"errors"
 
 	graphmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
-	"github.com/microsoftgraph/msgraph-sdk-go/models/odataerrors"
 	graphusers "github.com/microsoftgraph/msgraph-sdk-go/users"
 )
 
@@ -18,11 +16,10 @@ func (m *MGraph) GetCalendarView(requestStartDateTime string, requestEndDateTime
 		QueryParameters: requestParameters,
 	}
 	// Get the events
-	events, err := m.graphClient.Users().ByUserId("24dc94f1-08bf-4d47-850b-5690533b8236").Calendar().CalendarView().Get(context.Background(), configuration)
+	events, err := m.graphClient.Users().ByUserId(DefaultUserId).Calendar().CalendarView().Get(context.Background(), configuration)
 	if err != nil {
 		printOdataError(err)
-		errorMessage := err.(*odataerrors.ODataError).GetErrorEscaped().GetMessage()
-		return nil, errors.New(*errorMessage)
+		return nil, graphError(err)
 	}
 
 	return events, nil
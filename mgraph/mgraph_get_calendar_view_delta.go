@@ -2,17 +2,22 @@ package mgraph
 
 import (
 	"context"
-	"errors"
-	"log"
 
 	abstractions "github.com/microsoft/kiota-abstractions-go"
 	graphmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
-	"github.com/microsoftgraph/msgraph-sdk-go/models/odataerrors"
 	graphusers "github.com/microsoftgraph/msgraph-sdk-go/users"
 	"github.com/scheduler-prototype/dto"
 )
 
-func (m *MGraph) GetCalendarViewDelta(requestStartDateTime string, requestEndDateTime string, userDto dto.UserDto) (*string, *[]graphmodels.Eventable, error) {
+// DeltaPage is one page of a delta query with the events that arrived in it,
+// the first page is the initial request and has no link
+type DeltaPage struct {
+	Number int
+	Link   *string
+	Events []graphmodels.Eventable
+}
+
+func (m *MGraph) GetCalendarViewDelta(requestStartDateTime string, requestEndDateTime string, userDto dto.UserDto) (*string, []DeltaPage, error) {
 	// optional header config for restricting page size
 	headers := abstractions.NewRequestHeaders()
 	headers.Add("Prefer", "odata.maxpagesize=2")
@@ -31,25 +36,30 @@ func (m *MGraph) GetCalendarViewDelta(requestStartDateTime string, requestEndDat
 	delta, err := m.graphClient.Users().ByUserId(userDto.UserId.String()).CalendarView().Delta().Get(context.Background(), configuration)
 	if err != nil {
 		printOdataError(err)
-		errorMessage := err.(*odataerrors.ODataError).GetErrorEscaped().GetMessage()
-		return nil, nil, errors.New(*errorMessage)
+		return nil, nil, graphError(err)
 	}
 
 	// instantiate data store
+	pages := []DeltaPage{}
 	var eventData []graphmodels.Eventable
 	for _, event := range delta.GetValue() {
 		// check for event type, if series master, get the instance, loop and add
 		eventType := event.GetTypeEscaped()
-		if *eventType == graphmodels.OCCURRENCE_EVENTTYPE || *eventType == graphmodels.EXCEPTION_EVENTTYPE {
+		if eventType != nil && (*eventType == graphmodels.OCCURRENCE_EVENTTYPE || *eventType == graphmodels.EXCEPTION_EVENTTYPE) {
 			// skip occurrence & exception type as they only reference back to the series master
 			// will get it through series master instance below
 			continue
-		} else if *eventType == graphmodels.SERIESMASTER_EVENTTYPE {
+		}
+
+		// populate eventData
+		// -- series masters go before their instances so the instances can be linked to the series
+		eventData = append(eventData, event)
+
+		if eventType != nil && *eventType == graphmodels.SERIESMASTER_EVENTTYPE && event.GetId() != nil {
 			instances, err := m.GetEventSeriesMasterInstance(requestStartDateTime, requestEndDateTime, userDto.UserId.String(), *event.GetId())
 			if err != nil {
 				printOdataError(err)
-				errorMessage := err.(*odataerrors.ODataError).GetErrorEscaped().GetMessage()
-				return nil, nil, errors.New(*errorMessage)
+				return nil, nil, graphError(err)
 			}
 
 			// for each instance (occurence or exception) add to eventData
@@ -57,11 +67,10 @@ func (m *MGraph) GetCalendarViewDelta(requestStartDateTime string, requestEndDat
 				eventData = append(eventData, instance)
 			}
 		}
-
-		// populate eventData
-		eventData = append(eventData, event)
 	}
 
+	pages = append(pages, DeltaPage{Number: 1, Events: eventData})
+
 	// insantiate initial tokens variable
 	nextLink := delta.GetOdataNextLink()
 	deltaLink := delta.GetOdataDeltaLink()
@@ -73,41 +82,41 @@ func (m *MGraph) GetCalendarViewDelta(requestStartDateTime string, requestEndDat
 		nextPage, err := requestBuilder.Get(context.Background(), nil)
 		if err != nil {
 			printOdataError(err)
-			errorMessage := err.(*odataerrors.ODataError).GetErrorEscaped().GetMessage()
-			return nil, nil, errors.New(*errorMessage)
+			return nil, nil, graphError(err)
 		}
 
 		// populate event data
-		for _, event := range delta.GetValue() {
+		eventData = nil
+		for _, event := range nextPage.GetValue() {
 			// check for event type, if series master, get the instance, loop and add
 			eventType := event.GetTypeEscaped()
-			if *eventType == graphmodels.OCCURRENCE_EVENTTYPE || *eventType == graphmodels.EXCEPTION_EVENTTYPE {
+			if eventType != nil && (*eventType == graphmodels.OCCURRENCE_EVENTTYPE || *eventType == graphmodels.EXCEPTION_EVENTTYPE) {
 				// skip occurrence & exception type as they only reference back to the series master
 				// will get it through series master instance below
 				continue
-			} else if *eventType == graphmodels.SERIESMASTER_EVENTTYPE {
-				log.Println("-- series master --")
+			}
+
+			// populate eventData
+			// -- series masters go before their instances so the instances can be linked to the series
+			// removed events only carry their id, anything else may be missing
+			eventData = append(eventData, event)
+
+			if eventType != nil && *eventType == graphmodels.SERIESMASTER_EVENTTYPE && event.GetId() != nil {
 				instances, err := m.GetEventSeriesMasterInstance(requestStartDateTime, requestEndDateTime, userDto.UserId.String(), *event.GetId())
 				if err != nil {
 					printOdataError(err)
-					errorMessage := err.(*odataerrors.ODataError).GetErrorEscaped().GetMessage()
-					return nil, nil, errors.New(*errorMessage)
+					return nil, nil, graphError(err)
 				}
 
 				// for each instance (occurence or exception) add to eventData
 				for _, instance := range instances.GetValue() {
-					log.Println("-- instance --")
-					log.Printf("instance-subject: %v\n", *instance.GetSubject())
 					eventData = append(eventData, instance)
 				}
 			}
-
-			// populate eventData
-			log.Printf("event-type: %v\n", *event.GetTypeEscaped())
-			log.Printf("event-subject: %v\n", *event.GetSubject())
-			eventData = append(eventData, event)
 		}
 
+		pages = append(pages, DeltaPage{Number: len(pages) + 1, Link: nextLink, Events: eventData})
+
 		// insantiate tokens variable
 		newNextLink := nextPage.GetOdataNextLink()
 		newDeltaLink := nextPage.GetOdataDeltaLink()
@@ -115,11 +124,7 @@ func (m *MGraph) GetCalendarViewDelta(requestStartDateTime string, requestEndDat
 		// replace variable values with new values
 		nextLink = newNextLink
 		deltaLink = newDeltaLink
-
-		log.Printf("nextLink:  %v\n", nextLink)
-		log.Printf("deltaLink: %v\n", deltaLink)
-
 	}
 
-	return deltaLink, &eventData, nil
+	return deltaLink, pages, nil
 }
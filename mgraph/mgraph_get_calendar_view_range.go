@@ -0,0 +1,57 @@
+package mgraph
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	graphmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/microsoftgraph/msgraph-sdk-go/models/odataerrors"
+	graphusers "github.com/microsoftgraph/msgraph-sdk-go/users"
+)
+
+var ErrThrottled = errors.New("request was throttled by Microsoft Graph")
+
+// GetCalendarViewRange returns every event of the user between the given dates, following all pages
+// -- series are expanded into their occurrences and exceptions by the calendar view
+func (m *MGraph) GetCalendarViewRange(userId string, requestStartDateTime string, requestEndDateTime string) ([]graphmodels.Eventable, error) {
+	requestParameters := &graphusers.ItemCalendarViewRequestBuilderGetQueryParameters{
+		StartDateTime: &requestStartDateTime,
+		EndDateTime:   &requestEndDateTime,
+	}
+	configuration := &graphusers.ItemCalendarViewRequestBuilderGetRequestConfiguration{
+		QueryParameters: requestParameters,
+	}
+
+	events, err := m.graphClient.Users().ByUserId(userId).CalendarView().Get(context.Background(), configuration)
+	if err != nil {
+		return nil, calendarViewError(err)
+	}
+
+	eventData := events.GetValue()
+	nextLink := events.GetOdataNextLink()
+	for nextLink != nil {
+		requestBuilder := graphusers.NewItemCalendarViewRequestBuilder(*nextLink, m.adapter)
+		nextPage, err := requestBuilder.Get(context.Background(), nil)
+		if err != nil {
+			return nil, calendarViewError(err)
+		}
+
+		eventData = append(eventData, nextPage.GetValue()...)
+		nextLink = nextPage.GetOdataNextLink()
+	}
+
+	return eventData, nil
+}
+
+// calendarViewError keeps throttling recognisable so callers can back off and retry
+func calendarViewError(err error) error {
+	printOdataError(err)
+
+	var odataError *odataerrors.ODataError
+	if errors.As(err, &odataError) && (odataError.ResponseStatusCode == http.StatusTooManyRequests || odataError.ResponseStatusCode == http.StatusServiceUnavailable) {
+		return ErrThrottled
+	}
+
+	return graphError(err)
+}
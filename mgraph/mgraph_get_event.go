@@ -0,0 +1,30 @@
+package mgraph
+
+import (
+	"context"
+	"fmt"
+
+	abstractions "github.com/microsoft/kiota-abstractions-go"
+	graphmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	graphusers "github.com/microsoftgraph/msgraph-sdk-go/users"
+)
+
+// timeZone is optional, when set Graph returns start and end in that time zone instead of UTC
+func (m *MGraph) GetEvent(userId string, eventId string, timeZone *string) (graphmodels.Eventable, error) {
+	headers := abstractions.NewRequestHeaders()
+	if timeZone != nil {
+		headers.Add("Prefer", fmt.Sprintf("outlook.timezone=\"%s\"", *timeZone))
+	}
+
+	configuration := &graphusers.ItemEventsEventItemRequestBuilderGetRequestConfiguration{
+		Headers: headers,
+	}
+
+	event, err := m.graphClient.Users().ByUserId(userId).Events().ByEventId(eventId).Get(context.Background(), configuration)
+	if err != nil {
+		printOdataError(err)
+		return nil, graphError(err)
+	}
+
+	return event, nil
+}
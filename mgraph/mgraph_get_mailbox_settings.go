@@ -0,0 +1,18 @@
+package mgraph
+
+import (
+	"context"
+
+	graphmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+)
+
+func (m *MGraph) GetMailboxSettings(userId string) (graphmodels.MailboxSettingsable, error) {
+	// requires the MailboxSettings.Read application permission
+	mailboxSettings, err := m.graphClient.Users().ByUserId(userId).MailboxSettings().Get(context.Background(), nil)
+	if err != nil {
+		printOdataError(err)
+		return nil, graphError(err)
+	}
+
+	return mailboxSettings, nil
+}
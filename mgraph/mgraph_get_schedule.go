@@ -0,0 +1,93 @@
+package mgraph
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	graphmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	graphusers "github.com/microsoftgraph/msgraph-sdk-go/users"
+)
+
+// MaxScheduleAddresses is the number of addresses Graph accepts in a single getSchedule request
+const MaxScheduleAddresses = 20
+
+var ErrTooManySchedules = fmt.Errorf("at most %d addresses can be looked up at once", MaxScheduleAddresses)
+
+// GetSchedule returns the free/busy of the given email addresses as seen from the user's mailbox
+// -- answers are cached briefly per address, only the addresses missing from the cache are requested
+// -- the range is sent in UTC, Graph answers in the same zone
+func (m *MGraph) GetSchedule(userId string, schedules []string, startTime time.Time, endTime time.Time, interval int32) ([]graphmodels.ScheduleInformationable, error) {
+	if len(schedules) > MaxScheduleAddresses {
+		return nil, ErrTooManySchedules
+	}
+
+	cachedSchedules := map[string]graphmodels.ScheduleInformationable{}
+	missingSchedules := []string{}
+	for _, schedule := range schedules {
+		if cached, ok := m.scheduleCache.get(scheduleCacheKey(userId, schedule, startTime, endTime, interval)); ok {
+			cachedSchedules[strings.ToLower(schedule)] = cached
+			continue
+		}
+		missingSchedules = append(missingSchedules, schedule)
+	}
+
+	if len(missingSchedules) > 0 {
+		fetchedSchedules, err := m.postGetSchedule(userId, missingSchedules, startTime, endTime, interval)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, fetched := range fetchedSchedules {
+			if fetched.GetScheduleId() == nil {
+				continue
+			}
+			cachedSchedules[strings.ToLower(*fetched.GetScheduleId())] = fetched
+
+			// lookup errors are not cached so they are retried on the next request
+			if fetched.GetError() == nil {
+				m.scheduleCache.set(scheduleCacheKey(userId, *fetched.GetScheduleId(), startTime, endTime, interval), fetched)
+			}
+		}
+	}
+
+	// answer in the order the addresses were requested
+	scheduleInformation := []graphmodels.ScheduleInformationable{}
+	for _, schedule := range schedules {
+		if found, ok := cachedSchedules[strings.ToLower(schedule)]; ok {
+			scheduleInformation = append(scheduleInformation, found)
+		}
+	}
+
+	return scheduleInformation, nil
+}
+
+func (m *MGraph) postGetSchedule(userId string, schedules []string, startTime time.Time, endTime time.Time, interval int32) ([]graphmodels.ScheduleInformationable, error) {
+	utc := "UTC"
+
+	start := graphmodels.NewDateTimeTimeZone()
+	startDateTime := startTime.UTC().Format("2006-01-02T15:04:05")
+	start.SetDateTime(&startDateTime)
+	start.SetTimeZone(&utc)
+
+	end := graphmodels.NewDateTimeTimeZone()
+	endDateTime := endTime.UTC().Format("2006-01-02T15:04:05")
+	end.SetDateTime(&endDateTime)
+	end.SetTimeZone(&utc)
+
+	requestBody := graphusers.NewItemCalendarGetSchedulePostRequestBody()
+	requestBody.SetSchedules(schedules)
+	requestBody.SetStartTime(start)
+	requestBody.SetEndTime(end)
+	requestBody.SetAvailabilityViewInterval(&interval)
+
+	// requires the Calendars.Read application permission
+	schedule, err := m.graphClient.Users().ByUserId(userId).Calendar().GetSchedule().Post(context.Background(), requestBody, nil)
+	if err != nil {
+		printOdataError(err)
+		return nil, graphError(err)
+	}
+
+	return schedule.GetValue(), nil
+}
@@ -0,0 +1,25 @@
+package mgraph
+
+import (
+	"context"
+
+	graphmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	graphusers "github.com/microsoftgraph/msgraph-sdk-go/users"
+)
+
+func (m *MGraph) GetUser(userId string) (graphmodels.Userable, error) {
+	// requires the User.Read.All application permission
+	configuration := &graphusers.UserItemRequestBuilderGetRequestConfiguration{
+		QueryParameters: &graphusers.UserItemRequestBuilderGetQueryParameters{
+			Select: []string{"id", "displayName", "mail", "userPrincipalName"},
+		},
+	}
+
+	user, err := m.graphClient.Users().ByUserId(userId).Get(context.Background(), configuration)
+	if err != nil {
+		printOdataError(err)
+		return nil, graphError(err)
+	}
+
+	return user, nil
+}
@@ -0,0 +1,97 @@
+package mgraph
+
+import (
+	"context"
+	"errors"
+
+	graphmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	graphusers "github.com/microsoftgraph/msgraph-sdk-go/users"
+	requestDto "github.com/scheduler-prototype/dto/request"
+)
+
+var ErrProposedNewTimeNotAllowed = errors.New("proposed_new_time can only be sent with decline or tentatively accept, along with the response")
+
+// the user responds to an invitation in their own calendar, the organizer is notified unless send_response is false
+
+func (m *MGraph) AcceptEvent(requestDto *requestDto.MGraphRespondEventDto) error {
+	if requestDto.ProposedNewTime != nil {
+		return ErrProposedNewTimeNotAllowed
+	}
+
+	requestBody := graphusers.NewItemEventsItemAcceptPostRequestBody()
+	requestBody.SetComment(requestDto.Comment)
+	requestBody.SetSendResponse(requestDto.SendResponse)
+
+	err := m.graphClient.Users().ByUserId(requestDto.UserId).Events().ByEventId(requestDto.EventId).Accept().Post(context.Background(), requestBody, nil)
+	if err != nil {
+		printOdataError(err)
+		return graphError(err)
+	}
+
+	return nil
+}
+
+// DeclineEvent removes the event from the user's calendar
+func (m *MGraph) DeclineEvent(requestDto *requestDto.MGraphRespondEventDto) error {
+	requestBody := graphusers.NewItemEventsItemDeclinePostRequestBody()
+	requestBody.SetComment(requestDto.Comment)
+	requestBody.SetSendResponse(requestDto.SendResponse)
+
+	if requestDto.ProposedNewTime != nil {
+		proposedNewTime, err := newProposedNewTime(requestDto)
+		if err != nil {
+			return err
+		}
+		requestBody.SetProposedNewTime(proposedNewTime)
+	}
+
+	err := m.graphClient.Users().ByUserId(requestDto.UserId).Events().ByEventId(requestDto.EventId).Decline().Post(context.Background(), requestBody, nil)
+	if err != nil {
+		printOdataError(err)
+		return graphError(err)
+	}
+
+	return nil
+}
+
+func (m *MGraph) TentativelyAcceptEvent(requestDto *requestDto.MGraphRespondEventDto) error {
+	requestBody := graphusers.NewItemEventsItemTentativelyAcceptPostRequestBody()
+	requestBody.SetComment(requestDto.Comment)
+	requestBody.SetSendResponse(requestDto.SendResponse)
+
+	if requestDto.ProposedNewTime != nil {
+		proposedNewTime, err := newProposedNewTime(requestDto)
+		if err != nil {
+			return err
+		}
+		requestBody.SetProposedNewTime(proposedNewTime)
+	}
+
+	err := m.graphClient.Users().ByUserId(requestDto.UserId).Events().ByEventId(requestDto.EventId).TentativelyAccept().Post(context.Background(), requestBody, nil)
+	if err != nil {
+		printOdataError(err)
+		return graphError(err)
+	}
+
+	return nil
+}
+
+// Graph drops proposed times that aren't sent to the organizer
+func newProposedNewTime(requestDto *requestDto.MGraphRespondEventDto) (graphmodels.TimeSlotable, error) {
+	if requestDto.SendResponse != nil && !*requestDto.SendResponse {
+		return nil, ErrProposedNewTimeNotAllowed
+	}
+
+	start := graphmodels.NewDateTimeTimeZone()
+	start.SetDateTime(&requestDto.ProposedNewTime.StartTime)
+	start.SetTimeZone(&requestDto.ProposedNewTime.TimeZone)
+
+	end := graphmodels.NewDateTimeTimeZone()
+	end.SetDateTime(&requestDto.ProposedNewTime.EndTime)
+	end.SetTimeZone(&requestDto.ProposedNewTime.TimeZone)
+
+	timeSlot := graphmodels.NewTimeSlot()
+	timeSlot.SetStart(start)
+	timeSlot.SetEnd(end)
+	return timeSlot, nil
+}
@@ -0,0 +1,68 @@
+package mgraph
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	graphmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+)
+
+const defaultScheduleCacheTTL = 2 * time.Minute
+
+type scheduleCacheEntry struct {
+	schedule  graphmodels.ScheduleInformationable
+	expiresAt time.Time
+}
+
+// scheduleCache keeps getSchedule answers per requesting mailbox and address for a short while,
+// so repeated availability checks from the scheduling UI don't hit Graph every time
+type scheduleCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]scheduleCacheEntry
+}
+
+func newScheduleCache() *scheduleCache {
+	ttl, err := time.ParseDuration(os.Getenv("SCHEDULE_CACHE_TTL"))
+	if err != nil {
+		ttl = defaultScheduleCacheTTL
+	}
+
+	return &scheduleCache{ttl: ttl, entries: map[string]scheduleCacheEntry{}}
+}
+
+// the answer depends on what the requesting mailbox may see, so it's only reused for the same requester
+func scheduleCacheKey(userId string, schedule string, startTime time.Time, endTime time.Time, interval int32) string {
+	return fmt.Sprintf("%s|%s|%d|%d|%d", strings.ToLower(userId), strings.ToLower(schedule), startTime.Unix(), endTime.Unix(), interval)
+}
+
+func (c *scheduleCache) get(key string) (graphmodels.ScheduleInformationable, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	return entry.schedule, true
+}
+
+func (c *scheduleCache) set(key string, schedule graphmodels.ScheduleInformationable) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	// drop expired entries while we hold the lock so the map doesn't grow unbounded
+	for entryKey, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, entryKey)
+		}
+	}
+
+	c.entries[key] = scheduleCacheEntry{schedule: schedule, expiresAt: now.Add(c.ttl)}
+}
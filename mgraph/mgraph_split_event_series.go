@@ -0,0 +1,303 @@
+package mgraph
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/microsoft/kiota-abstractions-go/serialization"
+	graphmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	requestDto "github.com/scheduler-prototype/dto/request"
+	"github.com/scheduler-prototype/timezone"
+)
+
+var (
+	ErrSplitAtSeriesStart = errors.New("the first occurrence can't be split off, update the whole series instead")
+	ErrIncompleteEvent    = errors.New("Microsoft Graph returned the event without its start or end")
+)
+
+// the date time layout Graph uses in dateTimeTimeZone values
+const splitDateTimeLayout = "2006-01-02T15:04:05.0000000"
+
+// sample json request body for "this and all following" occurrences
+// {
+//     "user_id": "24dc94f1-08bf-4d47-850b-5690533b8236",
+//     "event_id": "AAMkAGI1AAAt9AHjAAA=",
+//     "pattern_type": "weekly",
+//     "pattern_interval": 2,
+//     "pattern_days_of_week": ["thursday"]
+// }
+
+// SplitEventSeries returns the truncated original series master and the newly created series master
+// -- occurrence is the occurrence the new series starts at, as the caller already fetched it
+func (m *MGraph) SplitEventSeries(requestDto *requestDto.MGraphSplitEventSeriesDto, occurrence graphmodels.Eventable) (graphmodels.Eventable, graphmodels.Eventable, error) {
+	if occurrence.GetSeriesMasterId() == nil {
+		return nil, nil, errors.New("event is not an occurrence of a recurring series")
+	}
+
+	master, err := m.GetEvent(requestDto.UserId, *occurrence.GetSeriesMasterId(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	recurrence := master.GetRecurrence()
+	if recurrence == nil || recurrence.GetPattern() == nil || recurrence.GetRangeEscaped() == nil {
+		return nil, nil, errors.New("series master has no recurrence")
+	}
+
+	occurrenceStartTime, occurrenceEndTime := occurrence.GetStart(), occurrence.GetEnd()
+	if occurrenceStartTime == nil || occurrenceStartTime.GetDateTime() == nil || occurrenceStartTime.GetTimeZone() == nil ||
+		occurrenceEndTime == nil || occurrenceEndTime.GetDateTime() == nil || occurrenceEndTime.GetTimeZone() == nil {
+		return nil, nil, ErrIncompleteEvent
+	}
+	startTime, err := timezone.ParseDateTimeTimeZone(*occurrenceStartTime.GetDateTime(), *occurrenceStartTime.GetTimeZone())
+	if err != nil {
+		return nil, nil, ErrIncompleteEvent
+	}
+	endTime, err := timezone.ParseDateTimeTimeZone(*occurrenceEndTime.GetDateTime(), *occurrenceEndTime.GetTimeZone())
+	if err != nil {
+		return nil, nil, ErrIncompleteEvent
+	}
+
+	// express the occurrence in the series time zone so the split date matches the recurrence dates
+	timeZone := "UTC"
+	location := time.UTC
+	if master.GetOriginalStartTimeZone() != nil {
+		if seriesLocation, err := timezone.LoadLocation(*master.GetOriginalStartTimeZone()); err == nil {
+			timeZone = *master.GetOriginalStartTimeZone()
+			location = seriesLocation
+		}
+	}
+	occurrenceStart := startTime.In(location).Format(splitDateTimeLayout)
+	occurrenceEnd := endTime.In(location).Format(splitDateTimeLayout)
+	splitDate, err := time.Parse("2006-01-02", occurrenceStart[:10])
+	if err != nil {
+		return nil, nil, ErrIncompleteEvent
+	}
+
+	// the original series would have to end before it starts
+	if rangeStart := recurrence.GetRangeEscaped().GetStartDate(); rangeStart != nil {
+		rangeStartDate, err := time.Parse("2006-01-02", rangeStart.String())
+		if err == nil && !splitDate.After(rangeStartDate) {
+			return nil, nil, ErrSplitAtSeriesStart
+		}
+	}
+
+	// build the new series starting on the occurrence date
+	newMaster := graphmodels.NewEvent()
+
+	subject := master.GetSubject()
+	if requestDto.Subject != nil {
+		subject = requestDto.Subject
+	}
+	newMaster.SetSubject(subject)
+
+	contentBody := graphmodels.NewItemBody()
+	contentBodyType := graphmodels.HTML_BODYTYPE
+	contentBody.SetContentType(&contentBodyType)
+	if requestDto.Content != nil {
+		contentBody.SetContent(requestDto.Content)
+	} else if master.GetBody() != nil {
+		contentBody.SetContent(master.GetBody().GetContent())
+	}
+	newMaster.SetBody(contentBody)
+
+	if requestDto.TimeZone != nil {
+		timeZone = *requestDto.TimeZone
+	}
+	if requestDto.StartTime != nil {
+		occurrenceStart = *requestDto.StartTime
+	}
+	if requestDto.EndTime != nil {
+		occurrenceEnd = *requestDto.EndTime
+	}
+
+	start := graphmodels.NewDateTimeTimeZone()
+	start.SetDateTime(&occurrenceStart)
+	start.SetTimeZone(&timeZone)
+	newMaster.SetStart(start)
+
+	end := graphmodels.NewDateTimeTimeZone()
+	end.SetDateTime(&occurrenceEnd)
+	end.SetTimeZone(&timeZone)
+	newMaster.SetEnd(end)
+
+	attendees := []graphmodels.Attendeeable{}
+	for _, attendee := range master.GetAttendees() {
+		if attendee.GetEmailAddress() == nil {
+			continue
+		}
+
+		attendeeObj := graphmodels.NewAttendee()
+		emailObj := graphmodels.NewEmailAddress()
+		emailObj.SetAddress(attendee.GetEmailAddress().GetAddress())
+		emailObj.SetName(attendee.GetEmailAddress().GetName())
+		attendeeObj.SetEmailAddress(emailObj)
+		attendeeObj.SetTypeEscaped(attendee.GetTypeEscaped())
+		attendees = append(attendees, attendeeObj)
+	}
+	newMaster.SetAttendees(attendees)
+
+	locations := []graphmodels.Locationable{}
+	for _, location := range master.GetLocations() {
+		locationObj := graphmodels.NewLocation()
+		locationObj.SetDisplayName(location.GetDisplayName())
+		locationObj.SetLocationUri(location.GetLocationUri())
+		if location.GetAddress() != nil {
+			addressObj := graphmodels.NewPhysicalAddress()
+			addressObj.SetStreet(location.GetAddress().GetStreet())
+			addressObj.SetCity(location.GetAddress().GetCity())
+			addressObj.SetState(location.GetAddress().GetState())
+			addressObj.SetCountryOrRegion(location.GetAddress().GetCountryOrRegion())
+			addressObj.SetPostalCode(location.GetAddress().GetPostalCode())
+			locationObj.SetAddress(addressObj)
+		}
+		locations = append(locations, locationObj)
+	}
+	newMaster.SetLocations(locations)
+
+	if master.GetIsOnlineMeeting() != nil && *master.GetIsOnlineMeeting() {
+		newMaster.SetIsOnlineMeeting(master.GetIsOnlineMeeting())
+		newMaster.SetOnlineMeetingProvider(master.GetOnlineMeetingProvider())
+	}
+
+	newPattern, err := newSplitRecurrencePattern(recurrence.GetPattern(), requestDto)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	newRange, err := newSplitRecurrenceRange(recurrence.GetRangeEscaped(), splitDate, requestDto)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	newRecurrence := graphmodels.NewPatternedRecurrence()
+	newRecurrence.SetPattern(newPattern)
+	newRecurrence.SetRangeEscaped(newRange)
+	newMaster.SetRecurrence(newRecurrence)
+
+	createdMaster, err := m.graphClient.Users().ByUserId(requestDto.UserId).Events().Post(context.Background(), newMaster, nil)
+	if err != nil {
+		printOdataError(err)
+		return nil, nil, graphError(err)
+	}
+
+	// truncate the original series so it ends the day before the split
+	// -- Graph expects the full recurrence when patching it, so the original pattern is sent back as is
+	truncatedRange := graphmodels.NewRecurrenceRange()
+	endDateRangeType := graphmodels.ENDDATE_RECURRENCERANGETYPE
+	truncatedRange.SetTypeEscaped(&endDateRangeType)
+	truncatedRange.SetStartDate(recurrence.GetRangeEscaped().GetStartDate())
+	truncatedRange.SetEndDate(serialization.NewDateOnly(splitDate.AddDate(0, 0, -1)))
+	truncatedRange.SetRecurrenceTimeZone(recurrence.GetRangeEscaped().GetRecurrenceTimeZone())
+
+	truncatedRecurrence := graphmodels.NewPatternedRecurrence()
+	truncatedRecurrence.SetPattern(copyRecurrencePattern(recurrence.GetPattern()))
+	truncatedRecurrence.SetRangeEscaped(truncatedRange)
+
+	requestBody := graphmodels.NewEvent()
+	requestBody.SetRecurrence(truncatedRecurrence)
+
+	truncatedMaster, err := m.graphClient.Users().ByUserId(requestDto.UserId).Events().ByEventId(*master.GetId()).Patch(context.Background(), requestBody, nil)
+	if err != nil {
+		printOdataError(err)
+
+		// remove the new series so the occurrences are not duplicated
+		if deleteErr := m.DeleteEvent(requestDto.UserId, *createdMaster.GetId()); deleteErr != nil {
+			printOdataError(deleteErr)
+		}
+
+		return nil, nil, graphError(err)
+	}
+
+	return truncatedMaster, createdMaster, nil
+}
+
+func copyRecurrencePattern(pattern graphmodels.RecurrencePatternable) graphmodels.RecurrencePatternable {
+	patternObj := graphmodels.NewRecurrencePattern()
+	patternObj.SetTypeEscaped(pattern.GetTypeEscaped())
+	patternObj.SetInterval(pattern.GetInterval())
+	patternObj.SetDaysOfWeek(pattern.GetDaysOfWeek())
+	patternObj.SetDayOfMonth(pattern.GetDayOfMonth())
+	patternObj.SetMonth(pattern.GetMonth())
+	patternObj.SetIndex(pattern.GetIndex())
+	patternObj.SetFirstDayOfWeek(pattern.GetFirstDayOfWeek())
+
+	return patternObj
+}
+
+func newSplitRecurrencePattern(pattern graphmodels.RecurrencePatternable, requestDto *requestDto.MGraphSplitEventSeriesDto) (graphmodels.RecurrencePatternable, error) {
+	patternObj := copyRecurrencePattern(pattern)
+
+	// -- set pattern type
+	if requestDto.PatternType != nil {
+		patternType, err := graphmodels.ParseRecurrencePatternType(*requestDto.PatternType)
+		if err != nil {
+			return nil, err
+		}
+
+		if pt, ok := (patternType).(*graphmodels.RecurrencePatternType); ok {
+			patternObj.SetTypeEscaped(pt)
+		}
+	}
+
+	// -- set pattern interval
+	if requestDto.PatternInterval != nil {
+		patternObj.SetInterval(requestDto.PatternInterval)
+	}
+
+	// -- set pattern for days of week
+	if requestDto.PatternDaysOfWeek != nil {
+		patternDaysOfWeek := []graphmodels.DayOfWeek{}
+		for _, dayOfWeek := range *requestDto.PatternDaysOfWeek {
+			dayOfWeek, err := graphmodels.ParseDayOfWeek(dayOfWeek)
+			if err != nil {
+				return nil, err
+			}
+
+			if dow, ok := (dayOfWeek).(*graphmodels.DayOfWeek); ok {
+				patternDaysOfWeek = append(patternDaysOfWeek, *dow)
+			}
+		}
+		patternObj.SetDaysOfWeek(patternDaysOfWeek)
+	}
+
+	return patternObj, nil
+}
+
+func newSplitRecurrenceRange(recurrenceRange graphmodels.RecurrenceRangeable, splitDate time.Time, requestDto *requestDto.MGraphSplitEventSeriesDto) (graphmodels.RecurrenceRangeable, error) {
+	rangeObj := graphmodels.NewRecurrenceRange()
+	rangeObj.SetStartDate(serialization.NewDateOnly(splitDate))
+	rangeObj.SetRecurrenceTimeZone(recurrenceRange.GetRecurrenceTimeZone())
+
+	rangeType := recurrenceRange.GetTypeEscaped()
+	if requestDto.RecurrenceType != nil {
+		parsedRangeType, err := graphmodels.ParseRecurrenceRangeType(*requestDto.RecurrenceType)
+		if err != nil {
+			return nil, err
+		}
+
+		if rt, ok := (parsedRangeType).(*graphmodels.RecurrenceRangeType); ok {
+			rangeType = rt
+		}
+	}
+	rangeObj.SetTypeEscaped(rangeType)
+
+	switch *rangeType {
+	case graphmodels.ENDDATE_RECURRENCERANGETYPE:
+		endDate := recurrenceRange.GetEndDate()
+		if requestDto.RecurrenceEnd != nil {
+			parsedEndDate, err := serialization.ParseDateOnly(*requestDto.RecurrenceEnd)
+			if err != nil {
+				return nil, err
+			}
+			endDate = parsedEndDate
+		}
+		rangeObj.SetEndDate(endDate)
+	case graphmodels.NUMBERED_RECURRENCERANGETYPE:
+		// the remaining number of occurrences can't be derived from the original range
+		return nil, errors.New("a numbered series can only be split into an endDate or noEnd series")
+	}
+
+	return rangeObj, nil
+}
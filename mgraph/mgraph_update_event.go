@@ -0,0 +1,172 @@
+package mgraph
+
+import (
+	"context"
+	"errors"
+
+	graphmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	requestDto "github.com/scheduler-prototype/dto/request"
+)
+
+// sample json request body for updating a single occurrence of a series
+// {
+//     "user_id": "24dc94f1-08bf-4d47-850b-5690533b8236",
+//     "event_id": "AAMkAGI1AAAt9AHjAAA=",
+//     "subject": "moved occurrence",
+//     "start_time": "2023-08-30T03:00:00",
+//     "end_time": "2023-08-30T04:00:00",
+//     "time_zone": "UTC"
+// }
+
+func (m *MGraph) PatchUpdateEvent(requestDto *requestDto.MGraphUpdateEventDto) (graphmodels.Eventable, error) {
+	// only set the fields that were sent, anything else stays untouched in Graph
+	requestBody := graphmodels.NewEvent()
+
+	if requestDto.Subject != nil {
+		requestBody.SetSubject(requestDto.Subject)
+	}
+
+	if requestDto.Content != nil {
+		contentBody := graphmodels.NewItemBody()
+		contentBodyType := graphmodels.HTML_BODYTYPE
+		contentBody.SetContentType(&contentBodyType)
+		contentBody.SetContent(requestDto.Content)
+		requestBody.SetBody(contentBody)
+	}
+
+	// start and end have to be sent together with their time zone
+	if requestDto.StartTime != nil || requestDto.EndTime != nil {
+		if requestDto.StartTime == nil || requestDto.EndTime == nil || requestDto.TimeZone == nil {
+			return nil, errors.New("start_time, end_time and time_zone must be set together")
+		}
+
+		startTime := *requestDto.StartTime
+		endTime := *requestDto.EndTime
+		if requestDto.IsAllDay != nil && *requestDto.IsAllDay {
+			var err error
+			startTime, endTime, err = allDayDateTimes(startTime, endTime)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		start := graphmodels.NewDateTimeTimeZone()
+		start.SetDateTime(&startTime)
+		start.SetTimeZone(requestDto.TimeZone)
+		requestBody.SetStart(start)
+
+		end := graphmodels.NewDateTimeTimeZone()
+		end.SetDateTime(&endTime)
+		end.SetTimeZone(requestDto.TimeZone)
+		requestBody.SetEnd(end)
+	}
+
+	if requestDto.IsAllDay != nil {
+		requestBody.SetIsAllDay(requestDto.IsAllDay)
+	}
+
+	if requestDto.Attendees != nil {
+		attendees, err := newAttendees(*requestDto.Attendees)
+		if err != nil {
+			return nil, err
+		}
+		requestBody.SetAttendees(attendees)
+	}
+
+	if requestDto.Locations != nil {
+		locations := []graphmodels.Locationable{}
+		for _, location := range *requestDto.Locations {
+			locations = append(locations, newLocation(location))
+		}
+		requestBody.SetLocations(locations)
+	}
+
+	if requestDto.IsOnlineMeeting != nil {
+		requestBody.SetIsOnlineMeeting(requestDto.IsOnlineMeeting)
+		if *requestDto.IsOnlineMeeting && requestDto.OnlineMeetingProvider != nil {
+			onlineMeetingProvider, err := graphmodels.ParseOnlineMeetingProviderType(*requestDto.OnlineMeetingProvider)
+			if err != nil {
+				return nil, err
+			}
+
+			if omp, ok := (onlineMeetingProvider).(*graphmodels.OnlineMeetingProviderType); ok {
+				requestBody.SetOnlineMeetingProvider(omp)
+			}
+		}
+	}
+
+	if requestDto.ShowAs != nil {
+		showAs, err := newShowAs(*requestDto.ShowAs)
+		if err != nil {
+			return nil, err
+		}
+		requestBody.SetShowAs(showAs)
+	}
+
+	if err := setEventMetadata(requestBody, requestDto.MGraphEventMetadataDto); err != nil {
+		return nil, err
+	}
+
+	event, err := m.graphClient.Users().ByUserId(requestDto.UserId).Events().ByEventId(requestDto.EventId).Patch(context.Background(), requestBody, nil)
+	if err != nil {
+		printOdataError(err)
+		return nil, graphError(err)
+	}
+
+	return event, nil
+}
+
+func newAttendees(attendeeDtos []requestDto.MGraphCreateEventAttendeeDto) ([]graphmodels.Attendeeable, error) {
+	attendees := []graphmodels.Attendeeable{}
+	for _, attendee := range attendeeDtos {
+		attendeeObj := graphmodels.NewAttendee()
+
+		// Set attendee email information
+		emailAddress := attendee.EmailAddress
+		name := attendee.Name
+		emailObj := graphmodels.NewEmailAddress()
+		emailObj.SetAddress(&emailAddress)
+		emailObj.SetName(&name)
+		attendeeObj.SetEmailAddress(emailObj)
+
+		// Set attendee type
+		attendeeType, err := graphmodels.ParseAttendeeType(attendee.AttendeeType)
+		if err != nil {
+			return nil, err
+		}
+
+		if at, ok := attendeeType.(*graphmodels.AttendeeType); ok {
+			attendeeObj.SetTypeEscaped(at)
+		}
+
+		attendees = append(attendees, attendeeObj)
+	}
+
+	return attendees, nil
+}
+
+func newLocation(location requestDto.MGraphCreateEventLocationDto) graphmodels.Locationable {
+	displayName := location.DisplayName
+	locationObj := graphmodels.NewLocation()
+	locationObj.SetDisplayName(&displayName)
+
+	// set address if there is one
+	if location.Address != nil {
+		address := *location.Address
+		addressObj := graphmodels.NewPhysicalAddress()
+		addressObj.SetStreet(&address.Street)
+		addressObj.SetCity(&address.City)
+		addressObj.SetState(&address.State)
+		addressObj.SetCountryOrRegion(&address.Country)
+		addressObj.SetPostalCode(&address.PostalCode)
+		locationObj.SetAddress(addressObj)
+	}
+
+	// check for default location
+	if location.DefaultLocation {
+		defaultLocation := graphmodels.DEFAULTESCAPED_LOCATIONTYPE
+		locationObj.SetLocationType(&defaultLocation)
+	}
+
+	return locationObj
+}
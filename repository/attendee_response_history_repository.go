@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/scheduler-prototype/dto"
+)
+
+func createAttendeeResponseHistory(tx *sql.Tx, history *dto.AttendeeResponseHistoryDto) error {
+	query := `
+				INSERT INTO attendee_response_history
+					(ical_uid, email_address, response_status, response_time, proposed_start_time, proposed_end_time, source, recorded_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+				RETURNING id
+			 `
+
+	return tx.QueryRow(
+		query,
+		history.ICalUid,
+		history.EmailAddress,
+		history.ResponseStatus,
+		history.ResponseTime,
+		history.ProposedStartTime,
+		history.ProposedEndTime,
+		history.Source,
+		history.RecordedAt,
+	).Scan(&history.ID)
+}
+
+// CreateAttendeeResponseHistory adds a response change of an attendee that isn't stored in attendees
+func (r *Repository) CreateAttendeeResponseHistory(history *dto.AttendeeResponseHistoryDto) error {
+	tx, err := r.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := createAttendeeResponseHistory(tx, history); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetAttendeeResponseHistoryByICalUid returns the response changes of the meeting, oldest first
+func (r *Repository) GetAttendeeResponseHistoryByICalUid(iCalUid string) ([]dto.AttendeeResponseHistoryDto, error) {
+	query := `
+				SELECT * FROM attendee_response_history WHERE ical_uid = $1 ORDER BY recorded_at
+			 `
+
+	rows, err := r.conn.Query(query, iCalUid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var histories []dto.AttendeeResponseHistoryDto
+	for rows.Next() {
+		var history dto.AttendeeResponseHistoryDto
+		if err := rows.Scan(
+			&history.ID,
+			&history.ICalUid,
+			&history.EmailAddress,
+			&history.ResponseStatus,
+			&history.ResponseTime,
+			&history.ProposedStartTime,
+			&history.ProposedEndTime,
+			&history.Source,
+			&history.RecordedAt,
+		); err != nil {
+			return nil, err
+		}
+		histories = append(histories, history)
+	}
+	return histories, nil
+}
@@ -18,11 +18,16 @@ func (r *Repository) fetchAttendees(query string, args ...interface{}) ([]dto.MG
 		if err := rows.Scan(
 			&attendee.ID,
 			&attendee.UserId,
+			&attendee.ICalUid,
 			&attendee.Name,
 			&attendee.EmailAddress,
-			&attendee.ICalUid,
 			&attendee.CreatedAt,
 			&attendee.UpdatedAt,
+			&attendee.ResponseStatus,
+			&attendee.ResponseTime,
+			&attendee.AttendeeType,
+			&attendee.ProposedStartTime,
+			&attendee.ProposedEndTime,
 		); err != nil {
 			return nil, err
 		}
@@ -34,8 +39,9 @@ func (r *Repository) fetchAttendees(query string, args ...interface{}) ([]dto.MG
 func (r *Repository) CreateAttendee(attendee *dto.MGraphAttendeeDto) error {
 	query := `
 				INSERT INTO attendees 
-					(user_id, name, email_address, ical_uid, created_at, updated_at)
-				VALUES ($1, $2, $3, $4, $5, $6) 
+					(user_id, name, email_address, ical_uid, created_at, updated_at,
+					response_status, response_time, attendee_type, proposed_start_time, proposed_end_time)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) 
 				RETURNING id
 			 `
 
@@ -47,6 +53,11 @@ func (r *Repository) CreateAttendee(attendee *dto.MGraphAttendeeDto) error {
 		attendee.ICalUid,
 		attendee.CreatedAt,
 		attendee.UpdatedAt,
+		attendee.ResponseStatus,
+		attendee.ResponseTime,
+		attendee.AttendeeType,
+		attendee.ProposedStartTime,
+		attendee.ProposedEndTime,
 	).Scan(&attendee.ID); err != nil {
 		return err
 	}
@@ -71,3 +82,61 @@ func (r *Repository) GetAttendeeByICalUidAndEmailAddress(iCalUid string, emailAd
 	return attendees[0], nil
 }
 
+func (r *Repository) GetAttendeesByICalUid(iCalUid string) ([]dto.MGraphAttendeeDto, error) {
+	query := `
+				SELECT * FROM attendees WHERE ical_uid = $1
+			 `
+
+	return r.fetchAttendees(query, iCalUid)
+}
+
+func (r *Repository) DeleteAttendeesByICalUid(iCalUid string) error {
+	query := `
+				DELETE FROM attendees WHERE ical_uid = $1
+			 `
+
+	if _, err := r.conn.Exec(query, iCalUid); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateAttendee refreshes the attendee's type and response,
+// the change is added to the response history in the same transaction when history is given
+func (r *Repository) UpdateAttendee(attendee *dto.MGraphAttendeeDto, history *dto.AttendeeResponseHistoryDto) error {
+	tx, err := r.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+				UPDATE attendees SET 
+					name = $2, attendee_type = $3, response_status = $4, response_time = $5,
+					proposed_start_time = $6, proposed_end_time = $7, updated_at = $8
+				WHERE id = $1
+			 `
+
+	if _, err := tx.Exec(
+		query,
+		attendee.ID,
+		attendee.Name,
+		attendee.AttendeeType,
+		attendee.ResponseStatus,
+		attendee.ResponseTime,
+		attendee.ProposedStartTime,
+		attendee.ProposedEndTime,
+		attendee.UpdatedAt,
+	); err != nil {
+		return err
+	}
+
+	if history != nil {
+		if err := createAttendeeResponseHistory(tx, history); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
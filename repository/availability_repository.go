@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/scheduler-prototype/dto"
+	"github.com/scheduler-prototype/utility"
+)
+
+func (r *Repository) fetchAvailabilityRules(query string, args ...interface{}) ([]dto.AvailabilityRulesDto, error) {
+	rows, err := r.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var availabilityRules []dto.AvailabilityRulesDto
+	for rows.Next() {
+		var rules dto.AvailabilityRulesDto
+		if err := rows.Scan(
+			&rules.ID,
+			&rules.UserId,
+			&rules.TimeZone,
+			&rules.BufferBeforeMinutes,
+			&rules.BufferAfterMinutes,
+			&rules.MinimumNoticeMinutes,
+			&rules.CreatedAt,
+			&rules.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		availabilityRules = append(availabilityRules, rules)
+	}
+	return availabilityRules, nil
+}
+
+func (r *Repository) fetchAvailabilityWeeklyHours(query string, args ...interface{}) ([]dto.AvailabilityWeeklyHoursDto, error) {
+	rows, err := r.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var weeklyHours []dto.AvailabilityWeeklyHoursDto
+	for rows.Next() {
+		var hours dto.AvailabilityWeeklyHoursDto
+		if err := rows.Scan(
+			&hours.ID,
+			&hours.UserId,
+			&hours.DayOfWeek,
+			&hours.StartTime,
+			&hours.EndTime,
+			&hours.CreatedAt,
+			&hours.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		weeklyHours = append(weeklyHours, hours)
+	}
+	return weeklyHours, nil
+}
+
+func (r *Repository) fetchAvailabilityOverrides(query string, args ...interface{}) ([]dto.AvailabilityOverrideDto, error) {
+	rows, err := r.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var overrides []dto.AvailabilityOverrideDto
+	for rows.Next() {
+		var override dto.AvailabilityOverrideDto
+		if err := rows.Scan(
+			&override.ID,
+			&override.UserId,
+			&override.Date,
+			&override.StartTime,
+			&override.EndTime,
+			&override.IsHoliday,
+			&override.Name,
+			&override.CreatedAt,
+			&override.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		overrides = append(overrides, override)
+	}
+	return overrides, nil
+}
+
+func (r *Repository) GetAvailabilityRulesByUserId(userId *uuid.UUID) (dto.AvailabilityRulesDto, error) {
+	query := `
+				SELECT * FROM availability_rules WHERE user_id = $1
+			 `
+
+	availabilityRules, err := r.fetchAvailabilityRules(query, userId)
+	if err != nil {
+		return dto.AvailabilityRulesDto{}, err
+	}
+
+	if len(availabilityRules) == 0 {
+		return dto.AvailabilityRulesDto{}, utility.ErrNotFound
+	}
+
+	return availabilityRules[0], nil
+}
+
+func (r *Repository) UpsertAvailabilityRules(rules *dto.AvailabilityRulesDto) error {
+	query := `
+				INSERT INTO availability_rules
+					(user_id, time_zone, buffer_before_minutes, buffer_after_minutes, minimum_notice_minutes, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)
+				ON CONFLICT (user_id) DO UPDATE SET
+					time_zone = EXCLUDED.time_zone, buffer_before_minutes = EXCLUDED.buffer_before_minutes,
+					buffer_after_minutes = EXCLUDED.buffer_after_minutes, minimum_notice_minutes = EXCLUDED.minimum_notice_minutes,
+					updated_at = EXCLUDED.updated_at
+				RETURNING id, created_at
+			 `
+
+	if err := r.conn.QueryRow(
+		query,
+		rules.UserId,
+		rules.TimeZone,
+		rules.BufferBeforeMinutes,
+		rules.BufferAfterMinutes,
+		rules.MinimumNoticeMinutes,
+		rules.CreatedAt,
+		rules.UpdatedAt,
+	).Scan(&rules.ID, &rules.CreatedAt); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) GetAvailabilityWeeklyHoursByUserId(userId *uuid.UUID) ([]dto.AvailabilityWeeklyHoursDto, error) {
+	query := `
+				SELECT * FROM availability_weekly_hours WHERE user_id = $1 ORDER BY day_of_week, start_time
+			 `
+
+	return r.fetchAvailabilityWeeklyHours(query, userId)
+}
+
+// ReplaceAvailabilityWeeklyHours swaps the user's weekly hours for the given ones in a single transaction
+func (r *Repository) ReplaceAvailabilityWeeklyHours(userId *uuid.UUID, weeklyHours []dto.AvailabilityWeeklyHoursDto) error {
+	tx, err := r.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM availability_weekly_hours WHERE user_id = $1`, userId); err != nil {
+		return err
+	}
+
+	query := `
+				INSERT INTO availability_weekly_hours
+					(user_id, day_of_week, start_time, end_time, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				RETURNING id
+			 `
+
+	for i := range weeklyHours {
+		hours := &weeklyHours[i]
+		if err := tx.QueryRow(
+			query,
+			userId,
+			hours.DayOfWeek,
+			hours.StartTime,
+			hours.EndTime,
+			hours.CreatedAt,
+			hours.UpdatedAt,
+		).Scan(&hours.ID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetAvailabilityOverridesByUserIdAndDateRange returns the overrides dated from startDate to endDate, both included
+func (r *Repository) GetAvailabilityOverridesByUserIdAndDateRange(userId *uuid.UUID, startDate time.Time, endDate time.Time) ([]dto.AvailabilityOverrideDto, error) {
+	query := `
+				SELECT * FROM availability_overrides WHERE user_id = $1 AND date >= $2 AND date <= $3 ORDER BY date, start_time
+			 `
+
+	return r.fetchAvailabilityOverrides(query, userId, startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+}
+
+func (r *Repository) GetAvailabilityOverridesByUserId(userId *uuid.UUID) ([]dto.AvailabilityOverrideDto, error) {
+	query := `
+				SELECT * FROM availability_overrides WHERE user_id = $1 ORDER BY date, start_time
+			 `
+
+	return r.fetchAvailabilityOverrides(query, userId)
+}
+
+func (r *Repository) CreateAvailabilityOverride(override *dto.AvailabilityOverrideDto) error {
+	query := `
+				INSERT INTO availability_overrides
+					(user_id, date, start_time, end_time, is_holiday, name, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+				RETURNING id
+			 `
+
+	if err := r.conn.QueryRow(
+		query,
+		override.UserId,
+		override.Date.Format("2006-01-02"),
+		override.StartTime,
+		override.EndTime,
+		override.IsHoliday,
+		override.Name,
+		override.CreatedAt,
+		override.UpdatedAt,
+	).Scan(&override.ID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) DeleteAvailabilityOverride(userId *uuid.UUID, overrideId *uuid.UUID) error {
+	query := `
+				DELETE FROM availability_overrides WHERE user_id = $1 AND id = $2
+			 `
+
+	result, err := r.conn.Exec(query, userId, overrideId)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if deleted == 0 {
+		return utility.ErrNotFound
+	}
+
+	return nil
+}
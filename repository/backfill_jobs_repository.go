@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/scheduler-prototype/dto"
+	"github.com/scheduler-prototype/utility"
+)
+
+func (r *Repository) fetchBackfillJobs(query string, args ...interface{}) ([]dto.BackfillJobDto, error) {
+	rows, err := r.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var backfillJobs []dto.BackfillJobDto
+	for rows.Next() {
+		var backfillJob dto.BackfillJobDto
+		if err := rows.Scan(
+			&backfillJob.ID,
+			&backfillJob.UserId,
+			&backfillJob.ChunkUnit,
+			&backfillJob.ChunkSize,
+			&backfillJob.BackfillUntil,
+			&backfillJob.Cursor,
+			&backfillJob.Status,
+			&backfillJob.EventsSynced,
+			&backfillJob.LastError,
+			&backfillJob.CreatedAt,
+			&backfillJob.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		backfillJobs = append(backfillJobs, backfillJob)
+	}
+	return backfillJobs, nil
+}
+
+func (r *Repository) CreateBackfillJob(backfillJob *dto.BackfillJobDto) error {
+	query := `
+				INSERT INTO backfill_jobs
+					(user_id, chunk_unit, chunk_size, backfill_until, cursor, status, events_synced, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+				RETURNING id
+			 `
+
+	if err := r.conn.QueryRow(
+		query,
+		backfillJob.UserId,
+		backfillJob.ChunkUnit,
+		backfillJob.ChunkSize,
+		backfillJob.BackfillUntil,
+		backfillJob.Cursor,
+		backfillJob.Status,
+		backfillJob.EventsSynced,
+		backfillJob.CreatedAt,
+		backfillJob.UpdatedAt,
+	).Scan(&backfillJob.ID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) GetLatestBackfillJobByUserId(userId *uuid.UUID) (dto.BackfillJobDto, error) {
+	query := `
+				SELECT * FROM backfill_jobs WHERE user_id = $1 ORDER BY created_at DESC LIMIT 1
+			 `
+
+	backfillJobs, err := r.fetchBackfillJobs(query, userId)
+	if err != nil {
+		return dto.BackfillJobDto{}, err
+	}
+
+	if len(backfillJobs) == 0 {
+		return dto.BackfillJobDto{}, utility.ErrNotFound
+	}
+
+	return backfillJobs[0], nil
+}
+
+func (r *Repository) UpdateBackfillJobProgress(backfillJob *dto.BackfillJobDto) error {
+	query := `
+				UPDATE backfill_jobs SET cursor = $2, status = $3, events_synced = $4, last_error = $5, updated_at = $6
+				WHERE id = $1
+			 `
+
+	if _, err := r.conn.Exec(
+		query,
+		backfillJob.ID,
+		backfillJob.Cursor,
+		backfillJob.Status,
+		backfillJob.EventsSynced,
+		backfillJob.LastError,
+		backfillJob.UpdatedAt,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/scheduler-prototype/dto"
+)
+
+func createBookingAssignments(tx *sql.Tx, assignments []dto.BookingAssignmentDto) error {
+	query := `
+				INSERT INTO booking_assignments
+					(booking_id, booking_type_id, user_id, scheduling_mode, event_count, assigned_at)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				RETURNING id
+			 `
+
+	for i := range assignments {
+		assignment := &assignments[i]
+		if err := tx.QueryRow(
+			query,
+			assignment.BookingId,
+			assignment.BookingTypeId,
+			assignment.UserId,
+			assignment.SchedulingMode,
+			assignment.EventCount,
+			assignment.AssignedAt,
+		).Scan(&assignment.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Repository) GetBookingAssignmentsByBookingTypeId(bookingTypeId *uuid.UUID) ([]dto.BookingAssignmentDto, error) {
+	query := `
+				SELECT * FROM booking_assignments WHERE booking_type_id = $1 ORDER BY assigned_at DESC
+			 `
+
+	rows, err := r.conn.Query(query, bookingTypeId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var assignments []dto.BookingAssignmentDto
+	for rows.Next() {
+		var assignment dto.BookingAssignmentDto
+		if err := rows.Scan(
+			&assignment.ID,
+			&assignment.BookingId,
+			&assignment.BookingTypeId,
+			&assignment.UserId,
+			&assignment.SchedulingMode,
+			&assignment.EventCount,
+			&assignment.AssignedAt,
+		); err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, assignment)
+	}
+	return assignments, nil
+}
+
+// GetBookingAssignmentStatsByBookingTypeId returns the stats of the members that were assigned at least once
+func (r *Repository) GetBookingAssignmentStatsByBookingTypeId(bookingTypeId *uuid.UUID) (map[uuid.UUID]dto.BookingAssignmentStatsDto, error) {
+	query := `
+				SELECT user_id, COUNT(*), MAX(assigned_at) FROM booking_assignments
+				WHERE booking_type_id = $1
+				GROUP BY user_id
+			 `
+
+	rows, err := r.conn.Query(query, bookingTypeId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := map[uuid.UUID]dto.BookingAssignmentStatsDto{}
+	for rows.Next() {
+		var stat dto.BookingAssignmentStatsDto
+		if err := rows.Scan(&stat.UserId, &stat.AssignmentCount, &stat.LastAssignedAt); err != nil {
+			return nil, err
+		}
+		stats[stat.UserId] = stat
+	}
+	return stats, nil
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// LockBookingMembers takes a postgres advisory lock on each member so no other process books them between
+// the slot check and the event creation, the locks are held on one connection until unlock is called
+func (r *Repository) LockBookingMembers(memberIds []uuid.UUID) (func(), error) {
+	conn, err := r.conn.Conn(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	// always lock in the same order so two bookings sharing members can't deadlock
+	keys := []string{}
+	for _, memberId := range memberIds {
+		keys = append(keys, "booking:"+memberId.String())
+	}
+	sort.Strings(keys)
+
+	unlock := func() {
+		_, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock_all()`)
+		if err != nil {
+			// the locks go away with the session, never hand the connection back to the pool holding them
+			conn.Raw(func(driverConn interface{}) error { return driver.ErrBadConn })
+		}
+		conn.Close()
+	}
+
+	for _, key := range keys {
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_lock(hashtext($1))`, key); err != nil {
+			unlock()
+			return nil, err
+		}
+	}
+
+	return unlock, nil
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/scheduler-prototype/dto"
+	"github.com/scheduler-prototype/utility"
+)
+
+func (r *Repository) fetchBookingTypes(query string, args ...interface{}) ([]dto.BookingTypeDto, error) {
+	rows, err := r.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bookingTypes []dto.BookingTypeDto
+	for rows.Next() {
+		var bookingType dto.BookingTypeDto
+		var questions []byte
+		if err := rows.Scan(
+			&bookingType.ID,
+			&bookingType.UserId,
+			&bookingType.Slug,
+			&bookingType.Title,
+			&bookingType.Description,
+			&bookingType.DurationMinutes,
+			&bookingType.SlotIntervalMinutes,
+			&bookingType.BookingWindowDays,
+			&bookingType.BufferBeforeMinutes,
+			&bookingType.BufferAfterMinutes,
+			&bookingType.MinimumNoticeMinutes,
+			&bookingType.LocationDisplayName,
+			&bookingType.IsOnlineMeeting,
+			&bookingType.OnlineMeetingProvider,
+			&questions,
+			&bookingType.IsActive,
+			&bookingType.CreatedAt,
+			&bookingType.UpdatedAt,
+			&bookingType.SchedulingMode,
+		); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(questions, &bookingType.Questions); err != nil {
+			return nil, err
+		}
+		bookingTypes = append(bookingTypes, bookingType)
+	}
+	return bookingTypes, nil
+}
+
+// CreateBookingType creates the booking type along with its members in a single transaction
+func (r *Repository) CreateBookingType(bookingType *dto.BookingTypeDto, members []dto.BookingTypeMemberDto) error {
+	questions, err := json.Marshal(bookingType.Questions)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+				INSERT INTO booking_types
+					(user_id, slug, title, description, duration_minutes, slot_interval_minutes, booking_window_days,
+					buffer_before_minutes, buffer_after_minutes, minimum_notice_minutes, location_display_name,
+					is_online_meeting, online_meeting_provider, questions, is_active, created_at, updated_at, scheduling_mode)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+				RETURNING id
+			 `
+
+	if err := tx.QueryRow(
+		query,
+		bookingType.UserId,
+		bookingType.Slug,
+		bookingType.Title,
+		bookingType.Description,
+		bookingType.DurationMinutes,
+		bookingType.SlotIntervalMinutes,
+		bookingType.BookingWindowDays,
+		bookingType.BufferBeforeMinutes,
+		bookingType.BufferAfterMinutes,
+		bookingType.MinimumNoticeMinutes,
+		bookingType.LocationDisplayName,
+		bookingType.IsOnlineMeeting,
+		bookingType.OnlineMeetingProvider,
+		questions,
+		bookingType.IsActive,
+		bookingType.CreatedAt,
+		bookingType.UpdatedAt,
+		bookingType.SchedulingMode,
+	).Scan(&bookingType.ID); err != nil {
+		return err
+	}
+
+	memberQuery := `
+				INSERT INTO booking_type_members (booking_type_id, user_id, created_at)
+				VALUES ($1, $2, $3)
+				RETURNING id
+			 `
+
+	for i := range members {
+		member := &members[i]
+		member.BookingTypeId = bookingType.ID
+		if err := tx.QueryRow(memberQuery, member.BookingTypeId, member.UserId, member.CreatedAt).Scan(&member.ID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repository) GetBookingTypeBySlug(slug string) (dto.BookingTypeDto, error) {
+	query := `
+				SELECT * FROM booking_types WHERE slug = $1
+			 `
+
+	bookingTypes, err := r.fetchBookingTypes(query, slug)
+	if err != nil {
+		return dto.BookingTypeDto{}, err
+	}
+
+	if len(bookingTypes) == 0 {
+		return dto.BookingTypeDto{}, utility.ErrNotFound
+	}
+
+	return bookingTypes[0], nil
+}
+
+func (r *Repository) GetBookingTypeById(bookingTypeId *uuid.UUID) (dto.BookingTypeDto, error) {
+	query := `
+				SELECT * FROM booking_types WHERE id = $1
+			 `
+
+	bookingTypes, err := r.fetchBookingTypes(query, bookingTypeId)
+	if err != nil {
+		return dto.BookingTypeDto{}, err
+	}
+
+	if len(bookingTypes) == 0 {
+		return dto.BookingTypeDto{}, utility.ErrNotFound
+	}
+
+	return bookingTypes[0], nil
+}
+
+func (r *Repository) GetBookingTypesByUserId(userId *uuid.UUID) ([]dto.BookingTypeDto, error) {
+	query := `
+				SELECT * FROM booking_types WHERE user_id = $1 ORDER BY created_at
+			 `
+
+	return r.fetchBookingTypes(query, userId)
+}
+
+// DeactivateBookingType keeps the row so existing bookings can still be managed
+func (r *Repository) DeactivateBookingType(userId *uuid.UUID, bookingTypeId *uuid.UUID) error {
+	query := `
+				UPDATE booking_types SET is_active = FALSE, updated_at = CURRENT_TIMESTAMP
+				WHERE user_id = $1 AND id = $2
+			 `
+
+	result, err := r.conn.Exec(query, userId, bookingTypeId)
+	if err != nil {
+		return err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if updated == 0 {
+		return utility.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *Repository) GetBookingTypeMembersByBookingTypeId(bookingTypeId *uuid.UUID) ([]dto.BookingTypeMemberDto, error) {
+	query := `
+				SELECT * FROM booking_type_members WHERE booking_type_id = $1 ORDER BY created_at, user_id
+			 `
+
+	rows, err := r.conn.Query(query, bookingTypeId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []dto.BookingTypeMemberDto
+	for rows.Next() {
+		var member dto.BookingTypeMemberDto
+		if err := rows.Scan(
+			&member.ID,
+			&member.BookingTypeId,
+			&member.UserId,
+			&member.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	return members, nil
+}
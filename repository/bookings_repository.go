@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"encoding/json"
+
+	"github.com/scheduler-prototype/dto"
+	"github.com/scheduler-prototype/utility"
+)
+
+func (r *Repository) fetchBookings(query string, args ...interface{}) ([]dto.BookingDto, error) {
+	rows, err := r.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bookings []dto.BookingDto
+	for rows.Next() {
+		var booking dto.BookingDto
+		var answers []byte
+		if err := rows.Scan(
+			&booking.ID,
+			&booking.BookingTypeId,
+			&booking.UserId,
+			&booking.EventId,
+			&booking.ICalUid,
+			&booking.GuestName,
+			&booking.GuestEmail,
+			&answers,
+			&booking.StartTime,
+			&booking.EndTime,
+			&booking.Status,
+			&booking.ManageTokenHash,
+			&booking.CancelledAt,
+			&booking.CreatedAt,
+			&booking.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(answers, &booking.Answers); err != nil {
+			return nil, err
+		}
+		bookings = append(bookings, booking)
+	}
+	return bookings, nil
+}
+
+// CreateBooking writes the booking along with the members it was assigned to
+func (r *Repository) CreateBooking(booking *dto.BookingDto, assignments []dto.BookingAssignmentDto) error {
+	answers, err := json.Marshal(booking.Answers)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+				INSERT INTO bookings
+					(booking_type_id, user_id, event_id, ical_uid, guest_name, guest_email, answers,
+					start_time, end_time, status, manage_token_hash, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+				RETURNING id
+			 `
+
+	if err := tx.QueryRow(
+		query,
+		booking.BookingTypeId,
+		booking.UserId,
+		booking.EventId,
+		booking.ICalUid,
+		booking.GuestName,
+		booking.GuestEmail,
+		answers,
+		booking.StartTime,
+		booking.EndTime,
+		booking.Status,
+		booking.ManageTokenHash,
+		booking.CreatedAt,
+		booking.UpdatedAt,
+	).Scan(&booking.ID); err != nil {
+		return err
+	}
+
+	for i := range assignments {
+		assignments[i].BookingId = booking.ID
+	}
+	if err := createBookingAssignments(tx, assignments); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repository) GetBookingByManageTokenHash(manageTokenHash string) (dto.BookingDto, error) {
+	query := `
+				SELECT * FROM bookings WHERE manage_token_hash = $1
+			 `
+
+	bookings, err := r.fetchBookings(query, manageTokenHash)
+	if err != nil {
+		return dto.BookingDto{}, err
+	}
+
+	if len(bookings) == 0 {
+		return dto.BookingDto{}, utility.ErrNotFound
+	}
+
+	return bookings[0], nil
+}
+
+func (r *Repository) UpdateBookingTimes(booking *dto.BookingDto) error {
+	query := `
+				UPDATE bookings SET start_time = $2, end_time = $3, updated_at = CURRENT_TIMESTAMP
+				WHERE id = $1
+			 `
+
+	if _, err := r.conn.Exec(query, booking.ID, booking.StartTime, booking.EndTime); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) CancelBooking(booking *dto.BookingDto) error {
+	query := `
+				UPDATE bookings SET status = $2, cancelled_at = $3, updated_at = CURRENT_TIMESTAMP
+				WHERE id = $1
+			 `
+
+	if _, err := r.conn.Exec(query, booking.ID, dto.BookingCancelled, booking.CancelledAt); err != nil {
+		return err
+	}
+
+	return nil
+}
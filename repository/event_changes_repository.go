@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"github.com/lib/pq"
+	"github.com/scheduler-prototype/dto"
+	"github.com/scheduler-prototype/utility"
+)
+
+func (r *Repository) fetchEventChanges(query string, args ...interface{}) ([]dto.EventChangeDto, error) {
+	rows, err := r.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []dto.EventChangeDto
+	for rows.Next() {
+		var change dto.EventChangeDto
+		if err := rows.Scan(
+			&change.ID,
+			&change.RevisionId,
+			&change.EventId,
+			&change.UserId,
+			&change.ICalUid,
+			&change.GraphEventId,
+			&change.ChangeType,
+			pq.Array(&change.ChangedFields),
+			&change.IsPrivate,
+			&change.ChangedAt,
+			&change.EventType,
+		); err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// GetEventChanges returns up to limit changes after the since cursor in feed order,
+// only the changes of the given user when userId is set
+func (r *Repository) GetEventChanges(since int64, userId *string, limit int) ([]dto.EventChangeDto, error) {
+	query := `
+				SELECT * FROM event_changes
+				WHERE id > $1 AND ($2::VARCHAR IS NULL OR user_id = $2)
+				ORDER BY id
+				LIMIT $3
+			 `
+
+	return r.fetchEventChanges(query, since, userId, limit)
+}
+
+func (r *Repository) GetEventChangeById(changeId int64) (dto.EventChangeDto, error) {
+	query := `
+				SELECT * FROM event_changes WHERE id = $1
+			 `
+
+	changes, err := r.fetchEventChanges(query, changeId)
+	if err != nil {
+		return dto.EventChangeDto{}, err
+	}
+
+	if len(changes) == 0 {
+		return dto.EventChangeDto{}, utility.ErrNotFound
+	}
+
+	return changes[0], nil
+}
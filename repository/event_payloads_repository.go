@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"github.com/scheduler-prototype/dto"
+	"github.com/scheduler-prototype/utility"
+)
+
+func (r *Repository) fetchEventPayloads(query string, args ...interface{}) ([]dto.EventPayloadDto, error) {
+	rows, err := r.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payloads []dto.EventPayloadDto
+	for rows.Next() {
+		var payload dto.EventPayloadDto
+		if err := rows.Scan(
+			&payload.ID,
+			&payload.UserId,
+			&payload.EventId,
+			&payload.ICalUid,
+			&payload.Payload,
+			&payload.PayloadHash,
+			&payload.Source,
+			&payload.SyncRunId,
+			&payload.DeltaPage,
+			&payload.DeltaPageLink,
+			&payload.ReceivedAt,
+			&payload.RemovedAt,
+		); err != nil {
+			return nil, err
+		}
+		payloads = append(payloads, payload)
+	}
+	return payloads, nil
+}
+
+func (r *Repository) CreateEventPayload(payload *dto.EventPayloadDto) error {
+	query := `
+				INSERT INTO event_payloads
+					(user_id, event_id, ical_uid, payload, payload_hash, source, sync_run_id, delta_page, delta_page_link, received_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+				RETURNING id
+			 `
+
+	return r.conn.QueryRow(
+		query,
+		payload.UserId,
+		payload.EventId,
+		payload.ICalUid,
+		payload.Payload,
+		payload.PayloadHash,
+		payload.Source,
+		payload.SyncRunId,
+		payload.DeltaPage,
+		payload.DeltaPageLink,
+		payload.ReceivedAt,
+	).Scan(&payload.ID)
+}
+
+// GetLatestEventPayloadByUserIdAndICalUid returns the last payload received for the user's copy of the event
+func (r *Repository) GetLatestEventPayloadByUserIdAndICalUid(userId string, iCalUid string) (dto.EventPayloadDto, error) {
+	query := `
+				SELECT * FROM event_payloads WHERE user_id = $1 AND ical_uid = $2
+				ORDER BY received_at DESC LIMIT 1
+			 `
+
+	payloads, err := r.fetchEventPayloads(query, userId, iCalUid)
+	if err != nil {
+		return dto.EventPayloadDto{}, err
+	}
+
+	if len(payloads) == 0 {
+		return dto.EventPayloadDto{}, utility.ErrNotFound
+	}
+
+	return payloads[0], nil
+}
+
+// GetLatestEventPayloads returns the last payload of every copy that wasn't removed, grouped by iCalUId,
+// only the copies of the given user when userId is set
+func (r *Repository) GetLatestEventPayloads(userId *string) ([]dto.EventPayloadDto, error) {
+	query := `
+				SELECT * FROM (
+					SELECT DISTINCT ON (user_id, ical_uid) * FROM event_payloads
+					WHERE ($1::VARCHAR IS NULL OR user_id = $1)
+					ORDER BY user_id, ical_uid, received_at DESC
+				) latest
+				WHERE removed_at IS NULL
+				ORDER BY ical_uid, user_id
+			 `
+
+	return r.fetchEventPayloads(query, userId)
+}
+
+// MarkEventPayloadsRemoved flags the payloads of the user's copy once the copy is gone
+func (r *Repository) MarkEventPayloadsRemoved(userId string, iCalUid string) error {
+	query := `
+				UPDATE event_payloads SET removed_at = CURRENT_TIMESTAMP
+				WHERE user_id = $1 AND ical_uid = $2 AND removed_at IS NULL
+			 `
+
+	if _, err := r.conn.Exec(query, userId, iCalUid); err != nil {
+		return err
+	}
+
+	return nil
+}
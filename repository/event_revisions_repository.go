@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/scheduler-prototype/dto"
+)
+
+// createEventRevision records the revision, adds it to the change feed and queues its webhook deliveries
+// in the caller's transaction, so they commit together with the events row they describe
+func createEventRevision(tx *sql.Tx, revision *dto.EventRevisionDto) error {
+	changes, err := json.Marshal(revision.Changes)
+	if err != nil {
+		return err
+	}
+
+	changedFields := []string{}
+	for field := range revision.Changes {
+		changedFields = append(changedFields, field)
+	}
+	sort.Strings(changedFields)
+
+	query := `
+				INSERT INTO event_revisions
+					(event_id, user_id, ical_uid, graph_event_id, revision_type, changes,
+					source, sync_run_id, delta_link, graph_modified_at, is_private, recorded_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+				RETURNING id
+			 `
+
+	if err := tx.QueryRow(
+		query,
+		revision.EventId,
+		revision.UserId,
+		revision.ICalUid,
+		revision.GraphEventId,
+		revision.RevisionType,
+		changes,
+		revision.Source,
+		revision.SyncRunId,
+		revision.DeltaLink,
+		revision.GraphModifiedAt,
+		revision.IsPrivate,
+		revision.RecordedAt,
+	).Scan(&revision.ID); err != nil {
+		return err
+	}
+
+	// serializes the writers of the feed, so a change with a lower id can't commit after a consumer read a higher one
+	if _, err := tx.Exec(`LOCK TABLE event_changes IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+		return err
+	}
+
+	query = `
+				INSERT INTO event_changes
+					(revision_id, event_id, user_id, ical_uid, graph_event_id, change_type, changed_fields, is_private, changed_at, event_type)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+				RETURNING id
+			 `
+
+	var changeId int64
+	if err := tx.QueryRow(
+		query,
+		revision.ID,
+		revision.EventId,
+		revision.UserId,
+		revision.ICalUid,
+		revision.GraphEventId,
+		revision.RevisionType,
+		pq.Array(changedFields),
+		revision.IsPrivate,
+		revision.RecordedAt,
+		revision.EventType,
+	).Scan(&changeId); err != nil {
+		return err
+	}
+
+	return createWebhookDeliveries(tx, changeId, revision)
+}
+
+// GetEventRevisionsByEventId returns the revisions of the events row, oldest first
+func (r *Repository) GetEventRevisionsByEventId(eventId *uuid.UUID) ([]dto.EventRevisionDto, error) {
+	query := `
+				SELECT * FROM event_revisions WHERE event_id = $1 ORDER BY recorded_at
+			 `
+
+	rows, err := r.conn.Query(query, eventId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []dto.EventRevisionDto
+	for rows.Next() {
+		var revision dto.EventRevisionDto
+		var changes []byte
+		if err := rows.Scan(
+			&revision.ID,
+			&revision.EventId,
+			&revision.UserId,
+			&revision.ICalUid,
+			&revision.GraphEventId,
+			&revision.RevisionType,
+			&changes,
+			&revision.Source,
+			&revision.SyncRunId,
+			&revision.DeltaLink,
+			&revision.GraphModifiedAt,
+			&revision.IsPrivate,
+			&revision.RecordedAt,
+		); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(changes, &revision.Changes); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+	return revisions, nil
+}
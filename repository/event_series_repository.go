@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/scheduler-prototype/dto"
+	"github.com/scheduler-prototype/utility"
+)
+
+func (r *Repository) fetchEventSeries(query string, args ...interface{}) ([]dto.MGraphEventSeriesDto, error) {
+	rows, err := r.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var eventSeries []dto.MGraphEventSeriesDto
+	for rows.Next() {
+		var series dto.MGraphEventSeriesDto
+		if err := rows.Scan(
+			&series.ID,
+			&series.SeriesMasterId,
+			&series.ICalUid,
+			&series.PatternType,
+			&series.PatternInterval,
+			pq.Array(&series.PatternDaysOfWeek),
+			&series.PatternDayOfMonth,
+			&series.PatternMonth,
+			&series.PatternIndex,
+			&series.PatternFirstDayOfWeek,
+			&series.RangeType,
+			&series.RangeStartDate,
+			&series.RangeEndDate,
+			&series.RangeNumberOfOccurrences,
+			&series.RecurrenceTimeZone,
+			&series.CreatedAt,
+			&series.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		eventSeries = append(eventSeries, series)
+	}
+	return eventSeries, nil
+}
+
+// UpsertEventSeries creates the series for a series master, or replaces its recurrence if it already exists
+func (r *Repository) UpsertEventSeries(series *dto.MGraphEventSeriesDto) error {
+	query := `
+				INSERT INTO event_series
+					(series_master_id, ical_uid, pattern_type, pattern_interval, pattern_days_of_week,
+					pattern_day_of_month, pattern_month, pattern_index, pattern_first_day_of_week,
+					range_type, range_start_date, range_end_date, range_number_of_occurrences,
+					recurrence_time_zone, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+				ON CONFLICT (series_master_id) DO UPDATE SET
+					ical_uid = EXCLUDED.ical_uid,
+					pattern_type = EXCLUDED.pattern_type,
+					pattern_interval = EXCLUDED.pattern_interval,
+					pattern_days_of_week = EXCLUDED.pattern_days_of_week,
+					pattern_day_of_month = EXCLUDED.pattern_day_of_month,
+					pattern_month = EXCLUDED.pattern_month,
+					pattern_index = EXCLUDED.pattern_index,
+					pattern_first_day_of_week = EXCLUDED.pattern_first_day_of_week,
+					range_type = EXCLUDED.range_type,
+					range_start_date = EXCLUDED.range_start_date,
+					range_end_date = EXCLUDED.range_end_date,
+					range_number_of_occurrences = EXCLUDED.range_number_of_occurrences,
+					recurrence_time_zone = EXCLUDED.recurrence_time_zone,
+					updated_at = EXCLUDED.updated_at
+				RETURNING id
+			 `
+
+	if err := r.conn.QueryRow(
+		query,
+		series.SeriesMasterId,
+		series.ICalUid,
+		series.PatternType,
+		series.PatternInterval,
+		pq.Array(series.PatternDaysOfWeek),
+		series.PatternDayOfMonth,
+		series.PatternMonth,
+		series.PatternIndex,
+		series.PatternFirstDayOfWeek,
+		series.RangeType,
+		series.RangeStartDate,
+		series.RangeEndDate,
+		series.RangeNumberOfOccurrences,
+		series.RecurrenceTimeZone,
+		series.CreatedAt,
+		series.UpdatedAt,
+	).Scan(&series.ID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) GetEventSeriesBySeriesMasterId(seriesMasterId string) (dto.MGraphEventSeriesDto, error) {
+	query := `
+				SELECT * FROM event_series WHERE series_master_id = $1
+			 `
+
+	eventSeries, err := r.fetchEventSeries(query, seriesMasterId)
+	if err != nil {
+		return dto.MGraphEventSeriesDto{}, err
+	}
+
+	if len(eventSeries) == 0 {
+		return dto.MGraphEventSeriesDto{}, utility.ErrNotFound
+	}
+
+	return eventSeries[0], nil
+}
+
+// LinkEventsToEventSeries links the master and any instance synced before its master to the series
+func (r *Repository) LinkEventsToEventSeries(series *dto.MGraphEventSeriesDto) error {
+	query := `
+				UPDATE events SET event_series_id = $1
+				WHERE event_id = $2 OR series_master_id = $2
+			 `
+
+	if _, err := r.conn.Exec(query, series.ID, series.SeriesMasterId); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpsertEventSeriesException records an occurrence that was modified or cancelled,
+// an occurrence is identified by its original start within the series
+func (r *Repository) UpsertEventSeriesException(exception *dto.MGraphEventSeriesExceptionDto) error {
+	query := `
+				INSERT INTO event_series_exceptions
+					(event_series_id, event_id, exception_type, original_start, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				ON CONFLICT (event_series_id, original_start) DO UPDATE SET
+					event_id = EXCLUDED.event_id,
+					exception_type = EXCLUDED.exception_type,
+					updated_at = EXCLUDED.updated_at
+				RETURNING id
+			 `
+
+	if err := r.conn.QueryRow(
+		query,
+		exception.EventSeriesId,
+		exception.EventId,
+		exception.ExceptionType,
+		exception.OriginalStart,
+		exception.CreatedAt,
+		exception.UpdatedAt,
+	).Scan(&exception.ID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) DeleteEventSeriesExceptionsFromOriginalStart(eventSeriesId uuid.UUID, originalStart time.Time) error {
+	query := `
+				DELETE FROM event_series_exceptions WHERE event_series_id = $1 AND original_start >= $2
+			 `
+
+	if _, err := r.conn.Exec(query, eventSeriesId, originalStart); err != nil {
+		return err
+	}
+
+	return nil
+}
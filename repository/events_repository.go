@@ -1,6 +1,12 @@
 package repository
 
 import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/scheduler-prototype/dto"
 	"github.com/scheduler-prototype/utility"
 )
@@ -39,6 +45,21 @@ func (r *Repository) fetchEvents(query string, args ...interface{}) ([]dto.MGrap
 			&event.SeriesMasterId,
 			&event.CreatedAt,
 			&event.UpdatedAt,
+			&event.EventSeriesId,
+			&event.IanaTimezone,
+			&event.StartDate,
+			&event.EndDate,
+			&event.ShowAs,
+			&event.Importance,
+			&event.Sensitivity,
+			pq.Array(&event.Categories),
+			&event.IsReminderOn,
+			&event.ReminderMinutesBeforeStart,
+			&event.ResponseRequested,
+			&event.AllowNewTimeProposals,
+			&event.HideAttendees,
+			&event.OrganizerEmail,
+			&event.IsPrivate,
 		); err != nil {
 			return nil, err
 		}
@@ -47,19 +68,45 @@ func (r *Repository) fetchEvents(query string, args ...interface{}) ([]dto.MGrap
 	return events, nil
 }
 
-func (r *Repository) CreateEvent(event *dto.MGraphEventDto) error {
+// CreateEvent stores the user's copy of the event, a copy stored in the meantime by a racing sync is overwritten
+// -- revision, if any, is recorded against the stored row in the same transaction
+func (r *Repository) CreateEvent(event *dto.MGraphEventDto, revision *dto.EventRevisionDto) error {
+	tx, err := r.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `
 				INSERT INTO events 
 					(user_id, ical_uid, event_id, title, description, 
 					locations_count, start_time, end_time, is_online, 
 					is_all_day, is_cancelled, organizer_user_id, 
 					created_time, updated_time, timezone, platform_url, 
-					meeting_url, type, is_recurring, series_master_id, created_at, updated_at)
-				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20 , $21, $22) 
+					meeting_url, type, is_recurring, series_master_id, created_at, updated_at,
+					event_series_id, iana_timezone, start_date, end_date, show_as,
+					importance, sensitivity, categories, is_reminder_on, reminder_minutes_before_start,
+					response_requested, allow_new_time_proposals, hide_attendees, organizer_email, is_private)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20 , $21, $22, $23, $24, $25, $26, $27,
+					$28, $29, $30, $31, $32, $33, $34, $35, $36, $37) 
+				ON CONFLICT (user_id, ical_uid) DO UPDATE SET
+					event_id = EXCLUDED.event_id, title = EXCLUDED.title, description = EXCLUDED.description,
+					locations_count = EXCLUDED.locations_count, start_time = EXCLUDED.start_time, end_time = EXCLUDED.end_time,
+					is_online = EXCLUDED.is_online, is_all_day = EXCLUDED.is_all_day, is_cancelled = EXCLUDED.is_cancelled,
+					organizer_user_id = EXCLUDED.organizer_user_id, created_time = EXCLUDED.created_time,
+					updated_time = EXCLUDED.updated_time, timezone = EXCLUDED.timezone, platform_url = EXCLUDED.platform_url,
+					meeting_url = EXCLUDED.meeting_url, type = EXCLUDED.type, is_recurring = EXCLUDED.is_recurring,
+					series_master_id = EXCLUDED.series_master_id, updated_at = EXCLUDED.updated_at,
+					event_series_id = EXCLUDED.event_series_id, iana_timezone = EXCLUDED.iana_timezone,
+					start_date = EXCLUDED.start_date, end_date = EXCLUDED.end_date, show_as = EXCLUDED.show_as,
+					importance = EXCLUDED.importance, sensitivity = EXCLUDED.sensitivity, categories = EXCLUDED.categories,
+					is_reminder_on = EXCLUDED.is_reminder_on, reminder_minutes_before_start = EXCLUDED.reminder_minutes_before_start,
+					response_requested = EXCLUDED.response_requested, allow_new_time_proposals = EXCLUDED.allow_new_time_proposals,
+					hide_attendees = EXCLUDED.hide_attendees, organizer_email = EXCLUDED.organizer_email, is_private = EXCLUDED.is_private
 				RETURNING id
 			 `
 
-	if err := r.conn.QueryRow(
+	if err := tx.QueryRow(
 		query,
 		event.UserId,
 		event.ICalUid,
@@ -83,19 +130,140 @@ func (r *Repository) CreateEvent(event *dto.MGraphEventDto) error {
 		event.SeriesMasterId,
 		event.CreatedAt,
 		event.UpdatedAt,
+		event.EventSeriesId,
+		event.IanaTimezone,
+		event.StartDate,
+		event.EndDate,
+		event.ShowAs,
+		event.Importance,
+		event.Sensitivity,
+		pq.Array(event.Categories),
+		event.IsReminderOn,
+		event.ReminderMinutesBeforeStart,
+		event.ResponseRequested,
+		event.AllowNewTimeProposals,
+		event.HideAttendees,
+		event.OrganizerEmail,
+		event.IsPrivate,
 	).Scan(&event.ID); err != nil {
 		return err
 	}
 
-	return nil
+	if revision != nil {
+		revision.EventId = event.ID
+		if err := createEventRevision(tx, revision); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// the same meeting shares its iCalUId across the calendars of every synced user
+func (r *Repository) GetEventByUserIdAndICalUid(userId string, iCalUid string) (dto.MGraphEventDto, error) {
+	query := `
+				SELECT * FROM events WHERE user_id = $1 AND ical_uid = $2
+			 `
+
+	events, err := r.fetchEvents(query, userId, iCalUid)
+	if err != nil {
+		return dto.MGraphEventDto{}, err
+	}
+
+	if len(events) == 0 {
+		return dto.MGraphEventDto{}, utility.ErrNotFound
+	}
+
+	// events_user_id_ical_uid_idx is unique, there is at most one
+	return events[0], nil
+}
+
+// GetEventsByFilter returns the events overlapping the filter's range that match every filter that is set
+func (r *Repository) GetEventsByFilter(filter dto.EventFilterDto) ([]dto.MGraphEventDto, error) {
+	query := `
+				SELECT * FROM events WHERE start_time < $2 AND end_time > $1
+			 `
+	args := []interface{}{filter.StartTime, filter.EndTime}
+
+	// each filter adds its value as the next placeholder
+	where := func(condition string, value interface{}) {
+		args = append(args, value)
+		query += " AND " + fmt.Sprintf(condition, len(args))
+	}
+
+	if filter.UserId != nil {
+		where("user_id = $%d", *filter.UserId)
+	}
+	if filter.ShowAs != nil {
+		where("show_as = $%d", *filter.ShowAs)
+	}
+	if filter.Importance != nil {
+		where("importance = $%d", *filter.Importance)
+	}
+	if filter.Sensitivity != nil {
+		where("sensitivity = $%d", *filter.Sensitivity)
+	}
+	if filter.Category != nil {
+		where("$%d = ANY(categories)", *filter.Category)
+	}
+	if filter.OrganizerEmail != nil {
+		where("LOWER(organizer_email) = LOWER($%d)", *filter.OrganizerEmail)
+	}
+	if filter.IsReminderOn != nil {
+		where("is_reminder_on = $%d", *filter.IsReminderOn)
+	}
+
+	return r.fetchEvents(query+" ORDER BY start_time", args...)
+}
+
+// GetBusyEventsByUserIdAndTimeRange returns the user's events that count towards free/busy,
+// series masters are skipped since each occurrence is stored as its own event
+func (r *Repository) GetBusyEventsByUserIdAndTimeRange(userId string, startTime time.Time, endTime time.Time) ([]dto.MGraphEventDto, error) {
+	query := `
+				SELECT * FROM events
+				WHERE user_id = $1 AND is_cancelled = FALSE AND start_time < $3 AND end_time > $2
+				AND (type IS NULL OR type <> 'seriesMaster')
+				ORDER BY start_time
+			 `
+
+	return r.fetchEvents(query, userId, startTime, endTime)
+}
+
+// CountBusyEventsByUserIdAndTimeRange counts the same events GetBusyEventsByUserIdAndTimeRange returns
+func (r *Repository) CountBusyEventsByUserIdAndTimeRange(userId string, startTime time.Time, endTime time.Time) (int, error) {
+	query := `
+				SELECT COUNT(*) FROM events
+				WHERE user_id = $1 AND is_cancelled = FALSE AND start_time < $3 AND end_time > $2
+				AND (type IS NULL OR type <> 'seriesMaster')
+			 `
+
+	var count int
+	if err := r.conn.QueryRow(query, userId, startTime, endTime).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetEventsByUserIdEndingBetween returns the user's events that ended after from and by to,
+// series masters are skipped since their occurrences carry the times
+func (r *Repository) GetEventsByUserIdEndingBetween(userId string, from time.Time, to time.Time) ([]dto.MGraphEventDto, error) {
+	query := `
+				SELECT * FROM events
+				WHERE user_id = $1 AND end_time > $2 AND end_time <= $3
+				AND (type IS NULL OR type <> 'seriesMaster')
+				ORDER BY start_time
+			 `
+
+	return r.fetchEvents(query, userId, from, to)
 }
 
-func (r *Repository) GetEventByICalUid(iCalUid string) (dto.MGraphEventDto, error) {
+func (r *Repository) GetEventById(id *uuid.UUID) (dto.MGraphEventDto, error) {
 	query := `
-				SELECT * FROM events WHERE ical_uid = $1
+				SELECT * FROM events WHERE id = $1
 			 `
 
-	events, err := r.fetchEvents(query, iCalUid)
+	events, err := r.fetchEvents(query, id)
 	if err != nil {
 		return dto.MGraphEventDto{}, err
 	}
@@ -106,3 +274,266 @@ func (r *Repository) GetEventByICalUid(iCalUid string) (dto.MGraphEventDto, erro
 
 	return events[0], nil
 }
+
+// GetEventsByIds returns the events rows that still exist among the given ids
+func (r *Repository) GetEventsByIds(ids []uuid.UUID) ([]dto.MGraphEventDto, error) {
+	query := `
+				SELECT * FROM events WHERE id = ANY($1)
+			 `
+
+	return r.fetchEvents(query, pq.Array(ids))
+}
+
+func (r *Repository) GetEventByEventId(eventId string) (dto.MGraphEventDto, error) {
+	query := `
+				SELECT * FROM events WHERE event_id = $1
+			 `
+
+	events, err := r.fetchEvents(query, eventId)
+	if err != nil {
+		return dto.MGraphEventDto{}, err
+	}
+
+	if len(events) == 0 {
+		return dto.MGraphEventDto{}, utility.ErrNotFound
+	}
+
+	return events[0], nil
+}
+
+// graph event ids are per mailbox, the user's copy is the one the id was handed out for
+func (r *Repository) GetEventByUserIdAndEventId(userId string, eventId string) (dto.MGraphEventDto, error) {
+	query := `
+				SELECT * FROM events WHERE user_id = $1 AND event_id = $2
+			 `
+
+	events, err := r.fetchEvents(query, userId, eventId)
+	if err != nil {
+		return dto.MGraphEventDto{}, err
+	}
+
+	if len(events) == 0 {
+		return dto.MGraphEventDto{}, utility.ErrNotFound
+	}
+
+	return events[0], nil
+}
+
+func (r *Repository) GetEventsBySeriesMasterIdFromStartTime(seriesMasterId string, startTime time.Time) ([]dto.MGraphEventDto, error) {
+	query := `
+				SELECT * FROM events WHERE series_master_id = $1 AND start_time >= $2 ORDER BY start_time
+			 `
+
+	return r.fetchEvents(query, seriesMasterId, startTime)
+}
+
+// UpdateEvent refreshes the stored row, revision, if any, is recorded in the same transaction
+func (r *Repository) UpdateEvent(event *dto.MGraphEventDto, revision *dto.EventRevisionDto) error {
+	tx, err := r.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+				UPDATE events SET
+					event_id = $2, title = $3, description = $4, locations_count = $5,
+					start_time = $6, end_time = $7, is_online = $8, is_all_day = $9,
+					is_cancelled = $10, updated_time = $11, timezone = $12, platform_url = $13,
+					meeting_url = $14, type = $15, is_recurring = $16, series_master_id = $17,
+					updated_at = $18, event_series_id = $19, iana_timezone = $20,
+					start_date = $21, end_date = $22, user_id = $23, show_as = $24,
+					importance = $25, sensitivity = $26, categories = $27, is_reminder_on = $28,
+					reminder_minutes_before_start = $29, response_requested = $30,
+					allow_new_time_proposals = $31, hide_attendees = $32, organizer_email = $33,
+					is_private = $34
+				WHERE id = $1
+			 `
+
+	if _, err := tx.Exec(
+		query,
+		event.ID,
+		event.EventId,
+		event.Title,
+		event.Description,
+		event.LocationsCount,
+		event.StartTime,
+		event.EndTime,
+		event.IsOnline,
+		event.IsAllDay,
+		event.IsCancelled,
+		event.UpdatedTime,
+		event.Timezone,
+		event.PlatformUrl,
+		event.MeetingUrl,
+		event.Type,
+		event.IsRecurring,
+		event.SeriesMasterId,
+		event.UpdatedAt,
+		event.EventSeriesId,
+		event.IanaTimezone,
+		event.StartDate,
+		event.EndDate,
+		event.UserId,
+		event.ShowAs,
+		event.Importance,
+		event.Sensitivity,
+		pq.Array(event.Categories),
+		event.IsReminderOn,
+		event.ReminderMinutesBeforeStart,
+		event.ResponseRequested,
+		event.AllowNewTimeProposals,
+		event.HideAttendees,
+		event.OrganizerEmail,
+		event.IsPrivate,
+	); err != nil {
+		return err
+	}
+
+	if revision != nil {
+		if err := createEventRevision(tx, revision); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetUncancelledEventsByEventId returns the copies cancelling the event affects,
+// a series master brings its occurrences and exceptions along
+func (r *Repository) GetUncancelledEventsByEventId(eventId string) ([]dto.MGraphEventDto, error) {
+	query := `
+				SELECT * FROM events WHERE (event_id = $1 OR series_master_id = $1) AND is_cancelled = FALSE
+			 `
+
+	return r.fetchEvents(query, eventId)
+}
+
+// CancelEvent marks the copy cancelled and records revision in the same transaction,
+// a copy cancelled in the meantime is left alone and its revision isn't recorded
+func (r *Repository) CancelEvent(event *dto.MGraphEventDto, revision *dto.EventRevisionDto) error {
+	tx, err := r.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+				UPDATE events SET is_cancelled = TRUE, updated_at = $2
+				WHERE id = $1 AND is_cancelled = FALSE
+			 `
+
+	result, err := tx.Exec(query, event.ID, event.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return nil
+	}
+
+	if revision != nil {
+		if err := createEventRevision(tx, revision); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repository) GetLastEndTimeBySeriesMasterIdFromStartTime(seriesMasterId string, startTime time.Time) (*time.Time, error) {
+	query := `
+				SELECT MAX(end_time) FROM events WHERE series_master_id = $1 AND start_time >= $2
+			 `
+
+	var endTime sql.NullTime
+	if err := r.conn.QueryRow(query, seriesMasterId, startTime).Scan(&endTime); err != nil {
+		return nil, err
+	}
+
+	if !endTime.Valid {
+		return nil, utility.ErrNotFound
+	}
+
+	return &endTime.Time, nil
+}
+
+// DeleteEventByUserIdAndICalUid deletes the user's copy, revision, if any, is recorded in the same transaction
+func (r *Repository) DeleteEventByUserIdAndICalUid(userId string, iCalUid string, revision *dto.EventRevisionDto) error {
+	tx, err := r.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+				DELETE FROM events WHERE user_id = $1 AND ical_uid = $2
+			 `
+
+	if _, err := tx.Exec(query, userId, iCalUid); err != nil {
+		return err
+	}
+
+	if revision != nil {
+		if err := createEventRevision(tx, revision); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// CountEventsByICalUid counts the copies of a meeting across the synced users' calendars
+func (r *Repository) CountEventsByICalUid(iCalUid string) (int, error) {
+	query := `
+				SELECT COUNT(*) FROM events WHERE ical_uid = $1
+			 `
+
+	var count int
+	if err := r.conn.QueryRow(query, iCalUid).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetEventsWithoutIanaTimezone returns the rows stored before iana_timezone was added, only with the columns
+// the backfill needs since the rest of the row can't be read while the column is null
+func (r *Repository) GetEventsWithoutIanaTimezone() ([]dto.MGraphEventDto, error) {
+	query := `
+				SELECT id, timezone, start_time, end_time, is_all_day FROM events WHERE iana_timezone IS NULL
+			 `
+
+	rows, err := r.conn.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []dto.MGraphEventDto
+	for rows.Next() {
+		var event dto.MGraphEventDto
+		if err := rows.Scan(&event.ID, &event.Timezone, &event.StartTime, &event.EndTime, &event.IsAllDay); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func (r *Repository) UpdateIanaTimezoneByEvent(event *dto.MGraphEventDto) error {
+	query := `
+				UPDATE events SET iana_timezone = $2, start_time = $3, end_time = $4, start_date = $5, end_date = $6
+				WHERE id = $1 AND iana_timezone IS NULL
+			 `
+
+	if _, err := r.conn.Exec(query, event.ID, event.IanaTimezone, event.StartTime, event.EndTime, event.StartDate, event.EndDate); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/scheduler-prototype/dto"
+	"github.com/scheduler-prototype/utility"
+)
+
+func (r *Repository) fetchHolds(query string, args ...interface{}) ([]dto.HoldDto, error) {
+	rows, err := r.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var holds []dto.HoldDto
+	for rows.Next() {
+		var hold dto.HoldDto
+		if err := rows.Scan(
+			&hold.ID,
+			&hold.UserId,
+			&hold.EventId,
+			&hold.ICalUid,
+			&hold.Subject,
+			&hold.StartTime,
+			&hold.EndTime,
+			&hold.ExpiresAt,
+			&hold.Status,
+			&hold.ConfirmedAt,
+			&hold.CreatedAt,
+			&hold.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		holds = append(holds, hold)
+	}
+	return holds, nil
+}
+
+func (r *Repository) CreateHold(hold *dto.HoldDto) error {
+	query := `
+				INSERT INTO holds
+					(user_id, event_id, ical_uid, subject, start_time, end_time, expires_at, status, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+				RETURNING id
+			 `
+
+	if err := r.conn.QueryRow(
+		query,
+		hold.UserId,
+		hold.EventId,
+		hold.ICalUid,
+		hold.Subject,
+		hold.StartTime,
+		hold.EndTime,
+		hold.ExpiresAt,
+		hold.Status,
+		hold.CreatedAt,
+		hold.UpdatedAt,
+	).Scan(&hold.ID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) GetHoldById(holdId *uuid.UUID) (dto.HoldDto, error) {
+	query := `
+				SELECT * FROM holds WHERE id = $1
+			 `
+
+	holds, err := r.fetchHolds(query, holdId)
+	if err != nil {
+		return dto.HoldDto{}, err
+	}
+
+	if len(holds) == 0 {
+		return dto.HoldDto{}, utility.ErrNotFound
+	}
+
+	return holds[0], nil
+}
+
+// GetExpiredHolds returns the holds still held past their expiry
+func (r *Repository) GetExpiredHolds(now time.Time) ([]dto.HoldDto, error) {
+	query := `
+				SELECT * FROM holds WHERE status = $1 AND expires_at <= $2 ORDER BY expires_at
+			 `
+
+	return r.fetchHolds(query, dto.HoldHeld, now)
+}
+
+// ClaimHold moves a held hold to confirming while it still runs, or to expiring once it ran out,
+// ErrConflict when it isn't held anymore or is on the other side of its expiry
+func (r *Repository) ClaimHold(holdId *uuid.UUID, to string, now time.Time) error {
+	query := `
+				UPDATE holds SET status = $2, updated_at = CURRENT_TIMESTAMP
+				WHERE id = $1 AND status = $3 AND expires_at > $4
+			 `
+	if to == dto.HoldExpiring {
+		query = `
+				UPDATE holds SET status = $2, updated_at = CURRENT_TIMESTAMP
+				WHERE id = $1 AND status = $3 AND expires_at <= $4
+			 `
+	}
+
+	result, err := r.conn.Exec(query, holdId, to, dto.HoldHeld, now)
+	if err != nil {
+		return err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if updated == 0 {
+		return utility.ErrConflict
+	}
+
+	return nil
+}
+
+// UpdateHoldStatus moves a claimed hold on from the given status, ErrConflict when it isn't in it anymore
+func (r *Repository) UpdateHoldStatus(hold *dto.HoldDto, from string) error {
+	query := `
+				UPDATE holds SET status = $2, subject = $3, confirmed_at = $4, updated_at = CURRENT_TIMESTAMP
+				WHERE id = $1 AND status = $5
+			 `
+
+	result, err := r.conn.Exec(query, hold.ID, hold.Status, hold.Subject, hold.ConfirmedAt, from)
+	if err != nil {
+		return err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if updated == 0 {
+		return utility.ErrConflict
+	}
+
+	return nil
+}
@@ -87,3 +87,15 @@ func (r *Repository) GetLocationByICalUidAndDisplayName(iCalUid string, displayN
 
 	return locations[0], nil
 }
+
+func (r *Repository) DeleteLocationsByICalUid(iCalUid string) error {
+	query := `
+				DELETE FROM locations WHERE ical_uid = $1
+			 `
+
+	if _, err := r.conn.Exec(query, iCalUid); err != nil {
+		return err
+	}
+
+	return nil
+}
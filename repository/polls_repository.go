@@ -0,0 +1,329 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/scheduler-prototype/dto"
+	"github.com/scheduler-prototype/utility"
+)
+
+func (r *Repository) fetchPolls(query string, args ...interface{}) ([]dto.PollDto, error) {
+	rows, err := r.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var polls []dto.PollDto
+	for rows.Next() {
+		var poll dto.PollDto
+		if err := rows.Scan(
+			&poll.ID,
+			&poll.UserId,
+			&poll.Title,
+			&poll.Description,
+			&poll.LocationDisplayName,
+			&poll.IsOnlineMeeting,
+			&poll.OnlineMeetingProvider,
+			&poll.Status,
+			&poll.FinalOptionId,
+			&poll.EventId,
+			&poll.ICalUid,
+			&poll.ClosedAt,
+			&poll.CreatedAt,
+			&poll.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		polls = append(polls, poll)
+	}
+	return polls, nil
+}
+
+// CreatePoll creates the poll along with its options and invitees in a single transaction
+func (r *Repository) CreatePoll(poll *dto.PollDto, options []dto.PollOptionDto, invitees []dto.PollInviteeDto) error {
+	tx, err := r.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+				INSERT INTO polls
+					(user_id, title, description, location_display_name, is_online_meeting, online_meeting_provider,
+					status, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+				RETURNING id
+			 `
+
+	if err := tx.QueryRow(
+		query,
+		poll.UserId,
+		poll.Title,
+		poll.Description,
+		poll.LocationDisplayName,
+		poll.IsOnlineMeeting,
+		poll.OnlineMeetingProvider,
+		poll.Status,
+		poll.CreatedAt,
+		poll.UpdatedAt,
+	).Scan(&poll.ID); err != nil {
+		return err
+	}
+
+	optionQuery := `
+				INSERT INTO poll_options (poll_id, start_time, end_time, created_at)
+				VALUES ($1, $2, $3, $4)
+				RETURNING id
+			 `
+
+	for i := range options {
+		option := &options[i]
+		option.PollId = poll.ID
+		if err := tx.QueryRow(optionQuery, option.PollId, option.StartTime, option.EndTime, option.CreatedAt).Scan(&option.ID); err != nil {
+			return err
+		}
+	}
+
+	inviteeQuery := `
+				INSERT INTO poll_invitees (poll_id, email_address, name, token_hash, created_at)
+				VALUES ($1, $2, $3, $4, $5)
+				RETURNING id
+			 `
+
+	for i := range invitees {
+		invitee := &invitees[i]
+		invitee.PollId = poll.ID
+		if err := tx.QueryRow(inviteeQuery, invitee.PollId, invitee.EmailAddress, invitee.Name, invitee.TokenHash, invitee.CreatedAt).Scan(&invitee.ID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repository) GetPollById(pollId *uuid.UUID) (dto.PollDto, error) {
+	query := `
+				SELECT * FROM polls WHERE id = $1
+			 `
+
+	polls, err := r.fetchPolls(query, pollId)
+	if err != nil {
+		return dto.PollDto{}, err
+	}
+
+	if len(polls) == 0 {
+		return dto.PollDto{}, utility.ErrNotFound
+	}
+
+	return polls[0], nil
+}
+
+func (r *Repository) GetPollsByUserId(userId *uuid.UUID) ([]dto.PollDto, error) {
+	query := `
+				SELECT * FROM polls WHERE user_id = $1 ORDER BY created_at DESC
+			 `
+
+	return r.fetchPolls(query, userId)
+}
+
+// UpdatePollStatus moves the poll from one status to another, ErrConflict when it isn't in the first one anymore
+func (r *Repository) UpdatePollStatus(pollId *uuid.UUID, from string, to string) error {
+	query := `
+				UPDATE polls SET status = $2, updated_at = CURRENT_TIMESTAMP
+				WHERE id = $1 AND status = $3
+			 `
+
+	result, err := r.conn.Exec(query, pollId, to, from)
+	if err != nil {
+		return err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if updated == 0 {
+		return utility.ErrConflict
+	}
+
+	return nil
+}
+
+// ClosePoll records the final option and event of a finalizing poll, ErrConflict when it isn't finalizing
+func (r *Repository) ClosePoll(poll *dto.PollDto) error {
+	query := `
+				UPDATE polls SET status = $2, final_option_id = $3, event_id = $4, ical_uid = $5, closed_at = $6,
+					updated_at = CURRENT_TIMESTAMP
+				WHERE id = $1 AND status = $7
+			 `
+
+	result, err := r.conn.Exec(
+		query,
+		poll.ID,
+		dto.PollClosed,
+		poll.FinalOptionId,
+		poll.EventId,
+		poll.ICalUid,
+		poll.ClosedAt,
+		dto.PollFinalizing,
+	)
+	if err != nil {
+		return err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if updated == 0 {
+		return utility.ErrConflict
+	}
+
+	return nil
+}
+
+func (r *Repository) GetPollOptionsByPollId(pollId *uuid.UUID) ([]dto.PollOptionDto, error) {
+	query := `
+				SELECT * FROM poll_options WHERE poll_id = $1 ORDER BY start_time
+			 `
+
+	rows, err := r.conn.Query(query, pollId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var options []dto.PollOptionDto
+	for rows.Next() {
+		var option dto.PollOptionDto
+		if err := rows.Scan(
+			&option.ID,
+			&option.PollId,
+			&option.StartTime,
+			&option.EndTime,
+			&option.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		options = append(options, option)
+	}
+	return options, nil
+}
+
+func (r *Repository) fetchPollInvitees(query string, args ...interface{}) ([]dto.PollInviteeDto, error) {
+	rows, err := r.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invitees []dto.PollInviteeDto
+	for rows.Next() {
+		var invitee dto.PollInviteeDto
+		if err := rows.Scan(
+			&invitee.ID,
+			&invitee.PollId,
+			&invitee.EmailAddress,
+			&invitee.Name,
+			&invitee.TokenHash,
+			&invitee.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		invitees = append(invitees, invitee)
+	}
+	return invitees, nil
+}
+
+func (r *Repository) GetPollInviteesByPollId(pollId *uuid.UUID) ([]dto.PollInviteeDto, error) {
+	query := `
+				SELECT * FROM poll_invitees WHERE poll_id = $1 ORDER BY created_at, email_address
+			 `
+
+	return r.fetchPollInvitees(query, pollId)
+}
+
+func (r *Repository) GetPollInviteeByTokenHash(tokenHash string) (dto.PollInviteeDto, error) {
+	query := `
+				SELECT * FROM poll_invitees WHERE token_hash = $1
+			 `
+
+	invitees, err := r.fetchPollInvitees(query, tokenHash)
+	if err != nil {
+		return dto.PollInviteeDto{}, err
+	}
+
+	if len(invitees) == 0 {
+		return dto.PollInviteeDto{}, utility.ErrNotFound
+	}
+
+	return invitees[0], nil
+}
+
+func (r *Repository) GetPollVotesByPollId(pollId *uuid.UUID) ([]dto.PollVoteDto, error) {
+	query := `
+				SELECT * FROM poll_votes WHERE poll_id = $1
+			 `
+
+	rows, err := r.conn.Query(query, pollId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var votes []dto.PollVoteDto
+	for rows.Next() {
+		var vote dto.PollVoteDto
+		if err := rows.Scan(
+			&vote.ID,
+			&vote.PollId,
+			&vote.OptionId,
+			&vote.InviteeId,
+			&vote.Vote,
+			&vote.CreatedAt,
+			&vote.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		votes = append(votes, vote)
+	}
+	return votes, nil
+}
+
+// ReplacePollVotes swaps the invitee's votes for the given ones in a single transaction
+func (r *Repository) ReplacePollVotes(invitee *dto.PollInviteeDto, votes []dto.PollVoteDto) error {
+	tx, err := r.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM poll_votes WHERE invitee_id = $1`, invitee.ID); err != nil {
+		return err
+	}
+
+	query := `
+				INSERT INTO poll_votes (poll_id, option_id, invitee_id, vote, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				RETURNING id
+			 `
+
+	for i := range votes {
+		vote := &votes[i]
+		if err := tx.QueryRow(
+			query,
+			vote.PollId,
+			vote.OptionId,
+			vote.InviteeId,
+			vote.Vote,
+			vote.CreatedAt,
+			vote.UpdatedAt,
+		).Scan(&vote.ID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/scheduler-prototype/dto"
 	"github.com/scheduler-prototype/utility"
 )
@@ -25,6 +26,18 @@ func (r *Repository) fetchUsers(query string, args ...interface{}) ([]dto.UserDt
 			&user.UpdatedAt,
 			&user.SubscriptionId,
 			&user.SubscriptionExpiresAt,
+			&user.MailboxTimezone,
+			&user.WorkingHoursStart,
+			&user.WorkingHoursEnd,
+			pq.Array(&user.WorkingDays),
+			&user.WorkingHoursTimezone,
+			&user.SyncWindowPastDays,
+			&user.SyncWindowFutureDays,
+			&user.SyncWindowStart,
+			&user.SyncWindowEnd,
+			&user.PreviousSyncWindowStart,
+			&user.PreviousSyncWindowEnd,
+			&user.EmailAddress,
 		); err != nil {
 			return nil, err
 		}
@@ -36,8 +49,9 @@ func (r *Repository) fetchUsers(query string, args ...interface{}) ([]dto.UserDt
 func (r *Repository) CreateUser(user *dto.UserDto) error {
 	query := `
 				INSERT INTO users 
-					(user_id, current_delta, previous_delta, created_at, updated_at, subscription_id, subscription_expires_at)
-				VALUES ($1, $2, $3, $4, $5, $6, $7) 
+					(user_id, current_delta, previous_delta, created_at, updated_at, subscription_id, subscription_expires_at,
+					sync_window_past_days, sync_window_future_days)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) 
 				RETURNING id
 			 `
 
@@ -50,6 +64,8 @@ func (r *Repository) CreateUser(user *dto.UserDto) error {
 		user.UpdatedAt,
 		user.SubscriptionId,
 		user.SubscriptionExpiresAt,
+		user.SyncWindowPastDays,
+		user.SyncWindowFutureDays,
 	).Scan(&user.ID); err != nil {
 		return err
 	}
@@ -73,6 +89,48 @@ func (r *Repository) GetUserByUserId(userId *uuid.UUID) (dto.UserDto, error) {
 	return users[0], nil
 }
 
+// email addresses are matched case insensitively
+func (r *Repository) GetUserByEmailAddress(emailAddress string) (dto.UserDto, error) {
+	query := `
+						SELECT * FROM users WHERE LOWER(email_address) = LOWER($1)
+					`
+	users, err := r.fetchUsers(query, emailAddress)
+	if err != nil {
+		return dto.UserDto{}, err
+	}
+
+	if len(users) == 0 {
+		return dto.UserDto{}, utility.ErrNotFound
+	}
+
+	return users[0], nil
+}
+
+// change notifications only carry the id of the subscription they were sent for
+func (r *Repository) GetUserBySubscriptionId(subscriptionId string) (dto.UserDto, error) {
+	query := `
+						SELECT * FROM users WHERE subscription_id = $1
+					`
+	users, err := r.fetchUsers(query, subscriptionId)
+	if err != nil {
+		return dto.UserDto{}, err
+	}
+
+	if len(users) == 0 {
+		return dto.UserDto{}, utility.ErrNotFound
+	}
+
+	return users[0], nil
+}
+
+func (r *Repository) GetUsersWithCurrentDelta() ([]dto.UserDto, error) {
+	query := `
+						SELECT * FROM users WHERE current_delta IS NOT NULL
+					`
+
+	return r.fetchUsers(query)
+}
+
 func (r *Repository) UpdateCurrentDeltaByUser(userDto *dto.UserDto) error {
 	query := ` 
 						UPDATE users SET current_delta = $2 WHERE user_id = $1
@@ -121,3 +179,132 @@ func (r *Repository) UpdateSubscriptionInfoByUser(userDto *dto.UserDto) error {
 
 	return nil
 }
+
+func (r *Repository) UpdateMailboxSettingsByUser(userDto *dto.UserDto) error {
+	query := ` 
+						UPDATE users SET mailbox_timezone = $2, working_hours_start = $3, working_hours_end = $4,
+							working_days = $5, working_hours_timezone = $6, updated_at = CURRENT_TIMESTAMP
+						WHERE user_id = $1
+					`
+
+	if _, err := r.conn.Exec(
+		query,
+		userDto.UserId,
+		userDto.MailboxTimezone,
+		userDto.WorkingHoursStart,
+		userDto.WorkingHoursEnd,
+		pq.Array(userDto.WorkingDays),
+		userDto.WorkingHoursTimezone,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) UpdateEmailAddressByUser(userDto *dto.UserDto) error {
+	query := ` 
+						UPDATE users SET email_address = $2, updated_at = CURRENT_TIMESTAMP
+						WHERE user_id = $1
+					`
+
+	if _, err := r.conn.Exec(query, userDto.UserId, userDto.EmailAddress); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) UpdateSyncWindowSettingsByUser(userDto *dto.UserDto) error {
+	query := ` 
+						UPDATE users SET sync_window_past_days = $2, sync_window_future_days = $3, updated_at = CURRENT_TIMESTAMP
+						WHERE user_id = $1
+					`
+
+	if _, err := r.conn.Exec(
+		query,
+		userDto.UserId,
+		userDto.SyncWindowPastDays,
+		userDto.SyncWindowFutureDays,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) UpdateSyncWindowByUser(userDto *dto.UserDto) error {
+	query := ` 
+						UPDATE users SET sync_window_start = $2, sync_window_end = $3, updated_at = CURRENT_TIMESTAMP
+						WHERE user_id = $1
+					`
+
+	if _, err := r.conn.Exec(
+		query,
+		userDto.UserId,
+		userDto.SyncWindowStart,
+		userDto.SyncWindowEnd,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RotateDeltaByUser swaps in the delta link of a new window, keeping the current one as previous for rollback
+func (r *Repository) RotateDeltaByUser(userDto *dto.UserDto) error {
+	query := ` 
+						UPDATE users SET
+							previous_delta = current_delta,
+							previous_sync_window_start = sync_window_start,
+							previous_sync_window_end = sync_window_end,
+							current_delta = $2,
+							sync_window_start = $3,
+							sync_window_end = $4,
+							updated_at = CURRENT_TIMESTAMP
+						WHERE user_id = $1
+					`
+
+	if _, err := r.conn.Exec(
+		query,
+		userDto.UserId,
+		userDto.CurrentDelta,
+		userDto.SyncWindowStart,
+		userDto.SyncWindowEnd,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RollbackDeltaByUser restores the previous delta link and its window
+func (r *Repository) RollbackDeltaByUser(userDto *dto.UserDto) error {
+	query := ` 
+						UPDATE users SET
+							current_delta = previous_delta,
+							sync_window_start = previous_sync_window_start,
+							sync_window_end = previous_sync_window_end,
+							previous_delta = NULL,
+							previous_sync_window_start = NULL,
+							previous_sync_window_end = NULL,
+							updated_at = CURRENT_TIMESTAMP
+						WHERE user_id = $1 AND previous_delta IS NOT NULL
+					`
+
+	result, err := r.conn.Exec(query, userDto.UserId)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return utility.ErrNotFound
+	}
+
+	return nil
+}
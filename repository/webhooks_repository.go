@@ -0,0 +1,362 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/scheduler-prototype/dto"
+	"github.com/scheduler-prototype/utility"
+)
+
+func (r *Repository) fetchWebhooks(query string, args ...interface{}) ([]dto.WebhookDto, error) {
+	rows, err := r.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []dto.WebhookDto
+	for rows.Next() {
+		var webhook dto.WebhookDto
+		if err := rows.Scan(
+			&webhook.ID,
+			&webhook.Url,
+			&webhook.Secret,
+			&webhook.UserId,
+			pq.Array(&webhook.EventTypes),
+			pq.Array(&webhook.ChangeTypes),
+			&webhook.IsActive,
+			&webhook.CreatedAt,
+			&webhook.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, nil
+}
+
+func (r *Repository) CreateWebhook(webhook *dto.WebhookDto) error {
+	query := `
+				INSERT INTO webhooks
+					(url, secret, user_id, event_types, change_types, is_active, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+				RETURNING id
+			 `
+
+	if err := r.conn.QueryRow(
+		query,
+		webhook.Url,
+		webhook.Secret,
+		webhook.UserId,
+		pq.Array(webhook.EventTypes),
+		pq.Array(webhook.ChangeTypes),
+		webhook.IsActive,
+		webhook.CreatedAt,
+		webhook.UpdatedAt,
+	).Scan(&webhook.ID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) GetWebhookById(webhookId *uuid.UUID) (dto.WebhookDto, error) {
+	query := `
+				SELECT * FROM webhooks WHERE id = $1
+			 `
+
+	webhooks, err := r.fetchWebhooks(query, webhookId)
+	if err != nil {
+		return dto.WebhookDto{}, err
+	}
+
+	if len(webhooks) == 0 {
+		return dto.WebhookDto{}, utility.ErrNotFound
+	}
+
+	return webhooks[0], nil
+}
+
+// GetActiveWebhooks returns the registered webhooks that weren't deleted, oldest first
+func (r *Repository) GetActiveWebhooks() ([]dto.WebhookDto, error) {
+	query := `
+				SELECT * FROM webhooks WHERE is_active ORDER BY created_at
+			 `
+
+	return r.fetchWebhooks(query)
+}
+
+// DeactivateWebhook stops the deliveries of the webhook, its history stays, ErrNotFound when it isn't active
+func (r *Repository) DeactivateWebhook(webhookId *uuid.UUID) error {
+	query := `
+				UPDATE webhooks SET is_active = FALSE, updated_at = CURRENT_TIMESTAMP
+				WHERE id = $1 AND is_active
+			 `
+
+	result, err := r.conn.Exec(query, webhookId)
+	if err != nil {
+		return err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if updated == 0 {
+		return utility.ErrNotFound
+	}
+
+	return nil
+}
+
+// createWebhookDeliveries queues a delivery of the change for every active webhook whose filters match it
+func createWebhookDeliveries(tx *sql.Tx, changeId int64, revision *dto.EventRevisionDto) error {
+	query := `
+				INSERT INTO webhook_deliveries (webhook_id, change_id, status, next_attempt_at, created_at)
+				SELECT id, $1, $2, $3, $3 FROM webhooks
+				WHERE is_active
+					AND (user_id IS NULL OR user_id = $4)
+					AND (cardinality(event_types) = 0 OR $5 = ANY(event_types))
+					AND (cardinality(change_types) = 0 OR $6 = ANY(change_types))
+			 `
+
+	_, err := tx.Exec(
+		query,
+		changeId,
+		dto.WebhookDeliveryPending,
+		revision.RecordedAt,
+		revision.UserId,
+		revision.EventType,
+		revision.RevisionType,
+	)
+	return err
+}
+
+// ClaimWebhookDeliveries returns up to limit pending deliveries that are due and pushes their next attempt
+// back by lease, so another worker doesn't pick them up while they are sent
+// -- a delivery whose worker stopped mid-send is attempted again once the lease ran out
+func (r *Repository) ClaimWebhookDeliveries(now time.Time, lease time.Duration, limit int) ([]dto.WebhookDeliveryDto, error) {
+	query := `
+				UPDATE webhook_deliveries SET next_attempt_at = $2
+				WHERE id IN (
+					SELECT d.id FROM webhook_deliveries d
+					JOIN webhooks w ON w.id = d.webhook_id
+					WHERE d.status = $3 AND d.next_attempt_at <= $1 AND w.is_active
+					ORDER BY d.next_attempt_at
+					LIMIT $4
+					FOR UPDATE OF d SKIP LOCKED
+				)
+				RETURNING *
+			 `
+
+	rows, err := r.conn.Query(query, now, now.Add(lease), dto.WebhookDeliveryPending, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []dto.WebhookDeliveryDto
+	for rows.Next() {
+		var delivery dto.WebhookDeliveryDto
+		if err := rows.Scan(
+			&delivery.ID,
+			&delivery.WebhookId,
+			&delivery.ChangeId,
+			&delivery.Status,
+			&delivery.Attempts,
+			&delivery.NextAttemptAt,
+			&delivery.LastError,
+			&delivery.DeliveredAt,
+			&delivery.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+// UpdateWebhookDelivery stores the outcome of an attempt, the delivery has to be pending still
+func (r *Repository) UpdateWebhookDelivery(delivery *dto.WebhookDeliveryDto) error {
+	query := `
+				UPDATE webhook_deliveries
+				SET status = $2, attempts = $3, next_attempt_at = $4, last_error = $5, delivered_at = $6
+				WHERE id = $1 AND status = $7
+			 `
+
+	result, err := r.conn.Exec(
+		query,
+		delivery.ID,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.NextAttemptAt,
+		delivery.LastError,
+		delivery.DeliveredAt,
+		dto.WebhookDeliveryPending,
+	)
+	if err != nil {
+		return err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if updated == 0 {
+		return utility.ErrConflict
+	}
+
+	return nil
+}
+
+// DeadLetterWebhookDelivery gives up on the delivery and moves it to the dead letters in the same transaction
+func (r *Repository) DeadLetterWebhookDelivery(delivery *dto.WebhookDeliveryDto, deadLetter *dto.WebhookDeadLetterDto) error {
+	tx, err := r.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+				UPDATE webhook_deliveries SET status = $2, attempts = $3, last_error = $4
+				WHERE id = $1 AND status = $5
+			 `
+
+	result, err := tx.Exec(query, delivery.ID, dto.WebhookDeliveryDead, delivery.Attempts, delivery.LastError, dto.WebhookDeliveryPending)
+	if err != nil {
+		return err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if updated == 0 {
+		return utility.ErrConflict
+	}
+
+	query = `
+				INSERT INTO webhook_dead_letters
+					(delivery_id, webhook_id, change_id, attempts, last_error, failed_at)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				RETURNING id
+			 `
+
+	if err := tx.QueryRow(
+		query,
+		deadLetter.DeliveryId,
+		deadLetter.WebhookId,
+		deadLetter.ChangeId,
+		deadLetter.Attempts,
+		deadLetter.LastError,
+		deadLetter.FailedAt,
+	).Scan(&deadLetter.ID); err != nil {
+		return err
+	}
+
+	delivery.Status = dto.WebhookDeliveryDead
+	return tx.Commit()
+}
+
+// GetWebhookDeadLettersByWebhookId returns the dead letters of the webhook, latest first
+func (r *Repository) GetWebhookDeadLettersByWebhookId(webhookId *uuid.UUID) ([]dto.WebhookDeadLetterDto, error) {
+	query := `
+				SELECT * FROM webhook_dead_letters WHERE webhook_id = $1 ORDER BY failed_at DESC
+			 `
+
+	rows, err := r.conn.Query(query, webhookId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deadLetters []dto.WebhookDeadLetterDto
+	for rows.Next() {
+		var deadLetter dto.WebhookDeadLetterDto
+		if err := rows.Scan(
+			&deadLetter.ID,
+			&deadLetter.DeliveryId,
+			&deadLetter.WebhookId,
+			&deadLetter.ChangeId,
+			&deadLetter.Attempts,
+			&deadLetter.LastError,
+			&deadLetter.FailedAt,
+			&deadLetter.ReplayedAt,
+		); err != nil {
+			return nil, err
+		}
+		deadLetters = append(deadLetters, deadLetter)
+	}
+	return deadLetters, nil
+}
+
+// ReplayWebhookDeadLetter queues a new delivery of the dead letter's change and marks it replayed,
+// ErrNotFound when the webhook has no such dead letter and ErrConflict when it was replayed already
+func (r *Repository) ReplayWebhookDeadLetter(webhookId *uuid.UUID, deadLetterId *uuid.UUID, now time.Time) (dto.WebhookDeliveryDto, error) {
+	tx, err := r.conn.Begin()
+	if err != nil {
+		return dto.WebhookDeliveryDto{}, err
+	}
+	defer tx.Rollback()
+
+	query := `
+				SELECT change_id, replayed_at FROM webhook_dead_letters
+				WHERE id = $1 AND webhook_id = $2
+				FOR UPDATE
+			 `
+
+	delivery := dto.WebhookDeliveryDto{
+		WebhookId:     *webhookId,
+		Status:        dto.WebhookDeliveryPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+	var replayedAt *time.Time
+	if err := tx.QueryRow(query, deadLetterId, webhookId).Scan(&delivery.ChangeId, &replayedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return dto.WebhookDeliveryDto{}, utility.ErrNotFound
+		}
+		return dto.WebhookDeliveryDto{}, err
+	}
+
+	if replayedAt != nil {
+		return dto.WebhookDeliveryDto{}, utility.ErrConflict
+	}
+
+	query = `
+				INSERT INTO webhook_deliveries (webhook_id, change_id, status, next_attempt_at, created_at)
+				VALUES ($1, $2, $3, $4, $5)
+				RETURNING id
+			 `
+
+	if err := tx.QueryRow(
+		query,
+		delivery.WebhookId,
+		delivery.ChangeId,
+		delivery.Status,
+		delivery.NextAttemptAt,
+		delivery.CreatedAt,
+	).Scan(&delivery.ID); err != nil {
+		return dto.WebhookDeliveryDto{}, err
+	}
+
+	query = `
+				UPDATE webhook_dead_letters SET replayed_at = $2 WHERE id = $1
+			 `
+
+	if _, err := tx.Exec(query, deadLetterId, now); err != nil {
+		return dto.WebhookDeliveryDto{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return dto.WebhookDeliveryDto{}, err
+	}
+
+	return delivery, nil
+}
@@ -0,0 +1,63 @@
+package timezone
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	// embed the IANA database so zones resolve regardless of the host image
+	_ "time/tzdata"
+)
+
+var ErrUnknownTimeZone = errors.New("unknown time zone")
+
+// Graph sends dateTime without an offset, with up to 7 fractional digits
+const graphDateTimeLayout = "2006-01-02T15:04:05.9999999"
+
+// ToIANA returns the IANA name for a Windows or IANA time zone name
+func ToIANA(name string) (string, error) {
+	name = strings.TrimSpace(name)
+
+	if iana, ok := windowsZones[name]; ok {
+		return iana, nil
+	}
+
+	// already an IANA name
+	if name != "" && name != "Local" {
+		if _, err := time.LoadLocation(name); err == nil {
+			return name, nil
+		}
+	}
+
+	return "", ErrUnknownTimeZone
+}
+
+// LoadLocation loads the location for a Windows or IANA time zone name
+func LoadLocation(name string) (*time.Location, error) {
+	iana, err := ToIANA(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return time.LoadLocation(iana)
+}
+
+// ParseDateTimeTimeZone converts a Graph dateTimeTimeZone pair into a UTC instant
+func ParseDateTimeTimeZone(dateTime string, timeZone string) (time.Time, error) {
+	location, err := LoadLocation(timeZone)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// tolerate dateTime values that already carry an offset
+	if parsed, err := time.Parse(time.RFC3339Nano, dateTime); err == nil {
+		return parsed.UTC(), nil
+	}
+
+	parsed, err := time.ParseInLocation(graphDateTimeLayout, dateTime, location)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return parsed.UTC(), nil
+}
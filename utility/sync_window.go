@@ -0,0 +1,31 @@
+package utility
+
+import (
+	"time"
+
+	"github.com/scheduler-prototype/timezone"
+)
+
+const (
+	DefaultSyncWindowPastDays   = 30
+	DefaultSyncWindowFutureDays = 180
+)
+
+// SyncWindow returns the range to sync for a user, aligned to midnight in the user's time zone
+// -- falls back to UTC when the user's zone is unknown
+func SyncWindow(timeZone *string, pastDays int, futureDays int, now time.Time) (time.Time, time.Time) {
+	location := time.UTC
+	if timeZone != nil {
+		if userLocation, err := timezone.LoadLocation(*timeZone); err == nil {
+			location = userLocation
+		}
+	}
+
+	userNow := now.In(location)
+	startOfToday := time.Date(userNow.Year(), userNow.Month(), userNow.Day(), 0, 0, 0, 0, location)
+
+	start := startOfToday.AddDate(0, 0, -pastDays)
+	end := startOfToday.AddDate(0, 0, futureDays+1)
+
+	return start.UTC(), end.UTC()
+}
@@ -0,0 +1,24 @@
+package utility
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// NewToken returns a random url safe token along with the hash to store in its place
+func NewToken() (string, string, error) {
+	buffer := make([]byte, 32)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", "", err
+	}
+
+	token := hex.EncodeToString(buffer)
+	return token, HashToken(token), nil
+}
+
+// HashToken returns the hash tokens are looked up by
+func HashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}